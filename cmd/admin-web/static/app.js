@@ -6,6 +6,7 @@ const API_URL = window.location.hostname === 'localhost'
 let authToken = localStorage.getItem('authToken');
 let stages = [];
 let phrases = [];
+let themes = [];
 
 // Utility Functions
 function showMessage(message, isError = false) {
@@ -37,6 +38,8 @@ function showTab(tabName) {
     } else if (tabName === 'phrases') {
         loadStagesForDropdown();
         loadPhrases();
+    } else if (tabName === 'themes') {
+        loadThemeList();
     }
 }
 
@@ -120,6 +123,10 @@ async function apiRequest(url, options = {}) {
     const data = await response.json();
 
     if (!response.ok) {
+        // Validation error: tampilkan pesan per field
+        if (data.fields && data.fields.length) {
+            throw new Error(data.fields.map(f => `${f.field} ${f.message}`).join('; '));
+        }
         throw new Error(data.error || 'Request failed');
     }
 
@@ -130,7 +137,7 @@ async function apiRequest(url, options = {}) {
 async function loadStages() {
     try {
         stages = await apiRequest('/admin/stages');
-        const themes = await apiRequest('/admin/themes');
+        themes = await apiRequest('/admin/themes') || [];
         
         // Map theme names to stages
         stages = stages.map(stage => {
@@ -160,9 +167,15 @@ function renderStages() {
             <td>${stage.name}</td>
             <td>${stage.theme_name || stage.theme_id}</td>
             <td><span class="badge badge-${stage.difficulty}">${stage.difficulty}</span></td>
-            <td><span class="badge ${stage.is_active ? 'badge-success' : 'badge-danger'}">${stage.is_active ? 'Active' : 'Inactive'}</span></td>
+            <td>
+                <span class="badge ${stage.is_active ? 'badge-success' : 'badge-danger'}">${stage.is_active ? 'Active' : 'Inactive'}</span>
+                ${stage.version_id ? '' : '<span class="badge badge-danger">Unpublished</span>'}
+                ${formatSchedule(stage)}
+            </td>
             <td class="action-buttons">
                 <button class="btn btn-small" onclick="editStage('${stage.id}')">Edit</button>
+                <button class="btn btn-small" onclick="publishStage('${stage.id}')">Publish</button>
+                <button class="btn btn-small" onclick="cloneStage('${stage.id}')">Clone</button>
                 <button class="btn btn-small btn-danger" onclick="deleteStage('${stage.id}')">Delete</button>
             </td>
         </tr>
@@ -179,6 +192,12 @@ document.getElementById('stageForm').addEventListener('submit', async (e) => {
         theme_id: document.getElementById('stageTheme').value,
         difficulty: document.getElementById('stageDifficulty').value,
         is_active: document.getElementById('stageIsActive').checked,
+        available_from: fromLocalInput(document.getElementById('stageAvailableFrom').value),
+        available_until: fromLocalInput(document.getElementById('stageAvailableUntil').value),
+        phrase_charset: document.getElementById('stagePhraseCharset').value,
+        phrase_pool_size: parseInt(document.getElementById('stagePhrasePoolSize').value) || 0,
+        whitespace_policy: document.getElementById('stageWhitespacePolicy').value,
+        phrase_language: document.getElementById('stagePhraseLanguage').value.trim(),
     };
 
     try {
@@ -209,6 +228,35 @@ document.getElementById('stageForm').addEventListener('submit', async (e) => {
     }
 });
 
+// datetime-local <-> RFC3339 (input memakai zona waktu browser)
+function fromLocalInput(value) {
+    return value ? new Date(value).toISOString() : null;
+}
+
+function toLocalInput(iso) {
+    if (!iso) {
+        return '';
+    }
+    const date = new Date(iso);
+    return new Date(date.getTime() - date.getTimezoneOffset() * 60000).toISOString().slice(0, 16);
+}
+
+function formatSchedule(stage) {
+    if (!stage.available_from && !stage.available_until) {
+        return '';
+    }
+    const from = stage.available_from ? new Date(stage.available_from).toLocaleString() : '…';
+    const until = stage.available_until ? new Date(stage.available_until).toLocaleString() : '…';
+    return `<br><small>${from} – ${until}</small>`;
+}
+
+// Phrase berisi kode (mis. List<String>) sehingga harus di-escape
+function escapeHtml(text) {
+    const div = document.createElement('div');
+    div.textContent = text;
+    return div.innerHTML;
+}
+
 function cancelEdit() {
     editingStageId = null;
     document.getElementById('stageForm').reset();
@@ -229,6 +277,12 @@ function editStage(stageId) {
     document.getElementById('stageTheme').value = stage.theme_id;
     document.getElementById('stageDifficulty').value = stage.difficulty;
     document.getElementById('stageIsActive').checked = stage.is_active;
+    document.getElementById('stageAvailableFrom').value = toLocalInput(stage.available_from);
+    document.getElementById('stageAvailableUntil').value = toLocalInput(stage.available_until);
+    document.getElementById('stagePhraseCharset').value = stage.phrase_charset || 'qwerty';
+    document.getElementById('stagePhrasePoolSize').value = stage.phrase_pool_size || 0;
+    document.getElementById('stageWhitespacePolicy').value = stage.whitespace_policy || 'strict';
+    document.getElementById('stagePhraseLanguage').value = stage.phrase_language || '';
 
     // Update form UI
     editingStageId = stageId;
@@ -257,6 +311,51 @@ async function deleteStage(stageId) {
     }
 }
 
+// Publish draft stage (nama + phrase) sebagai versi baru untuk pemain
+async function publishStage(stageId) {
+    if (!confirm('Publish the current draft of this stage to players?')) {
+        return;
+    }
+    const resetLeaderboard = confirm('Also reset the leaderboard of this stage?\n\nOK = start a fresh leaderboard, Cancel = keep existing scores ranked.');
+
+    try {
+        const version = await apiRequest(`/admin/stage/${stageId}/publish`, {
+            method: 'POST',
+            body: JSON.stringify({ reset_leaderboard: resetLeaderboard }),
+        });
+
+        showMessage(`Published version ${version.version}` + (version.leaderboard_reset ? ' (leaderboard reset)' : ''));
+        loadStages();
+    } catch (error) {
+        showMessage('Error publishing stage: ' + error.message, true);
+    }
+}
+
+async function cloneStage(stageId) {
+    const stage = stages.find(s => s.id === stageId);
+    const name = prompt('Name of the copy:', stage ? `${stage.name} (copy)` : '');
+    if (name === null) {
+        return;
+    }
+    const scale = prompt('Multiply every phrase multiplier by:', '1');
+    if (scale === null) {
+        return;
+    }
+
+    try {
+        const clone = await apiRequest(`/admin/stage/${stageId}/clone`, {
+            method: 'POST',
+            body: JSON.stringify({ name: name.trim(), multiplier_scale: parseFloat(scale) || 0 }),
+        });
+
+        showMessage(`Cloned as "${clone.name}" (inactive draft)`);
+        loadStages();
+        loadStagesForDropdown();
+    } catch (error) {
+        showMessage('Error cloning stage: ' + error.message, true);
+    }
+}
+
 // Phrases Management
 async function loadStagesForDropdown() {
     try {
@@ -322,17 +421,24 @@ function renderPhrases() {
         return;
     }
 
-    tbody.innerHTML = phrases.map(phrase => {
+    // Urutan hanya bisa diubah saat melihat satu stage
+    const canReorder = !!document.getElementById('filterStageId')?.value;
+
+    tbody.innerHTML = phrases.map((phrase, index) => {
         const stage = stages.find(s => s.id === phrase.stage_id);
         const stageName = stage ? stage.name : (phrase.stageName || 'Unknown');
         
         return `
             <tr>
                 <td>${stageName}</td>
-                <td>${phrase.text}</td>
+                <td class="phrase-text">${escapeHtml(phrase.text)}</td>
                 <td>${phrase.sequence_number}</td>
                 <td>${phrase.multiplier || phrase.base_multiplier}</td>
                 <td class="action-buttons">
+                    ${canReorder ? `
+                    <button class="btn btn-small" onclick="movePhrase(${index}, -1)" ${index === 0 ? 'disabled' : ''}>&uarr;</button>
+                    <button class="btn btn-small" onclick="movePhrase(${index}, 1)" ${index === phrases.length - 1 ? 'disabled' : ''}>&darr;</button>
+                    ` : ''}
                     <button class="btn btn-small" onclick="editPhrase('${phrase.id}')">Edit</button>
                     <button class="btn btn-small btn-danger" onclick="deletePhrase('${phrase.id}')">Delete</button>
                 </td>
@@ -341,6 +447,29 @@ function renderPhrases() {
     }).join('');
 }
 
+async function movePhrase(index, direction) {
+    const stageId = document.getElementById('filterStageId').value;
+    const target = index + direction;
+    if (!stageId || target < 0 || target >= phrases.length) {
+        return;
+    }
+
+    const phraseIds = phrases.map(p => p.id);
+    [phraseIds[index], phraseIds[target]] = [phraseIds[target], phraseIds[index]];
+
+    try {
+        const result = await apiRequest(`/admin/stage/${stageId}/phrases/order`, {
+            method: 'PUT',
+            body: JSON.stringify({ phrase_ids: phraseIds }),
+        });
+        phrases = result || [];
+        renderPhrases();
+    } catch (error) {
+        showMessage('Error reordering phrases: ' + error.message, true);
+        loadPhrases();
+    }
+}
+
 let editingPhraseId = null;
 
 document.getElementById('phraseForm').addEventListener('submit', async (e) => {
@@ -352,6 +481,14 @@ document.getElementById('phraseForm').addEventListener('submit', async (e) => {
         sequence_number: parseInt(document.getElementById('phraseSequence').value),
         base_multiplier: parseFloat(document.getElementById('phraseMultiplier').value),
     };
+    // Kosong saat create = multiplier dari analyzer server
+    if (isNaN(phraseData.base_multiplier)) {
+        if (editingPhraseId) {
+            showMessage('Base multiplier is required when updating a phrase', true);
+            return;
+        }
+        delete phraseData.base_multiplier;
+    }
 
     try {
         if (editingPhraseId) {
@@ -381,6 +518,31 @@ document.getElementById('phraseForm').addEventListener('submit', async (e) => {
     }
 });
 
+async function suggestMultiplier() {
+    const text = document.getElementById('phraseText').value;
+    if (!text.trim()) {
+        showMessage('Enter the phrase text first', true);
+        return;
+    }
+
+    // Karakter yang diketik bergantung pada whitespace policy stage
+    const stage = stages.find(s => s.id === document.getElementById('phraseStageId').value);
+    const whitespacePolicy = stage ? stage.whitespace_policy : '';
+
+    try {
+        const analysis = await apiRequest('/admin/phrases/analyze', {
+            method: 'POST',
+            body: JSON.stringify({ texts: [text], whitespace_policy: whitespacePolicy }),
+        });
+        const phrase = analysis.phrases[0];
+        document.getElementById('phraseMultiplier').value = phrase.suggested_multiplier;
+        document.getElementById('phraseAnalysis').textContent =
+            `complexity ${phrase.score.toFixed(2)} (${analysis.suggested_difficulty})`;
+    } catch (error) {
+        showMessage('Error analyzing phrase: ' + error.message, true);
+    }
+}
+
 function editPhrase(phraseId) {
     const phrase = phrases.find(p => p.id === phraseId);
     if (!phrase) {
@@ -454,7 +616,7 @@ if (authToken) {
 // Load themes
 async function loadThemes() {
     try {
-        const themes = await apiRequest('/admin/themes');
+        themes = await apiRequest('/admin/themes') || [];
         const themeSelect = document.getElementById('stageTheme');
         
         themeSelect.innerHTML = '<option value="">Select a theme</option>' + 
@@ -464,3 +626,125 @@ async function loadThemes() {
     }
 }
 
+// Themes Management
+async function loadThemeList() {
+    try {
+        await loadThemes();
+        renderThemes();
+    } catch (error) {
+        showMessage('Error loading themes: ' + error.message, true);
+    }
+}
+
+function renderThemes() {
+    const tbody = document.getElementById('themesTableBody');
+
+    if (themes.length === 0) {
+        tbody.innerHTML = '<tr><td colspan="6">No themes found</td></tr>';
+        return;
+    }
+
+    tbody.innerHTML = themes.map(theme => `
+        <tr>
+            <td>${theme.sort_order}</td>
+            <td>${theme.name}</td>
+            <td>${theme.color ? `<span class="color-swatch" style="background: ${theme.color}"></span>${theme.color}` : '-'}</td>
+            <td>${theme.icon_key || '-'}</td>
+            <td>${theme.description || ''}</td>
+            <td class="action-buttons">
+                <button class="btn btn-small" onclick="editTheme('${theme.id}')">Edit</button>
+                <button class="btn btn-small btn-danger" onclick="deleteTheme('${theme.id}')">Delete</button>
+            </td>
+        </tr>
+    `).join('');
+}
+
+let editingThemeId = null;
+
+document.getElementById('themeForm').addEventListener('submit', async (e) => {
+    e.preventDefault();
+
+    const themeData = {
+        name: document.getElementById('themeName').value,
+        description: document.getElementById('themeDescription').value,
+        color: document.getElementById('themeHasColor').checked ? document.getElementById('themeColor').value : '',
+        icon_key: document.getElementById('themeIconKey').value,
+        sort_order: parseInt(document.getElementById('themeSortOrder').value) || 0,
+    };
+
+    try {
+        if (editingThemeId) {
+            // Update existing theme
+            await apiRequest(`/admin/themes/${editingThemeId}`, {
+                method: 'PUT',
+                body: JSON.stringify(themeData),
+            });
+            showMessage('Theme updated successfully!');
+            cancelThemeEdit();
+        } else {
+            // Create new theme
+            await apiRequest('/admin/themes', {
+                method: 'POST',
+                body: JSON.stringify(themeData),
+            });
+            showMessage('Theme created successfully!');
+            document.getElementById('themeForm').reset();
+        }
+
+        loadThemeList();
+    } catch (error) {
+        showMessage('Error saving theme: ' + error.message, true);
+    }
+});
+
+function editTheme(themeId) {
+    const theme = themes.find(t => t.id === themeId);
+    if (!theme) {
+        showMessage('Theme not found', true);
+        return;
+    }
+
+    // Populate form with theme data
+    document.getElementById('themeName').value = theme.name;
+    document.getElementById('themeDescription').value = theme.description || '';
+    document.getElementById('themeHasColor').checked = !!theme.color;
+    document.getElementById('themeColor').value = (theme.color || '#667eea').toLowerCase();
+    document.getElementById('themeIconKey').value = theme.icon_key || '';
+    document.getElementById('themeSortOrder').value = theme.sort_order;
+
+    // Update form UI
+    editingThemeId = themeId;
+    document.getElementById('themeFormTitle').textContent = 'Edit Theme';
+    document.querySelector('#themeForm button[type="submit"]').textContent = 'Update Theme';
+    document.getElementById('cancelThemeEditBtn').style.display = 'inline-block';
+
+    // Scroll to form
+    document.getElementById('themeForm').scrollIntoView({ behavior: 'smooth' });
+}
+
+function cancelThemeEdit() {
+    editingThemeId = null;
+    document.getElementById('themeForm').reset();
+    document.getElementById('themeFormTitle').textContent = 'Create New Theme';
+    document.querySelector('#themeForm button[type="submit"]').textContent = 'Create Theme';
+    document.getElementById('cancelThemeEditBtn').style.display = 'none';
+}
+
+async function deleteTheme(themeId) {
+    if (!confirm('Are you sure you want to delete this theme?')) {
+        return;
+    }
+
+    try {
+        await apiRequest(`/admin/themes/${themeId}`, {
+            method: 'DELETE',
+        });
+
+        showMessage('Theme deleted successfully!');
+        loadThemeList();
+    } catch (error) {
+        // 409: theme masih dipakai stage
+        showMessage('Error deleting theme: ' + error.message, true);
+    }
+}
+
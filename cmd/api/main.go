@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"log"
+	"math"
 	"os"
+	"strconv"
+	"time"
+	_ "time/tzdata" // alpine image ships without zoneinfo
 
 	"uwika_quick_typer_game/internal/application/services"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+	"uwika_quick_typer_game/internal/infrastructure/cache"
 	"uwika_quick_typer_game/internal/infrastructure/database"
 	"uwika_quick_typer_game/internal/infrastructure/http/router"
 	"uwika_quick_typer_game/internal/infrastructure/persistence/postgres"
@@ -30,21 +38,138 @@ func main() {
 
 	log.Println("Database connected successfully")
 
+	// Leaderboard windows (daily/weekly/monthly) follow the class timezone
+	leaderboardLocation, err := time.LoadLocation(getEnv("LEADERBOARD_TIMEZONE", "Asia/Jakarta"))
+	if err != nil {
+		log.Fatalf("Invalid LEADERBOARD_TIMEZONE: %v", err)
+	}
+
+	// Attempt yang dimulai sebelum stage terjadwal tutup masih boleh disubmit
+	// selama grace period ini
+	stageCloseGracePeriod, err := time.ParseDuration(getEnv("STAGE_CLOSE_GRACE_PERIOD", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid STAGE_CLOSE_GRACE_PERIOD: %v", err)
+	}
+
+	// Sesi permainan (POST /api/stage/:id/session) harus disubmit sebelum
+	// umur ini
+	gameSessionTTL, err := time.ParseDuration(getEnv("GAME_SESSION_TTL", "30m"))
+	if err != nil || gameSessionTTL <= 0 {
+		log.Fatalf("Invalid GAME_SESSION_TTL: %q", getEnv("GAME_SESSION_TTL", "30m"))
+	}
+
+	// Nama/deskripsi asli stage dan theme ditulis dalam locale ini; locale
+	// lain diambil dari tabel terjemahan
+	defaultLocale := domainservices.NormalizeLocale(getEnv("DEFAULT_LOCALE", "id"))
+	if defaultLocale == "" {
+		log.Fatalf("Invalid DEFAULT_LOCALE: %q", os.Getenv("DEFAULT_LOCALE"))
+	}
+
+	// Batas phrase yang diperiksa linter saat create, update dan import
+	phraseLintConfig := domainservices.DefaultPhraseLintConfig
+	phraseLintConfig.MinLength = getEnvInt("PHRASE_MIN_LENGTH", phraseLintConfig.MinLength)
+	phraseLintConfig.MaxLength = getEnvInt("PHRASE_MAX_LENGTH", phraseLintConfig.MaxLength)
+	phraseLintConfig.MaxLines = getEnvInt("PHRASE_MAX_LINES", phraseLintConfig.MaxLines)
+	phraseLintConfig.MinMultiplier = getEnvFloat("PHRASE_MIN_MULTIPLIER", phraseLintConfig.MinMultiplier)
+	phraseLintConfig.MaxMultiplier = getEnvFloat("PHRASE_MAX_MULTIPLIER", phraseLintConfig.MaxMultiplier)
+	if phraseLintConfig.MinLength < 1 || phraseLintConfig.MinLength > phraseLintConfig.MaxLength {
+		log.Fatalf("Invalid phrase length bounds: %d..%d", phraseLintConfig.MinLength, phraseLintConfig.MaxLength)
+	}
+	if phraseLintConfig.MaxLines < 1 {
+		log.Fatalf("Invalid phrase line limit: %d", phraseLintConfig.MaxLines)
+	}
+	if phraseLintConfig.MinMultiplier <= 0 || phraseLintConfig.MinMultiplier > phraseLintConfig.MaxMultiplier {
+		log.Fatalf("Invalid phrase multiplier bounds: %g..%g", phraseLintConfig.MinMultiplier, phraseLintConfig.MaxMultiplier)
+	}
+	phraseLinter := domainservices.NewPhraseLinter(phraseLintConfig)
+
+	// Saran multiplier dari analyzer harus selalu lolos linter
+	phraseAnalyzerConfig := domainservices.DefaultPhraseAnalyzerConfig
+	phraseAnalyzerConfig.MinMultiplier = math.Max(phraseAnalyzerConfig.MinMultiplier, phraseLintConfig.MinMultiplier)
+	phraseAnalyzerConfig.MaxMultiplier = math.Min(phraseAnalyzerConfig.MaxMultiplier, phraseLintConfig.MaxMultiplier)
+	phraseAnalyzer := domainservices.NewPhraseAnalyzer(domainservices.QWERTY, phraseAnalyzerConfig)
+
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
 	tokenRepo := postgres.NewTokenRepository(db)
 	themeRepo := postgres.NewThemeRepository(db)
 	stageRepo := postgres.NewStageRepository(db)
 	phraseRepo := postgres.NewPhraseRepository(db)
-	scoreRepo := postgres.NewScoreRepository(db)
+	stageVersionRepo := postgres.NewStageVersionRepository(db)
+	gameSessionRepo := postgres.NewGameSessionRepository(db)
+	seasonRepo := postgres.NewSeasonRepository(db)
+	keyStatsRepo := postgres.NewKeyStatsRepository(db)
+	prerequisiteRepo := postgres.NewStagePrerequisiteRepository(db)
+	achievementRepo := postgres.NewAchievementRepository(db)
+	dailyChallengeRepo := postgres.NewDailyChallengeRepository(db)
+	progressRepo := postgres.NewUserProgressRepository(db)
+	translationRepo := postgres.NewTranslationRepository(db)
+	transactor := postgres.NewTransactor(db)
+	// Top-N leaderboard per stage di-cache di memory (+1 untuk deteksi next page)
+	scoreRepo := cache.NewLeaderboardCache(postgres.NewScoreRepository(db), services.MaxLeaderboardLimit+1, 30*time.Second)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo, tokenRepo)
-	gameService := services.NewGameService(stageRepo, phraseRepo, scoreRepo)
-	adminService := services.NewAdminService(stageRepo, phraseRepo, userRepo, themeRepo)
+	progressService := services.NewProgressService(progressRepo, userRepo, leaderboardLocation)
+	achievementService := services.NewAchievementService(achievementRepo, scoreRepo, progressService)
+	gameService := services.NewGameService(stageRepo, stageVersionRepo, gameSessionRepo, scoreRepo, keyStatsRepo, prerequisiteRepo, transactor, progressService, achievementService, stageCloseGracePeriod, gameSessionTTL)
+	leaderboardService := services.NewLeaderboardService(scoreRepo, stageRepo, themeRepo, seasonRepo, leaderboardLocation)
+	leaderboardStream := services.NewLeaderboardStream(scoreRepo, seasonRepo)
+	dailyChallengeService := services.NewDailyChallengeService(dailyChallengeRepo, phraseRepo, transactor, leaderboardLocation, gameSessionTTL)
+	seasonService := services.NewSeasonService(seasonRepo)
+	// Statistik pemain di-cache per user sampai ada attempt baru
+	playerService := services.NewPlayerService(userRepo, cache.NewPlayerStatsCache(scoreRepo, 1000), keyStatsRepo, progressService, leaderboardLocation)
+	adminService := services.NewAdminService(stageRepo, phraseRepo, userRepo, themeRepo, prerequisiteRepo, stageVersionRepo, transactor, phraseLinter, phraseAnalyzer)
+	stageBundleService := services.NewStageBundleService(themeRepo, stageRepo, phraseRepo, transactor, phraseLinter, phraseAnalyzer)
+	translationService := services.NewTranslationService(translationRepo, stageRepo, stageVersionRepo, themeRepo, defaultLocale)
+
+	// Closing job: tutup season yang ends_at-nya sudah lewat
+	go seasonService.RunClosingJob(context.Background(), time.Minute)
+
+	// Perubahan best score dari semua instance API datang lewat LISTEN/NOTIFY:
+	// invalidate cache top-N lokal lalu kirim ke subscriber SSE
+	err = database.Listen(context.Background(), dbConfig, postgres.LeaderboardChannel,
+		func(payload string) {
+			var notification postgres.LeaderboardNotification
+			if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+				log.Printf("Invalid leaderboard notification %q: %v", payload, err)
+				return
+			}
+			if notification.StageID == "" {
+				// Season berganti: board default semua stage ikut berganti
+				scoreRepo.InvalidateAll()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				leaderboardStream.Resync(ctx)
+				return
+			}
+			scoreRepo.Invalidate(notification.StageID)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			publish := leaderboardStream.Publish
+			if notification.ScoreID == 0 {
+				publish = leaderboardStream.Reset
+			}
+			if err := publish(ctx, notification.StageID); err != nil {
+				log.Printf("Failed to publish leaderboard update: %v", err)
+			}
+		},
+		func() {
+			scoreRepo.InvalidateAll()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			leaderboardStream.Resync(ctx)
+		},
+	)
+	if err != nil {
+		log.Fatalf("Failed to listen for leaderboard updates: %v", err)
+	}
 
 	// Setup router
-	r := router.SetupRouter(authService, gameService, adminService, userRepo)
+	r := router.SetupRouter(authService, gameService, leaderboardService, leaderboardStream, seasonService, playerService, achievementService, dailyChallengeService, stageBundleService, translationService, adminService)
 
 	// Start server
 	port := getEnv("PORT", "8080")
@@ -62,3 +187,26 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return parsed
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return parsed
+}
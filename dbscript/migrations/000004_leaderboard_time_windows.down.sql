@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scores_completed_at;
+DROP INDEX IF EXISTS idx_scores_stage_completed_at;
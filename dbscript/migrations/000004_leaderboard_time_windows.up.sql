@@ -0,0 +1,9 @@
+-- Index untuk leaderboard harian/mingguan/bulanan.
+-- Query mengambil best score per user di dalam window completed_at,
+-- jadi kolom yang dibutuhkan ikut di-INCLUDE agar cukup index-only scan.
+CREATE INDEX IF NOT EXISTS idx_scores_stage_completed_at
+    ON scores(stage_id, completed_at DESC)
+    INCLUDE (user_id, final_score, total_time_ms);
+
+-- Untuk query lintas stage yang hanya memfilter berdasarkan waktu
+CREATE INDEX IF NOT EXISTS idx_scores_completed_at ON scores(completed_at DESC);
@@ -0,0 +1,28 @@
+-- Best score per user per stage, dipelihara oleh ScoreRepository.Create
+-- dalam transaksi yang sama dengan insert ke scores.
+-- Leaderboard all-time membaca tabel ini, bukan DISTINCT ON atas semua attempt.
+CREATE TABLE IF NOT EXISTS user_stage_bests (
+    user_id UUID NOT NULL,
+    stage_id UUID NOT NULL,
+    score_id INTEGER NOT NULL,
+    final_score DECIMAL(10, 2) NOT NULL,
+    total_time_ms INTEGER NOT NULL,
+    total_errors INTEGER NOT NULL,
+    completed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, stage_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE CASCADE,
+    FOREIGN KEY (score_id) REFERENCES scores(id) ON DELETE CASCADE
+);
+
+-- Urutan ranking leaderboard
+CREATE INDEX IF NOT EXISTS idx_user_stage_bests_ranking
+    ON user_stage_bests(stage_id, final_score DESC, total_time_ms ASC, user_id ASC);
+
+-- Backfill dari attempt yang sudah ada
+INSERT INTO user_stage_bests (user_id, stage_id, score_id, final_score, total_time_ms, total_errors, completed_at)
+SELECT DISTINCT ON (user_id, stage_id)
+    user_id, stage_id, id, final_score, total_time_ms, total_errors, completed_at
+FROM scores
+ORDER BY user_id, stage_id, final_score DESC, total_time_ms ASC, completed_at ASC
+ON CONFLICT (user_id, stage_id) DO NOTHING;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS season_standings;
+DROP FUNCTION IF EXISTS prevent_season_standings_change();
+
+DROP INDEX IF EXISTS idx_scores_season_stage;
+ALTER TABLE scores DROP COLUMN IF EXISTS season_id;
+
+DROP TABLE IF EXISTS seasons;
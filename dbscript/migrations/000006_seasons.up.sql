@@ -0,0 +1,50 @@
+-- Season kompetisi (per semester). Hanya boleh ada satu season aktif.
+CREATE TABLE IF NOT EXISTS seasons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    starts_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status IN ('active', 'closed'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_single_active ON seasons(status) WHERE status = 'active';
+
+-- Score diatribusikan ke season yang aktif saat disimpan (NULL = di luar season)
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS season_id UUID REFERENCES seasons(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_scores_season_stage
+    ON scores(season_id, stage_id, final_score DESC, total_time_ms ASC)
+    WHERE season_id IS NOT NULL;
+
+-- Snapshot final standings per stage saat season ditutup.
+-- Nama stage dan username ikut disalin supaya snapshot tidak berubah.
+CREATE TABLE IF NOT EXISTS season_standings (
+    season_id UUID NOT NULL,
+    stage_id UUID NOT NULL,
+    stage_name VARCHAR(255) NOT NULL,
+    rank INTEGER NOT NULL,
+    user_id UUID NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    score_id INTEGER NOT NULL,
+    final_score DECIMAL(10, 2) NOT NULL,
+    total_time_ms INTEGER NOT NULL,
+    total_errors INTEGER NOT NULL,
+    completed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (season_id, stage_id, rank),
+    FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE RESTRICT
+);
+
+-- Final standings bersifat immutable
+CREATE OR REPLACE FUNCTION prevent_season_standings_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'season standings are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS season_standings_immutable ON season_standings;
+CREATE TRIGGER season_standings_immutable
+    BEFORE UPDATE OR DELETE ON season_standings
+    FOR EACH ROW EXECUTE FUNCTION prevent_season_standings_change();
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_scores_user_completed_at;
@@ -0,0 +1,4 @@
+-- Index untuk riwayat attempt user (GET /api/me/scores): keyset
+-- pagination berdasarkan (completed_at, id), terbaru lebih dulu
+CREATE INDEX IF NOT EXISTS idx_scores_user_completed_at
+    ON scores(user_id, completed_at DESC, id DESC);
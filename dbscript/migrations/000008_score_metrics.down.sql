@@ -0,0 +1,4 @@
+ALTER TABLE scores
+    DROP COLUMN IF EXISTS wpm,
+    DROP COLUMN IF EXISTS accuracy,
+    DROP COLUMN IF EXISTS total_chars;
@@ -0,0 +1,21 @@
+-- Simpan metrik per attempt supaya statistik pemain bisa diagregasi di SQL
+ALTER TABLE scores
+    ADD COLUMN IF NOT EXISTS wpm NUMERIC(8, 2),
+    ADD COLUMN IF NOT EXISTS accuracy NUMERIC(5, 2),
+    ADD COLUMN IF NOT EXISTS total_chars INTEGER;
+
+-- Backfill attempt lama dengan rumus yang sama seperti GameService.SubmitScore
+-- (jumlah karakter = panjang byte semua phrase di stage)
+UPDATE scores sc
+SET total_chars = chars.total_chars,
+    wpm = ROUND(chars.total_chars / (GREATEST(sc.total_time_ms, 1) / 1000.0) * 60.0 / 5.0, 2),
+    accuracy = CASE
+        WHEN chars.total_chars = 0 THEN 100
+        ELSE ROUND(GREATEST(chars.total_chars - sc.total_errors, 0) * 100.0 / chars.total_chars, 2)
+    END
+FROM (
+    SELECT stage_id, SUM(octet_length(text))::int AS total_chars
+    FROM phrases
+    GROUP BY stage_id
+) chars
+WHERE chars.stage_id = sc.stage_id AND sc.wpm IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_bigram_stats;
+DROP TABLE IF EXISTS user_key_stats;
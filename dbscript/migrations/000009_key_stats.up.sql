@@ -0,0 +1,26 @@
+-- Statistik kumulatif per user untuk heatmap keyboard.
+-- presses dihitung dari teks phrase yang dimainkan, errors dan latency dari
+-- mistake events yang dikirim client saat submit score.
+CREATE TABLE IF NOT EXISTS user_key_stats (
+    user_id UUID NOT NULL,
+    key_char TEXT NOT NULL,
+    presses INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    total_mistake_latency_ms BIGINT NOT NULL DEFAULT 0,
+    mistake_latency_samples INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, key_char),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_bigram_stats (
+    user_id UUID NOT NULL,
+    bigram TEXT NOT NULL,
+    presses INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    total_mistake_latency_ms BIGINT NOT NULL DEFAULT 0,
+    mistake_latency_samples INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, bigram),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS profile_visibility,
+    DROP COLUMN IF EXISTS leaderboard_anonymous;
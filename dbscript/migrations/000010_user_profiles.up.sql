@@ -0,0 +1,6 @@
+-- Profil publik & pengaturan privasi pemain
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS profile_visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+        CHECK (profile_visibility IN ('public', 'private')),
+    ADD COLUMN IF NOT EXISTS leaderboard_anonymous BOOLEAN NOT NULL DEFAULT FALSE;
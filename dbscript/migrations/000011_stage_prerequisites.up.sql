@@ -0,0 +1,15 @@
+-- Syarat membuka stage: selesaikan stage lain dengan minimal bintang
+-- dan/atau accuracy tertentu (dari attempt terbaik)
+CREATE TABLE IF NOT EXISTS stage_prerequisites (
+    stage_id UUID NOT NULL,
+    required_stage_id UUID NOT NULL,
+    min_stars INTEGER NOT NULL DEFAULT 0 CHECK (min_stars BETWEEN 0 AND 3),
+    min_accuracy NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (min_accuracy BETWEEN 0 AND 100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stage_id, required_stage_id),
+    CHECK (stage_id <> required_stage_id),
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE CASCADE,
+    FOREIGN KEY (required_stage_id) REFERENCES stages(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stage_prerequisites_required ON stage_prerequisites(required_stage_id);
@@ -0,0 +1,31 @@
+-- Definisi achievement dikelola admin. rule_type menentukan cara evaluasi,
+-- params berisi parameter rule (lihat domain/services/achievement_rules.go)
+CREATE TABLE IF NOT EXISTS achievements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    rule_type VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id UUID NOT NULL,
+    achievement_id UUID NOT NULL,
+    score_id INTEGER,
+    awarded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, achievement_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (achievement_id) REFERENCES achievements(id) ON DELETE CASCADE,
+    FOREIGN KEY (score_id) REFERENCES scores(id) ON DELETE SET NULL
+);
+
+INSERT INTO achievements (code, name, description, rule_type, params) VALUES
+    ('first_60_wpm', 'Speed Demon', 'Capai 60 WPM untuk pertama kali', 'wpm_reached', '{"min_wpm": 60}'),
+    ('perfect_hard', 'Flawless', 'Accuracy 100% di stage hard', 'accuracy_reached', '{"min_accuracy": 100, "difficulty": "hard"}'),
+    ('streak_7', 'On Fire', 'Bermain 7 hari berturut-turut', 'daily_streak', '{"days": 7}'),
+    ('theme_master', 'Theme Master', 'Selesaikan semua stage dalam satu theme', 'theme_completed', '{}')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS daily_challenge_scores;
+DROP TABLE IF EXISTS daily_challenge_phrases;
+DROP TABLE IF EXISTS daily_challenges;
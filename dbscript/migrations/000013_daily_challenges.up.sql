@@ -0,0 +1,47 @@
+-- Daily challenge: satu set phrase per tanggal (timezone leaderboard),
+-- dipilih dari phrase semua stage aktif dengan seed dari tanggal.
+CREATE TABLE IF NOT EXISTS daily_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    challenge_date DATE NOT NULL UNIQUE,
+    seed BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Snapshot phrase supaya challenge lama tetap bisa dimainkan ulang
+-- walaupun phrase asalnya diubah atau dihapus
+CREATE TABLE IF NOT EXISTS daily_challenge_phrases (
+    challenge_id UUID NOT NULL,
+    sequence_number INTEGER NOT NULL,
+    phrase_id UUID,
+    stage_id UUID,
+    text TEXT NOT NULL,
+    base_multiplier DECIMAL(10, 2) NOT NULL DEFAULT 1.0,
+    PRIMARY KEY (challenge_id, sequence_number),
+    FOREIGN KEY (challenge_id) REFERENCES daily_challenges(id) ON DELETE CASCADE,
+    FOREIGN KEY (phrase_id) REFERENCES phrases(id) ON DELETE SET NULL,
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS daily_challenge_scores (
+    id SERIAL PRIMARY KEY,
+    challenge_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    final_score DECIMAL(10, 2) NOT NULL,
+    total_time_ms INTEGER NOT NULL,
+    total_errors INTEGER NOT NULL,
+    total_chars INTEGER NOT NULL,
+    wpm NUMERIC(8, 2) NOT NULL,
+    accuracy NUMERIC(5, 2) NOT NULL,
+    ranked BOOLEAN NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (challenge_id) REFERENCES daily_challenges(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Satu attempt ranked per user per challenge
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_challenge_scores_ranked
+    ON daily_challenge_scores(challenge_id, user_id) WHERE ranked;
+
+-- Urutan ranking leaderboard daily challenge
+CREATE INDEX IF NOT EXISTS idx_daily_challenge_scores_ranking
+    ON daily_challenge_scores(challenge_id, final_score DESC, total_time_ms ASC, user_id ASC) WHERE ranked;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_progress;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone;
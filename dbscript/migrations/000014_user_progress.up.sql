@@ -0,0 +1,59 @@
+-- Timezone user (IANA, mis. Asia/Jakarta) untuk menghitung streak harian.
+-- NULL = pakai LEADERBOARD_TIMEZONE.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+-- XP, level, dan streak harian, diperbarui dalam transaksi yang sama
+-- dengan insert ke scores
+CREATE TABLE IF NOT EXISTS user_progress (
+    user_id UUID PRIMARY KEY,
+    xp BIGINT NOT NULL DEFAULT 0,
+    level INTEGER NOT NULL DEFAULT 1,
+    current_streak INTEGER NOT NULL DEFAULT 0,
+    longest_streak INTEGER NOT NULL DEFAULT 0,
+    last_played_on DATE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Backfill dari attempt yang sudah ada dengan rumus yang sama seperti
+-- domain/services/player_level.go. Tanggal streak memakai jam server.
+WITH attempt_xp AS (
+    SELECT sc.user_id,
+        SUM(ROUND((10 + GREATEST(sc.final_score, 0) / 10.0) * CASE st.difficulty
+            WHEN 'medium' THEN 1.5
+            WHEN 'hard' THEN 2.0
+            ELSE 1.0
+        END))::bigint AS xp
+    FROM scores sc
+    JOIN stages st ON st.id = sc.stage_id
+    GROUP BY sc.user_id
+),
+days AS (
+    SELECT DISTINCT user_id, completed_at::date AS day FROM scores
+),
+islands AS (
+    SELECT user_id, day, day - (ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY day))::int AS grp
+    FROM days
+),
+runs AS (
+    SELECT user_id, COUNT(*)::int AS length, MAX(day) AS last_day
+    FROM islands
+    GROUP BY user_id, grp
+),
+streaks AS (
+    SELECT user_id,
+        (ARRAY_AGG(length ORDER BY last_day DESC))[1] AS current_streak,
+        MAX(length) AS longest_streak,
+        MAX(last_day) AS last_played_on
+    FROM runs
+    GROUP BY user_id
+)
+INSERT INTO user_progress (user_id, xp, level, current_streak, longest_streak, last_played_on)
+SELECT a.user_id, a.xp,
+    -- Invers total XP level n = 100 × n(n-1)/2
+    GREATEST(FLOOR((1 + SQRT(1 + 8 * a.xp / 100.0)) / 2), 1)::int,
+    s.current_streak, s.longest_streak, s.last_played_on
+FROM attempt_xp a
+JOIN streaks s ON s.user_id = a.user_id
+ON CONFLICT (user_id) DO NOTHING;
@@ -0,0 +1,5 @@
+ALTER TABLE themes
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS icon_key,
+    DROP COLUMN IF EXISTS color;
@@ -0,0 +1,15 @@
+-- Metadata tampilan theme yang dikelola dari admin panel
+ALTER TABLE themes
+    ADD COLUMN IF NOT EXISTS color VARCHAR(7),
+    ADD COLUMN IF NOT EXISTS icon_key VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Urutan awal mengikuti urutan nama sebelumnya
+UPDATE themes t
+SET sort_order = ordered.position * 10
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY name ASC) AS position
+    FROM themes
+) ordered
+WHERE ordered.id = t.id;
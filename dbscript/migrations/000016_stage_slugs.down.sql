@@ -0,0 +1,2 @@
+ALTER TABLE stages DROP CONSTRAINT IF EXISTS stages_slug_key;
+ALTER TABLE stages DROP COLUMN IF EXISTS slug;
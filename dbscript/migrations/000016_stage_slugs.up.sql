@@ -0,0 +1,22 @@
+-- Slug stabil untuk stage, dipakai import/export bundle antar environment
+ALTER TABLE stages ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+
+-- Backfill dari nama stage; nama yang sama diberi suffix -2, -3, ...
+WITH base AS (
+    SELECT id, created_at,
+        COALESCE(
+            NULLIF(TRIM(BOTH '-' FROM LEFT(regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'), 90)), ''),
+            'stage'
+        ) AS slug
+    FROM stages
+), numbered AS (
+    SELECT id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+    FROM base
+)
+UPDATE stages s
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.slug ELSE numbered.slug || '-' || numbered.n END
+FROM numbered
+WHERE numbered.id = s.id AND s.slug IS NULL;
+
+ALTER TABLE stages ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE stages ADD CONSTRAINT stages_slug_key UNIQUE (slug);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_scores_stage_version;
+ALTER TABLE scores DROP COLUMN IF EXISTS stage_version_id;
+ALTER TABLE stages
+    DROP COLUMN IF EXISTS leaderboard_min_version,
+    DROP COLUMN IF EXISTS published_version_id;
+DROP TABLE IF EXISTS stage_version_phrases;
+DROP TABLE IF EXISTS stage_versions;
@@ -0,0 +1,61 @@
+-- Versi stage yang sudah dipublish bersifat immutable. Tabel stages dan
+-- phrases menjadi draft yang diedit admin; pemain selalu memainkan versi
+-- published terbaru (stages.published_version_id).
+CREATE TABLE IF NOT EXISTS stage_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    stage_id UUID NOT NULL,
+    version_number INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    leaderboard_reset BOOLEAN NOT NULL DEFAULT false,
+    published_by UUID,
+    published_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (stage_id, version_number),
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE CASCADE,
+    FOREIGN KEY (published_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Snapshot phrase per versi; phrase_id menunjuk phrase draft asalnya
+CREATE TABLE IF NOT EXISTS stage_version_phrases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version_id UUID NOT NULL,
+    phrase_id UUID,
+    text TEXT NOT NULL,
+    sequence_number INTEGER NOT NULL,
+    base_multiplier DECIMAL(10, 2) NOT NULL DEFAULT 1.0,
+    UNIQUE (version_id, sequence_number),
+    FOREIGN KEY (version_id) REFERENCES stage_versions(id) ON DELETE CASCADE,
+    FOREIGN KEY (phrase_id) REFERENCES phrases(id) ON DELETE SET NULL
+);
+
+-- leaderboard_min_version: hanya score dari versi >= nilai ini yang masuk
+-- leaderboard stage (dinaikkan saat publish dengan reset leaderboard)
+ALTER TABLE stages
+    ADD COLUMN IF NOT EXISTS published_version_id UUID REFERENCES stage_versions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS leaderboard_min_version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE scores
+    ADD COLUMN IF NOT EXISTS stage_version_id UUID REFERENCES stage_versions(id) ON DELETE CASCADE;
+
+-- Backfill: konten saat ini menjadi versi 1 setiap stage, dan semua score
+-- lama dianggap dimainkan di versi tersebut
+INSERT INTO stage_versions (stage_id, version_number, name)
+SELECT id, 1, name FROM stages;
+
+INSERT INTO stage_version_phrases (version_id, phrase_id, text, sequence_number, base_multiplier)
+SELECT v.id, p.id, p.text, p.sequence_number, p.base_multiplier
+FROM phrases p
+JOIN stage_versions v ON v.stage_id = p.stage_id AND v.version_number = 1;
+
+UPDATE stages s
+SET published_version_id = v.id
+FROM stage_versions v
+WHERE v.stage_id = s.id AND v.version_number = 1;
+
+UPDATE scores sc
+SET stage_version_id = v.id
+FROM stage_versions v
+WHERE v.stage_id = sc.stage_id AND v.version_number = 1;
+
+ALTER TABLE scores ALTER COLUMN stage_version_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_scores_stage_version ON scores(stage_version_id);
@@ -0,0 +1,4 @@
+ALTER TABLE stages
+    DROP CONSTRAINT IF EXISTS stages_availability_window_check,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from;
@@ -0,0 +1,7 @@
+-- Jadwal buka/tutup stage untuk event terjadwal (NULL = tanpa batas).
+-- TIMESTAMP menyimpan waktu lokal server, sama seperti seasons.ends_at.
+ALTER TABLE stages
+    ADD COLUMN IF NOT EXISTS available_from TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS available_until TIMESTAMP,
+    ADD CONSTRAINT stages_availability_window_check
+        CHECK (available_from IS NULL OR available_until IS NULL OR available_from < available_until);
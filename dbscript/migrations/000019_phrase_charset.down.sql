@@ -0,0 +1,3 @@
+ALTER TABLE stages
+    DROP CONSTRAINT IF EXISTS stages_phrase_charset_check,
+    DROP COLUMN IF EXISTS phrase_charset;
@@ -0,0 +1,13 @@
+-- Himpunan karakter yang boleh dipakai phrase di stage (dicek oleh phrase
+-- linter saat create/update/import)
+ALTER TABLE stages
+    ADD COLUMN IF NOT EXISTS phrase_charset VARCHAR(20) NOT NULL DEFAULT 'qwerty',
+    ADD CONSTRAINT stages_phrase_charset_check CHECK (phrase_charset IN ('qwerty', 'latin', 'unicode'));
+
+-- Stage lama yang sudah berisi karakter non-ASCII tetap bisa diedit
+UPDATE stages s
+SET phrase_charset = 'unicode'
+WHERE EXISTS (
+    SELECT 1 FROM phrases p
+    WHERE p.stage_id = s.id AND p.text ~ '[^ -~]'
+);
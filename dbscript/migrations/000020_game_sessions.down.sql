@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS game_sessions;
+
+ALTER TABLE stages
+    DROP CONSTRAINT IF EXISTS stages_phrase_pool_size_check,
+    DROP COLUMN IF EXISTS phrase_pool_size;
@@ -0,0 +1,24 @@
+-- phrase_pool_size > 0: setiap sesi memainkan sejumlah phrase acak dari
+-- versi published (0 = semua phrase berurutan)
+ALTER TABLE stages
+    ADD COLUMN IF NOT EXISTS phrase_pool_size INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT stages_phrase_pool_size_check CHECK (phrase_pool_size >= 0);
+
+-- Sesi permainan stage dengan phrase pool. phrase_ids adalah id
+-- stage_version_phrases yang disajikan, sesuai urutan tampil; submit score
+-- dinilai dari phrase ini dan setiap sesi hanya bisa disubmit sekali.
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    stage_id UUID NOT NULL,
+    stage_version_id UUID NOT NULL,
+    seed BIGINT NOT NULL,
+    phrase_ids UUID[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE CASCADE,
+    FOREIGN KEY (stage_version_id) REFERENCES stage_versions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_sessions_user ON game_sessions(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE stages
+    DROP CONSTRAINT IF EXISTS stages_whitespace_policy_check,
+    DROP COLUMN IF EXISTS whitespace_policy;
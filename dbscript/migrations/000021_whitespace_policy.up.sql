@@ -0,0 +1,6 @@
+-- Cara whitespace phrase multi-baris dinilai saat diketik:
+-- strict (semua spasi diketik), collapse (deretan spasi cukup diketik sekali)
+-- atau auto_indent (indentasi di awal baris diisi otomatis oleh client)
+ALTER TABLE stages
+    ADD COLUMN IF NOT EXISTS whitespace_policy VARCHAR(20) NOT NULL DEFAULT 'strict',
+    ADD CONSTRAINT stages_whitespace_policy_check CHECK (whitespace_policy IN ('strict', 'collapse', 'auto_indent'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_stages_phrase_language;
+ALTER TABLE stages DROP COLUMN IF EXISTS phrase_language;
+
+DROP TABLE IF EXISTS theme_translations;
+DROP TABLE IF EXISTS stage_translations;
@@ -0,0 +1,30 @@
+-- Terjemahan teks tampilan stage dan theme. Kolom name/description di
+-- stages dan themes adalah teks dalam locale default (DEFAULT_LOCALE);
+-- locale disimpan sebagai subtag bahasa utama (mis. 'en', 'id').
+CREATE TABLE IF NOT EXISTS stage_translations (
+    stage_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stage_id, locale),
+    FOREIGN KEY (stage_id) REFERENCES stages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS theme_translations (
+    theme_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (theme_id, locale),
+    FOREIGN KEY (theme_id) REFERENCES themes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stage_translations_locale ON stage_translations(locale);
+CREATE INDEX IF NOT EXISTS idx_theme_translations_locale ON theme_translations(locale);
+
+-- Bahasa teks phrase stage (NULL = belum ditentukan), untuk filter daftar stage
+ALTER TABLE stages ADD COLUMN IF NOT EXISTS phrase_language VARCHAR(10);
+CREATE INDEX IF NOT EXISTS idx_stages_phrase_language ON stages(phrase_language);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS scores_recompute_best ON scores;
+DROP FUNCTION IF EXISTS recompute_user_stage_best();
+
+ALTER TABLE user_stage_bests DROP CONSTRAINT IF EXISTS user_stage_bests_score_id_fkey;
+ALTER TABLE user_stage_bests
+    ADD CONSTRAINT user_stage_bests_score_id_fkey FOREIGN KEY (score_id) REFERENCES scores(id) ON DELETE CASCADE;
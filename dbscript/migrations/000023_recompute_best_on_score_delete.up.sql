@@ -0,0 +1,60 @@
+-- Menghapus attempt terbaik user tidak boleh menghapus user dari
+-- leaderboard jika masih ada attempt lain. Sebelum score dihapus, best
+-- score dihitung ulang dari attempt yang tersisa (dengan aturan yang sama
+-- seperti ScoreRepository.Create); baris best hanya dihapus jika tidak ada
+-- attempt lain. Perubahan dikirim lewat NOTIFY sebagai reset (score_id 0)
+-- supaya cache top-N dan stream SSE di semua instance ikut diperbarui.
+ALTER TABLE user_stage_bests DROP CONSTRAINT IF EXISTS user_stage_bests_score_id_fkey;
+ALTER TABLE user_stage_bests
+    ADD CONSTRAINT user_stage_bests_score_id_fkey FOREIGN KEY (score_id) REFERENCES scores(id);
+
+CREATE OR REPLACE FUNCTION recompute_user_stage_best() RETURNS trigger AS $$
+DECLARE
+    replacement RECORD;
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM user_stage_bests
+        WHERE user_id = OLD.user_id AND stage_id = OLD.stage_id AND score_id = OLD.id
+    ) THEN
+        RETURN OLD;
+    END IF;
+
+    -- Stage atau user sedang dihapus: baris best ikut terhapus lewat FK-nya
+    IF NOT EXISTS (SELECT 1 FROM stages WHERE id = OLD.stage_id)
+        OR NOT EXISTS (SELECT 1 FROM users WHERE id = OLD.user_id) THEN
+        RETURN OLD;
+    END IF;
+
+    SELECT sc.id, sc.final_score, sc.total_time_ms, sc.total_errors, sc.completed_at
+    INTO replacement
+    FROM scores sc
+    JOIN stages st ON st.id = sc.stage_id
+    JOIN stage_versions v ON v.id = sc.stage_version_id
+    WHERE sc.user_id = OLD.user_id
+        AND sc.stage_id = OLD.stage_id
+        AND sc.id <> OLD.id
+        AND v.version_number >= st.leaderboard_min_version
+    ORDER BY sc.final_score DESC, sc.total_time_ms ASC, sc.completed_at ASC
+    LIMIT 1;
+
+    IF FOUND THEN
+        UPDATE user_stage_bests
+        SET score_id = replacement.id,
+            final_score = replacement.final_score,
+            total_time_ms = replacement.total_time_ms,
+            total_errors = replacement.total_errors,
+            completed_at = replacement.completed_at
+        WHERE user_id = OLD.user_id AND stage_id = OLD.stage_id;
+    ELSE
+        DELETE FROM user_stage_bests WHERE user_id = OLD.user_id AND stage_id = OLD.stage_id;
+    END IF;
+
+    PERFORM pg_notify('leaderboard_updates', json_build_object('stage_id', OLD.stage_id, 'score_id', 0)::text);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS scores_recompute_best ON scores;
+CREATE TRIGGER scores_recompute_best
+    BEFORE DELETE ON scores
+    FOR EACH ROW EXECUTE FUNCTION recompute_user_stage_best();
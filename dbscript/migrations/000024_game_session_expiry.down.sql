@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_game_sessions_open;
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS expires_at;
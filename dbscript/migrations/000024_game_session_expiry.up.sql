@@ -0,0 +1,20 @@
+-- Sesi permainan dimulai lewat POST /api/stage/:id/session dan punya batas
+-- waktu. Sesi lama diberi expiry dari created_at.
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+UPDATE game_sessions SET expires_at = created_at + INTERVAL '30 minutes' WHERE expires_at IS NULL;
+ALTER TABLE game_sessions ALTER COLUMN expires_at SET NOT NULL;
+
+-- Satu sesi terbuka per (user, stage, versi): sesi yang belum disubmit
+-- dipakai ulang alih-alih mengundi phrase baru. Sisakan sesi terbuka terbaru.
+DELETE FROM game_sessions g
+WHERE g.submitted_at IS NULL
+  AND EXISTS (
+      SELECT 1 FROM game_sessions n
+      WHERE n.user_id = g.user_id AND n.stage_id = g.stage_id
+        AND n.stage_version_id = g.stage_version_id AND n.submitted_at IS NULL
+        AND (n.created_at, n.id) > (g.created_at, g.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_game_sessions_open
+    ON game_sessions(user_id, stage_id, stage_version_id)
+    WHERE submitted_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE stage_versions
+    DROP CONSTRAINT IF EXISTS stage_versions_phrase_pool_size_check,
+    DROP CONSTRAINT IF EXISTS stage_versions_whitespace_policy_check,
+    DROP COLUMN IF EXISTS phrase_pool_size,
+    DROP COLUMN IF EXISTS whitespace_policy;
@@ -0,0 +1,13 @@
+-- whitespace_policy dan phrase_pool_size ikut di-snapshot saat publish,
+-- sehingga mengubah draft stage tidak mengubah cara versi published
+-- disajikan dan dinilai. Versi lama mengambil nilai stage saat ini.
+ALTER TABLE stage_versions
+    ADD COLUMN IF NOT EXISTS whitespace_policy VARCHAR(20) NOT NULL DEFAULT 'strict',
+    ADD COLUMN IF NOT EXISTS phrase_pool_size INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT stage_versions_whitespace_policy_check CHECK (whitespace_policy IN ('strict', 'collapse', 'auto_indent')),
+    ADD CONSTRAINT stage_versions_phrase_pool_size_check CHECK (phrase_pool_size >= 0);
+
+UPDATE stage_versions v
+SET whitespace_policy = s.whitespace_policy, phrase_pool_size = s.phrase_pool_size
+FROM stages s
+WHERE s.id = v.stage_id;
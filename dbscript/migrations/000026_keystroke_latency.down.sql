@@ -0,0 +1,7 @@
+ALTER TABLE user_bigram_stats
+    DROP COLUMN IF EXISTS latency_samples,
+    DROP COLUMN IF EXISTS total_latency_ms;
+
+ALTER TABLE user_key_stats
+    DROP COLUMN IF EXISTS latency_samples,
+    DROP COLUMN IF EXISTS total_latency_ms;
@@ -0,0 +1,10 @@
+-- Latency dicatat untuk setiap keystroke (latencies_ms per phrase saat
+-- submit), bukan hanya untuk keystroke yang salah, supaya rata-rata latency
+-- heatmap tidak bias ke tombol yang sering salah.
+ALTER TABLE user_key_stats
+    ADD COLUMN IF NOT EXISTS total_latency_ms BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS latency_samples INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE user_bigram_stats
+    ADD COLUMN IF NOT EXISTS total_latency_ms BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS latency_samples INTEGER NOT NULL DEFAULT 0;
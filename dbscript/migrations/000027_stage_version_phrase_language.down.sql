@@ -0,0 +1 @@
+ALTER TABLE stage_versions DROP COLUMN IF EXISTS phrase_language;
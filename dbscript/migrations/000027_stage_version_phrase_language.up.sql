@@ -0,0 +1,8 @@
+-- phrase_language ikut di-snapshot saat publish seperti isi phrase-nya;
+-- filter bahasa di daftar stage pemain memakai nilai versi published.
+ALTER TABLE stage_versions ADD COLUMN IF NOT EXISTS phrase_language VARCHAR(10);
+
+UPDATE stage_versions v
+SET phrase_language = s.phrase_language
+FROM stages s
+WHERE s.id = v.stage_id;
@@ -0,0 +1,11 @@
+-- Karakter yang diketik pemain sebagai ganti karakter phrase (typed pada
+-- mistake event), untuk melihat pasangan karakter yang sering tertukar.
+CREATE TABLE IF NOT EXISTS user_key_substitutions (
+    user_id UUID NOT NULL,
+    expected TEXT NOT NULL,
+    typed TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, expected, typed),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- Sesi daily challenge dimulai lewat POST /api/daily/session. Submit wajib
+-- menyebut sesi: challenge yang dinilai dan status ranked diambil dari sesi,
+-- dan total_time_ms tidak boleh melebihi waktu sejak sesi dimulai.
+CREATE TABLE IF NOT EXISTS daily_challenge_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    challenge_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    ranked BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    submitted_at TIMESTAMP,
+    FOREIGN KEY (challenge_id) REFERENCES daily_challenges(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Satu sesi terbuka per (user, challenge): memulai ulang memakai sesi yang
+-- sama sehingga waktu mulai tidak bisa di-reset
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_challenge_sessions_open
+    ON daily_challenge_sessions(user_id, challenge_id)
+    WHERE submitted_at IS NULL;
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAchievementNotFound   = errors.New("achievement not found")
+	ErrAchievementCodeExists = errors.New("achievement code already exists")
+)
+
+// AchievementStatus adalah achievement beserta status perolehan user
+type AchievementStatus struct {
+	Achievement *models.Achievement
+	Earned      bool
+	AwardedAt   *time.Time
+}
+
+type AchievementService struct {
+	achievementRepo repositories.AchievementRepository
+	scoreRepo       repositories.ScoreRepository
+	progress        *ProgressService
+}
+
+// NewAchievementService membuat service achievement. Rule daily_streak
+// membaca streak dari progress, yang sudah memakai batas hari timezone user.
+func NewAchievementService(
+	achievementRepo repositories.AchievementRepository,
+	scoreRepo repositories.ScoreRepository,
+	progress *ProgressService,
+) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		scoreRepo:       scoreRepo,
+		progress:        progress,
+	}
+}
+
+// EvaluateAfterScore memeriksa semua achievement aktif yang belum dimiliki
+// user terhadap score yang baru disimpan, lalu mengembalikan yang baru didapat
+func (s *AchievementService) EvaluateAfterScore(ctx context.Context, score *models.Score, stage *models.Stage) ([]*models.Achievement, error) {
+	achievements, err := s.achievementRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	earned, err := s.earnedByUser(ctx, score.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		achievement *models.Achievement
+		rule        domainservices.AchievementRule
+	}
+	var candidates []candidate
+	needsFacts := false
+	for _, achievement := range achievements {
+		if _, ok := earned[achievement.ID]; ok {
+			continue
+		}
+		// Definisi sudah divalidasi saat disimpan; yang rusak dilewati
+		rule, err := domainservices.ParseAchievementRule(achievement.RuleType, achievement.Params)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{achievement: achievement, rule: rule})
+		needsFacts = needsFacts || rule.NeedsFacts()
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	input := &domainservices.AchievementInput{Score: score, Stage: stage}
+	if needsFacts {
+		input.Facts, err = s.playerFacts(ctx, score.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var awarded []*models.Achievement
+	for _, c := range candidates {
+		if !c.rule.Satisfied(input) {
+			continue
+		}
+		isNew, err := s.achievementRepo.Award(ctx, score.UserID, c.achievement.ID, score.ID)
+		if err != nil {
+			return nil, err
+		}
+		if isNew {
+			awarded = append(awarded, c.achievement)
+		}
+	}
+	return awarded, nil
+}
+
+// GetUserAchievements mengembalikan achievement aktif (earned atau masih
+// terkunci) dan achievement nonaktif yang sudah pernah didapat user
+func (s *AchievementService) GetUserAchievements(ctx context.Context, userID string) ([]*AchievementStatus, error) {
+	achievements, err := s.achievementRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	earned, err := s.earnedByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*AchievementStatus
+	for _, achievement := range achievements {
+		status := &AchievementStatus{Achievement: achievement}
+		if ua, ok := earned[achievement.ID]; ok {
+			status.Earned = true
+			status.AwardedAt = &ua.AwardedAt
+		} else if !achievement.IsActive {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Admin: kelola definisi achievement
+func (s *AchievementService) GetAllAchievements(ctx context.Context) ([]*models.Achievement, error) {
+	return s.achievementRepo.FindAll(ctx)
+}
+
+func (s *AchievementService) CreateAchievement(ctx context.Context, achievement *models.Achievement) (*models.Achievement, error) {
+	if err := validateAchievement(achievement); err != nil {
+		return nil, err
+	}
+	err := s.achievementRepo.Create(ctx, achievement)
+	if err == repositories.ErrDuplicate {
+		return nil, ErrAchievementCodeExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return achievement, nil
+}
+
+func (s *AchievementService) UpdateAchievement(ctx context.Context, achievementID string, update *models.Achievement) (*models.Achievement, error) {
+	achievement, err := s.findAchievement(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+
+	achievement.Code = update.Code
+	achievement.Name = update.Name
+	achievement.Description = update.Description
+	achievement.RuleType = update.RuleType
+	achievement.Params = update.Params
+	achievement.IsActive = update.IsActive
+
+	if err := validateAchievement(achievement); err != nil {
+		return nil, err
+	}
+	err = s.achievementRepo.Update(ctx, achievement)
+	if err == repositories.ErrDuplicate {
+		return nil, ErrAchievementCodeExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return achievement, nil
+}
+
+// DeleteAchievement juga menghapus badge yang sudah didapat user. Untuk
+// menyembunyikan achievement tanpa menghapus badge, nonaktifkan saja.
+func (s *AchievementService) DeleteAchievement(ctx context.Context, achievementID string) error {
+	if _, err := s.findAchievement(ctx, achievementID); err != nil {
+		return err
+	}
+	return s.achievementRepo.Delete(ctx, achievementID)
+}
+
+func (s *AchievementService) findAchievement(ctx context.Context, achievementID string) (*models.Achievement, error) {
+	if _, err := uuid.Parse(achievementID); err != nil {
+		return nil, ErrAchievementNotFound
+	}
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if achievement == nil {
+		return nil, ErrAchievementNotFound
+	}
+	return achievement, nil
+}
+
+// validateAchievement memeriksa rule achievement. Keunikan code dijaga
+// unique constraint di database (ErrDuplicate), bukan dicek lebih dulu,
+// supaya create bersamaan dengan code yang sama tetap menghasilkan 409.
+func validateAchievement(achievement *models.Achievement) error {
+	if len(achievement.Params) == 0 {
+		achievement.Params = []byte("{}")
+	}
+	_, err := domainservices.ParseAchievementRule(achievement.RuleType, achievement.Params)
+	return err
+}
+
+func (s *AchievementService) earnedByUser(ctx context.Context, userID string) (map[string]*models.UserAchievement, error) {
+	awarded, err := s.achievementRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	earned := make(map[string]*models.UserAchievement, len(awarded))
+	for _, ua := range awarded {
+		earned[ua.AchievementID] = ua
+	}
+	return earned, nil
+}
+
+func (s *AchievementService) playerFacts(ctx context.Context, userID string) (*models.PlayerFacts, error) {
+	summary, err := s.scoreRepo.FindPlayerSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Streak yang sama dengan yang ditampilkan di profil (user_progress)
+	progress, err := s.progress.GetProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	themes, err := s.scoreRepo.FindCompletedThemes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PlayerFacts{
+		StreakDays:      progress.CurrentStreak,
+		TotalAttempts:   summary.TotalAttempts,
+		StagesCompleted: summary.StagesCompleted,
+		CompletedThemes: themes,
+	}, nil
+}
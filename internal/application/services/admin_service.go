@@ -3,16 +3,61 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"uwika_quick_typer_game/internal/domain/models"
 	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRequiredStageNotFound     = errors.New("required stage not found")
+	ErrThemeNameExists           = errors.New("theme name already exists")
+	ErrInvalidThemeName          = errors.New("theme name is required and must be at most 255 characters")
+	ErrThemeInUse                = errors.New("theme is still used by one or more stages")
+	ErrInvalidThemeColor         = errors.New("color must be a hex color like #1E88E5")
+	ErrInvalidIconKey            = errors.New("icon_key may only contain lowercase letters, digits, '-' and '_'")
+	ErrInvalidStageSlug          = errors.New("slug may only contain lowercase letters, digits and single '-' between them (max 100)")
+	ErrStageSlugExists           = errors.New("stage slug already exists")
+	ErrPhraseOrderMismatch       = errors.New("phrase_ids must list every phrase of the stage exactly once")
+	ErrNothingToPublish          = errors.New("draft has no changes since the last published version")
+	ErrStageHasNoPhrases         = errors.New("stage needs at least one phrase to be published")
+	ErrInvalidAvailabilityWindow = errors.New("available_from must be before available_until")
+	ErrInvalidPhraseCharset      = errors.New("phrase_charset must be qwerty, latin or unicode")
+	ErrPhraseNotFound            = errors.New("phrase not found")
+	ErrInvalidPhrasePoolSize     = errors.New("phrase_pool_size must be 0 or greater")
+	ErrInvalidWhitespacePolicy   = errors.New("whitespace_policy must be strict, collapse or auto_indent")
+	ErrInvalidPhraseLanguage     = errors.New("phrase_language must be a language code such as en or id")
+	ErrInvalidMultiplierScale    = errors.New("multiplier_scale must be greater than 0")
+)
+
+var (
+	themeColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	iconKeyPattern    = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
 )
 
+// maxThemeNameLength mengikuti kolom themes.name VARCHAR(255)
+const maxThemeNameLength = 255
+
 type AdminService struct {
 	stageRepo  repositories.StageRepository
 	phraseRepo repositories.PhraseRepository
 	userRepo   repositories.UserRepository
 	themeRepo  repositories.ThemeRepository
+
+	prerequisiteRepo repositories.StagePrerequisiteRepository
+	versionRepo      repositories.StageVersionRepository
+	transactor       repositories.Transactor
+	phraseLinter     *domainservices.PhraseLinter
+	phraseAnalyzer   *domainservices.PhraseAnalyzer
 }
 
 func NewAdminService(
@@ -20,12 +65,22 @@ func NewAdminService(
 	phraseRepo repositories.PhraseRepository,
 	userRepo repositories.UserRepository,
 	themeRepo repositories.ThemeRepository,
+	prerequisiteRepo repositories.StagePrerequisiteRepository,
+	versionRepo repositories.StageVersionRepository,
+	transactor repositories.Transactor,
+	phraseLinter *domainservices.PhraseLinter,
+	phraseAnalyzer *domainservices.PhraseAnalyzer,
 ) *AdminService {
 	return &AdminService{
-		stageRepo:  stageRepo,
-		phraseRepo: phraseRepo,
-		userRepo:   userRepo,
-		themeRepo:  themeRepo,
+		stageRepo:        stageRepo,
+		phraseRepo:       phraseRepo,
+		userRepo:         userRepo,
+		themeRepo:        themeRepo,
+		prerequisiteRepo: prerequisiteRepo,
+		versionRepo:      versionRepo,
+		transactor:       transactor,
+		phraseLinter:     phraseLinter,
+		phraseAnalyzer:   phraseAnalyzer,
 	}
 }
 
@@ -34,22 +89,235 @@ func (s *AdminService) GetAllThemes(ctx context.Context) ([]*models.Theme, error
 	return s.themeRepo.FindAll(ctx)
 }
 
+func (s *AdminService) CreateTheme(ctx context.Context, name, description, color, iconKey string, sortOrder int) (*models.Theme, error) {
+	theme := &models.Theme{}
+	if err := applyTheme(theme, name, description, color, iconKey, sortOrder); err != nil {
+		return nil, err
+	}
+
+	err := s.themeRepo.Create(ctx, theme)
+	if err == repositories.ErrDuplicate {
+		return nil, ErrThemeNameExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+func (s *AdminService) UpdateTheme(ctx context.Context, themeID, name, description, color, iconKey string, sortOrder int) (*models.Theme, error) {
+	theme, err := s.findTheme(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTheme(theme, name, description, color, iconKey, sortOrder); err != nil {
+		return nil, err
+	}
+
+	err = s.themeRepo.Update(ctx, theme)
+	if err == repositories.ErrDuplicate {
+		return nil, ErrThemeNameExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// DeleteTheme ditolak dengan ErrThemeInUse selama masih ada stage yang
+// memakai theme tersebut
+func (s *AdminService) DeleteTheme(ctx context.Context, themeID string) error {
+	if _, err := s.findTheme(ctx, themeID); err != nil {
+		return err
+	}
+
+	err := s.themeRepo.Delete(ctx, themeID)
+	if err == repositories.ErrReferenced {
+		return ErrThemeInUse
+	}
+	return err
+}
+
+func (s *AdminService) findTheme(ctx context.Context, themeID string) (*models.Theme, error) {
+	if _, err := uuid.Parse(themeID); err != nil {
+		return nil, ErrThemeNotFound
+	}
+	theme, err := s.themeRepo.FindByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if theme == nil {
+		return nil, ErrThemeNotFound
+	}
+	return theme, nil
+}
+
+func applyTheme(theme *models.Theme, name, description, color, iconKey string, sortOrder int) error {
+	name = strings.TrimSpace(name)
+	if !validThemeName(name) {
+		return ErrInvalidThemeName
+	}
+	color = strings.TrimSpace(color)
+	if color != "" && !themeColorPattern.MatchString(color) {
+		return ErrInvalidThemeColor
+	}
+	iconKey = strings.TrimSpace(iconKey)
+	if iconKey != "" && !iconKeyPattern.MatchString(iconKey) {
+		return ErrInvalidIconKey
+	}
+
+	theme.Name = name
+	theme.Description = description
+	theme.Color = strings.ToUpper(color)
+	theme.IconKey = iconKey
+	theme.SortOrder = sortOrder
+	return nil
+}
+
+// validThemeName memeriksa nama theme yang sudah di-trim, baik dari
+// CreateTheme/UpdateTheme maupun yang dibuat otomatis oleh import bundle
+func validThemeName(name string) bool {
+	return name != "" && utf8.RuneCountInString(name) <= maxThemeNameLength
+}
+
 // Stage Management
-func (s *AdminService) CreateStage(ctx context.Context, name, themeID, difficulty string, isActive bool) (*models.Stage, error) {
+
+// CreateStage membuat stage baru. Slug kosong dibuat otomatis dari nama,
+// phraseCharset kosong berarti qwerty dan whitespacePolicy kosong berarti
+// strict. phrasePoolSize > 0 membuat setiap sesi memainkan sejumlah phrase
+// acak. phraseLanguage (mis. "en") boleh kosong.
+//
+// phraseTexts (opsional) disimpan sebagai phrase awal berurutan dengan
+// multiplier saran PhraseAnalyzer; difficulty kosong diisi saran analyzer
+// dari phrase tersebut. Pelanggaran linter dikembalikan sebagai
+// *ValidationError.
+func (s *AdminService) CreateStage(ctx context.Context, name, slug, themeID, difficulty string, isActive bool, availableFrom, availableUntil *time.Time, phraseCharset string, phrasePoolSize int, whitespacePolicy, phraseLanguage string, phraseTexts []string) (*models.Stage, error) {
+	if !validAvailabilityWindow(availableFrom, availableUntil) {
+		return nil, ErrInvalidAvailabilityWindow
+	}
+	if phrasePoolSize < 0 {
+		return nil, ErrInvalidPhrasePoolSize
+	}
+	if phraseCharset == "" {
+		phraseCharset = domainservices.PhraseCharsetQWERTY
+	} else if !domainservices.ValidPhraseCharset(phraseCharset) {
+		return nil, ErrInvalidPhraseCharset
+	}
+	if whitespacePolicy == "" {
+		whitespacePolicy = domainservices.WhitespaceStrict
+	} else if !domainservices.ValidWhitespacePolicy(whitespacePolicy) {
+		return nil, ErrInvalidWhitespacePolicy
+	}
+	phraseLanguage, err := normalizePhraseLanguage(phraseLanguage)
+	if err != nil {
+		return nil, err
+	}
+	if slug == "" {
+		generated, err := uniqueStageSlug(ctx, s.stageRepo, name)
+		if err != nil {
+			return nil, err
+		}
+		slug = generated
+	} else if !domainservices.ValidSlug(slug) {
+		return nil, ErrInvalidStageSlug
+	}
+
+	phrases, difficulty, err := s.initialPhrases(phraseTexts, difficulty, phraseCharset, whitespacePolicy)
+	if err != nil {
+		return nil, err
+	}
+
 	stage := &models.Stage{
+		Slug:       slug,
 		Name:       name,
 		ThemeID:    themeID,
 		Difficulty: difficulty,
 		IsActive:   isActive,
+
+		AvailableFrom:    availableFrom,
+		AvailableUntil:   availableUntil,
+		PhraseCharset:    phraseCharset,
+		PhrasePoolSize:   phrasePoolSize,
+		WhitespacePolicy: whitespacePolicy,
+		PhraseLanguage:   phraseLanguage,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.stageRepo.Create(ctx, stage); err != nil {
+			return err
+		}
+		for _, phrase := range phrases {
+			phrase.StageID = stage.ID
+			if err := s.phraseRepo.Create(ctx, phrase); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == repositories.ErrDuplicate {
+		return nil, ErrStageSlugExists
 	}
-	err := s.stageRepo.Create(ctx, stage)
 	if err != nil {
 		return nil, err
 	}
 	return stage, nil
 }
 
-func (s *AdminService) UpdateStage(ctx context.Context, stageID, name, themeID, difficulty string, isActive bool) (*models.Stage, error) {
+// initialPhrases menormalisasi dan memeriksa phrase awal stage baru, mengisi
+// multiplier dari saran PhraseAnalyzer, lalu menentukan difficulty: nilai
+// yang dikirim admin atau saran analyzer jika kosong.
+func (s *AdminService) initialPhrases(texts []string, difficulty, phraseCharset, whitespacePolicy string) ([]*models.Phrase, string, error) {
+	problems := &ValidationError{}
+	phrases := make([]*models.Phrase, 0, len(texts))
+	seen := make(map[string]int)
+	for i, text := range texts {
+		path := "phrases[" + strconv.Itoa(i) + "]."
+		phrase := &models.Phrase{
+			Text:           s.phraseLinter.NormalizeText(text),
+			SequenceNumber: i + 1,
+		}
+		phrase.BaseMultiplier = s.phraseAnalyzer.Analyze(phrase.Text, whitespacePolicy).SuggestedMultiplier
+		problems.addPhraseIssues(path, "base_multiplier", s.phraseLinter.Lint(phrase.Text, phrase.BaseMultiplier, phraseCharset))
+		if first, ok := seen[phrase.Text]; ok && phrase.Text != "" {
+			problems.add(path+"text", "duplicates phrases[%d] in this stage", first)
+		}
+		seen[phrase.Text] = i
+		phrases = append(phrases, phrase)
+	}
+
+	if difficulty == "" {
+		difficulty = s.phraseAnalyzer.AnalyzeStage(phrases, whitespacePolicy).SuggestedDifficulty
+		if difficulty == "" {
+			problems.add("difficulty", "is required for a stage without phrases")
+		}
+	}
+	if err := problems.err(); err != nil {
+		return nil, "", err
+	}
+	return phrases, difficulty, nil
+}
+
+// UpdateStage mengubah stage. Slug, phraseCharset dan whitespacePolicy
+// kosong berarti nilai lama dipertahankan; jadwal, phrase pool dan
+// phraseLanguage selalu ditimpa (nil/0/kosong = tanpa batas / semua phrase /
+// belum ditentukan). Charset baru ditolak jika ada phrase stage yang tidak
+// lolos charset tersebut.
+func (s *AdminService) UpdateStage(ctx context.Context, stageID, name, slug, themeID, difficulty string, isActive bool, availableFrom, availableUntil *time.Time, phraseCharset string, phrasePoolSize int, whitespacePolicy, phraseLanguage string) (*models.Stage, error) {
+	if !validAvailabilityWindow(availableFrom, availableUntil) {
+		return nil, ErrInvalidAvailabilityWindow
+	}
+	if phrasePoolSize < 0 {
+		return nil, ErrInvalidPhrasePoolSize
+	}
+	if phraseCharset != "" && !domainservices.ValidPhraseCharset(phraseCharset) {
+		return nil, ErrInvalidPhraseCharset
+	}
+	if whitespacePolicy != "" && !domainservices.ValidWhitespacePolicy(whitespacePolicy) {
+		return nil, ErrInvalidWhitespacePolicy
+	}
+	phraseLanguage, err := normalizePhraseLanguage(phraseLanguage)
+	if err != nil {
+		return nil, err
+	}
 	stage, err := s.stageRepo.FindByID(ctx, stageID)
 	if err != nil {
 		return nil, err
@@ -57,36 +325,300 @@ func (s *AdminService) UpdateStage(ctx context.Context, stageID, name, themeID,
 	if stage == nil {
 		return nil, ErrStageNotFound
 	}
+	if slug != "" {
+		if !domainservices.ValidSlug(slug) {
+			return nil, ErrInvalidStageSlug
+		}
+		stage.Slug = slug
+	}
+	if phraseCharset != "" && phraseCharset != stage.PhraseCharset {
+		if err := s.checkPhraseCharset(ctx, stage.ID, phraseCharset); err != nil {
+			return nil, err
+		}
+		stage.PhraseCharset = phraseCharset
+	}
+	if whitespacePolicy != "" {
+		stage.WhitespacePolicy = whitespacePolicy
+	}
 
 	stage.Name = name
 	stage.ThemeID = themeID
 	stage.Difficulty = difficulty
 	stage.IsActive = isActive
+	stage.AvailableFrom = availableFrom
+	stage.AvailableUntil = availableUntil
+	stage.PhrasePoolSize = phrasePoolSize
+	stage.PhraseLanguage = phraseLanguage
 
 	err = s.stageRepo.Update(ctx, stage)
+	if err == repositories.ErrDuplicate {
+		return nil, ErrStageSlugExists
+	}
 	if err != nil {
 		return nil, err
 	}
 	return stage, nil
 }
 
+// normalizePhraseLanguage menyimpan bahasa phrase sebagai subtag bahasa
+// utama ("en-US" menjadi "en"); kosong berarti belum ditentukan
+func normalizePhraseLanguage(language string) (string, error) {
+	if strings.TrimSpace(language) == "" {
+		return "", nil
+	}
+	normalized := domainservices.NormalizeLocale(language)
+	if normalized == "" {
+		return "", ErrInvalidPhraseLanguage
+	}
+	return normalized, nil
+}
+
+func filterByPhraseLanguage(stages []*models.Stage, language string) []*models.Stage {
+	filtered := make([]*models.Stage, 0, len(stages))
+	for _, stage := range stages {
+		if stage.PhraseLanguage == language {
+			filtered = append(filtered, stage)
+		}
+	}
+	return filtered
+}
+
+func validAvailabilityWindow(from, until *time.Time) bool {
+	return from == nil || until == nil || from.Before(*until)
+}
+
+// checkPhraseCharset memastikan semua phrase stage masih valid dengan
+// charset baru
+func (s *AdminService) checkPhraseCharset(ctx context.Context, stageID, charset string) error {
+	phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+	if err != nil {
+		return err
+	}
+	problems := &ValidationError{}
+	for _, phrase := range phrases {
+		for _, issue := range s.phraseLinter.Lint(phrase.Text, phrase.BaseMultiplier, charset) {
+			if issue.Field == domainservices.PhraseFieldText {
+				problems.add("phrase_charset", "phrase #%d %s", phrase.SequenceNumber, issue.Message)
+			}
+		}
+	}
+	return problems.err()
+}
+
+// uniqueStageSlug membuat slug dari nama dan menambahkan suffix -2, -3, ...
+// jika slug tersebut sudah dipakai stage lain
+func uniqueStageSlug(ctx context.Context, stageRepo repositories.StageRepository, name string) (string, error) {
+	base := domainservices.Slugify(name)
+	slug := base
+	for i := 2; ; i++ {
+		existing, err := stageRepo.FindBySlug(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
 func (s *AdminService) DeleteStage(ctx context.Context, stageID string) error {
 	return s.stageRepo.Delete(ctx, stageID)
 }
 
-func (s *AdminService) GetAllStages(ctx context.Context) ([]*models.Stage, error) {
-	return s.stageRepo.FindAll(ctx)
+// CloneStage menyalin stage beserta semua phrase draft-nya menjadi stage
+// baru yang belum aktif dan belum dipublish, dalam satu transaksi. name
+// kosong berarti "<nama asal> (copy)" dan themeID kosong berarti theme
+// asal. multiplierScale > 0 mengalikan base multiplier setiap phrase
+// (dibulatkan 2 desimal, tetap harus lolos linter); 0 berarti tidak diubah.
+// Jadwal, prerequisite dan terjemahan tidak ikut disalin.
+func (s *AdminService) CloneStage(ctx context.Context, stageID, name, themeID string, multiplierScale float64) (*models.Stage, error) {
+	if multiplierScale < 0 {
+		return nil, ErrInvalidMultiplierScale
+	}
+	if multiplierScale == 0 {
+		multiplierScale = 1
+	}
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+
+	var clone *models.Stage
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		source, err := s.stageRepo.FindByID(ctx, stageID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return ErrStageNotFound
+		}
+		if themeID == "" {
+			themeID = source.ThemeID
+		} else if _, err := s.findTheme(ctx, themeID); err != nil {
+			return err
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = source.Name + " (copy)"
+		}
+
+		phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+		if err != nil {
+			return err
+		}
+		problems := &ValidationError{}
+		copies := make([]*models.Phrase, 0, len(phrases))
+		for i, phrase := range phrases {
+			copied := &models.Phrase{
+				Text:           phrase.Text,
+				SequenceNumber: phrase.SequenceNumber,
+				BaseMultiplier: math.Round(phrase.BaseMultiplier*multiplierScale*100) / 100,
+			}
+			problems.addPhraseIssues(fmt.Sprintf("phrases[%d].", i), "base_multiplier",
+				s.phraseLinter.Lint(copied.Text, copied.BaseMultiplier, source.PhraseCharset))
+			copies = append(copies, copied)
+		}
+		if err := problems.err(); err != nil {
+			return err
+		}
+
+		slug, err := uniqueStageSlug(ctx, s.stageRepo, name)
+		if err != nil {
+			return err
+		}
+		clone = &models.Stage{
+			Slug:       slug,
+			Name:       name,
+			ThemeID:    themeID,
+			Difficulty: source.Difficulty,
+			IsActive:   false,
+
+			PhraseCharset:    source.PhraseCharset,
+			PhrasePoolSize:   source.PhrasePoolSize,
+			WhitespacePolicy: source.WhitespacePolicy,
+			PhraseLanguage:   source.PhraseLanguage,
+		}
+		err = s.stageRepo.Create(ctx, clone)
+		if err == repositories.ErrDuplicate {
+			return ErrStageSlugExists
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, phrase := range copies {
+			phrase.StageID = clone.ID
+			if err := s.phraseRepo.Create(ctx, phrase); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// GetAllStages mengembalikan semua stage; phraseLanguage tidak kosong
+// hanya mengembalikan stage dengan bahasa phrase tersebut
+func (s *AdminService) GetAllStages(ctx context.Context, phraseLanguage string) ([]*models.Stage, error) {
+	phraseLanguage, err := normalizePhraseLanguage(phraseLanguage)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := s.stageRepo.FindAll(ctx)
+	if err != nil || phraseLanguage == "" {
+		return stages, err
+	}
+	return filterByPhraseLanguage(stages, phraseLanguage), nil
+}
+
+// Stage Prerequisites
+func (s *AdminService) GetStagePrerequisites(ctx context.Context, stageID string) ([]*models.StagePrerequisite, error) {
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+	return s.prerequisiteRepo.FindByStageID(ctx, stageID)
+}
+
+// SetStagePrerequisites mengganti semua prerequisite stage. Ditolak jika
+// stage prasyarat tidak ada atau perubahan membentuk siklus.
+func (s *AdminService) SetStagePrerequisites(ctx context.Context, stageID string, prerequisites []*models.StagePrerequisite) ([]*models.StagePrerequisite, error) {
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+
+	if err := domainservices.ValidatePrerequisites(stageID, prerequisites); err != nil {
+		return nil, err
+	}
+	for _, prerequisite := range prerequisites {
+		required, err := s.stageRepo.FindByID(ctx, prerequisite.RequiredStageID)
+		if err != nil {
+			return nil, err
+		}
+		if required == nil {
+			return nil, ErrRequiredStageNotFound
+		}
+	}
+
+	// Bangun graph dengan prerequisite baru untuk stage ini
+	existing, err := s.prerequisiteRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph := make(map[string][]string)
+	for _, prerequisite := range existing {
+		if prerequisite.StageID != stageID {
+			graph[prerequisite.StageID] = append(graph[prerequisite.StageID], prerequisite.RequiredStageID)
+		}
+	}
+	for _, prerequisite := range prerequisites {
+		graph[stageID] = append(graph[stageID], prerequisite.RequiredStageID)
+	}
+	if domainservices.HasPrerequisiteCycle(graph) {
+		return nil, domainservices.ErrPrerequisiteCycle
+	}
+
+	if err := s.prerequisiteRepo.ReplaceForStage(ctx, stageID, prerequisites); err != nil {
+		return nil, err
+	}
+	return s.prerequisiteRepo.FindByStageID(ctx, stageID)
 }
 
 // Phrase Management
-func (s *AdminService) CreatePhrase(ctx context.Context, stageID, text string, sequenceNumber int, baseMultiplier float64) (*models.Phrase, error) {
+
+// CreatePhrase menyimpan phrase baru setelah whitespace dinormalisasi dan
+// lolos linter; pelanggaran dikembalikan sebagai *ValidationError.
+// baseMultiplier nil berarti memakai saran PhraseAnalyzer.
+func (s *AdminService) CreatePhrase(ctx context.Context, stageID, text string, sequenceNumber int, baseMultiplier *float64) (*models.Phrase, error) {
+	stage, err := s.phraseStage(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
 	phrase := &models.Phrase{
 		StageID:        stageID,
-		Text:           text,
+		Text:           s.phraseLinter.NormalizeText(text),
 		SequenceNumber: sequenceNumber,
-		BaseMultiplier: baseMultiplier,
 	}
-	err := s.phraseRepo.Create(ctx, phrase)
+	if baseMultiplier != nil {
+		phrase.BaseMultiplier = math.Round(*baseMultiplier*100) / 100
+	} else {
+		phrase.BaseMultiplier = s.phraseAnalyzer.Analyze(phrase.Text, stage.WhitespacePolicy).SuggestedMultiplier
+	}
+	if err := s.lintPhrase(ctx, stage, phrase); err != nil {
+		return nil, err
+	}
+
+	err = s.phraseRepo.Create(ctx, phrase)
 	if err != nil {
 		return nil, err
 	}
@@ -94,18 +626,28 @@ func (s *AdminService) CreatePhrase(ctx context.Context, stageID, text string, s
 }
 
 func (s *AdminService) UpdatePhrase(ctx context.Context, phraseID, stageID, text string, sequenceNumber int, baseMultiplier float64) (*models.Phrase, error) {
+	if _, err := uuid.Parse(phraseID); err != nil {
+		return nil, ErrPhraseNotFound
+	}
 	phrase, err := s.phraseRepo.FindByID(ctx, phraseID)
 	if err != nil {
 		return nil, err
 	}
 	if phrase == nil {
-		return nil, errors.New("phrase not found")
+		return nil, ErrPhraseNotFound
+	}
+	stage, err := s.phraseStage(ctx, stageID)
+	if err != nil {
+		return nil, err
 	}
 
 	phrase.StageID = stageID
-	phrase.Text = text
+	phrase.Text = s.phraseLinter.NormalizeText(text)
 	phrase.SequenceNumber = sequenceNumber
-	phrase.BaseMultiplier = baseMultiplier
+	phrase.BaseMultiplier = math.Round(baseMultiplier*100) / 100
+	if err := s.lintPhrase(ctx, stage, phrase); err != nil {
+		return nil, err
+	}
 
 	err = s.phraseRepo.Update(ctx, phrase)
 	if err != nil {
@@ -114,6 +656,40 @@ func (s *AdminService) UpdatePhrase(ctx context.Context, phraseID, stageID, text
 	return phrase, nil
 }
 
+// phraseStage mencari stage tujuan phrase
+func (s *AdminService) phraseStage(ctx context.Context, stageID string) (*models.Stage, error) {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+	return stage, nil
+}
+
+// lintPhrase menjalankan linter dengan charset stage tujuan dan menolak
+// teks yang sudah dipakai phrase lain di stage yang sama
+func (s *AdminService) lintPhrase(ctx context.Context, stage *models.Stage, phrase *models.Phrase) error {
+	problems := &ValidationError{}
+	problems.addPhraseIssues("", "base_multiplier", s.phraseLinter.Lint(phrase.Text, phrase.BaseMultiplier, stage.PhraseCharset))
+
+	others, err := s.phraseRepo.FindByStageID(ctx, stage.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID != phrase.ID && other.Text == phrase.Text {
+			problems.add("text", "duplicates phrase #%d in this stage", other.SequenceNumber)
+			break
+		}
+	}
+	return problems.err()
+}
+
 func (s *AdminService) DeletePhrase(ctx context.Context, phraseID string) error {
 	return s.phraseRepo.Delete(ctx, phraseID)
 }
@@ -121,3 +697,223 @@ func (s *AdminService) DeletePhrase(ctx context.Context, phraseID string) error
 func (s *AdminService) GetPhrasesByStage(ctx context.Context, stageID string) ([]*models.Phrase, error) {
 	return s.phraseRepo.FindByStageID(ctx, stageID)
 }
+
+// ReorderPhrases mengurutkan ulang phrase stage menjadi sequence 1..n sesuai
+// urutan phraseIDs dalam satu transaksi. phraseIDs harus berisi semua
+// phrase milik stage tepat satu kali.
+func (s *AdminService) ReorderPhrases(ctx context.Context, stageID string, phraseIDs []string) ([]*models.Phrase, error) {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+
+	var phrases []*models.Phrase
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.phraseRepo.FindByStageID(ctx, stageID)
+		if err != nil {
+			return err
+		}
+		if !samePhraseSet(current, phraseIDs) {
+			return ErrPhraseOrderMismatch
+		}
+
+		err = s.phraseRepo.Resequence(ctx, stageID, phraseIDs)
+		if err == repositories.ErrDuplicate {
+			// Phrase baru ditambahkan bersamaan; urutan yang dikirim sudah basi
+			return ErrPhraseOrderMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		phrases, err = s.phraseRepo.FindByStageID(ctx, stageID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return phrases, nil
+}
+
+func samePhraseSet(phrases []*models.Phrase, phraseIDs []string) bool {
+	if len(phrases) != len(phraseIDs) {
+		return false
+	}
+	remaining := make(map[string]bool, len(phrases))
+	for _, phrase := range phrases {
+		remaining[phrase.ID] = true
+	}
+	for _, id := range phraseIDs {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// Phrase Analysis
+
+// AnalyzeStage menilai kompleksitas phrase draft stage dan menyarankan
+// multiplier tiap phrase serta difficulty stage
+func (s *AdminService) AnalyzeStage(ctx context.Context, stageID string) (*models.StageAnalysis, error) {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+	phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := s.phraseAnalyzer.AnalyzeStage(phrases, stage.WhitespacePolicy)
+	analysis.StageID = stage.ID
+	analysis.Difficulty = stage.Difficulty
+	return analysis, nil
+}
+
+// AnalyzePhrases menilai teks yang belum disimpan (mis. saat admin menyusun
+// stage baru). Teks dinormalisasi seperti saat disimpan; whitespacePolicy
+// kosong berarti strict.
+func (s *AdminService) AnalyzePhrases(texts []string, whitespacePolicy string) (*models.StageAnalysis, error) {
+	if whitespacePolicy == "" {
+		whitespacePolicy = domainservices.WhitespaceStrict
+	} else if !domainservices.ValidWhitespacePolicy(whitespacePolicy) {
+		return nil, ErrInvalidWhitespacePolicy
+	}
+	phrases := make([]*models.Phrase, 0, len(texts))
+	for _, text := range texts {
+		phrases = append(phrases, &models.Phrase{Text: s.phraseLinter.NormalizeText(text)})
+	}
+	return s.phraseAnalyzer.AnalyzeStage(phrases, whitespacePolicy), nil
+}
+
+// Stage Versions
+
+// PublishStage menyimpan draft stage (nama dan phrase) sebagai versi baru
+// yang immutable dan langsung disajikan ke pemain. Dengan resetLeaderboard,
+// leaderboard stage dimulai ulang dari versi ini; publish tanpa perubahan
+// hanya diizinkan jika disertai reset.
+func (s *AdminService) PublishStage(ctx context.Context, stageID, publishedBy string, resetLeaderboard bool) (*models.StageVersion, error) {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+
+	var version *models.StageVersion
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		stage, err := s.stageRepo.FindByID(ctx, stageID)
+		if err != nil {
+			return err
+		}
+		if stage == nil {
+			return ErrStageNotFound
+		}
+
+		phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+		if err != nil {
+			return err
+		}
+		if len(phrases) == 0 {
+			return ErrStageHasNoPhrases
+		}
+
+		if !resetLeaderboard {
+			published, err := s.versionRepo.FindPublished(ctx, stageID)
+			if err != nil {
+				return err
+			}
+			if !draftChanged(stage, phrases, published) {
+				return ErrNothingToPublish
+			}
+		}
+
+		version = &models.StageVersion{
+			StageID:          stageID,
+			Name:             stage.Name,
+			WhitespacePolicy: stage.WhitespacePolicy,
+			PhrasePoolSize:   stage.PhrasePoolSize,
+			PhraseLanguage:   stage.PhraseLanguage,
+			LeaderboardReset: resetLeaderboard,
+			PublishedBy:      publishedBy,
+		}
+		for _, phrase := range phrases {
+			version.Phrases = append(version.Phrases, &models.Phrase{
+				ID:             phrase.ID,
+				Text:           phrase.Text,
+				SequenceNumber: phrase.SequenceNumber,
+				BaseMultiplier: phrase.BaseMultiplier,
+			})
+		}
+		return s.versionRepo.Publish(ctx, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetStageVersions mengembalikan riwayat versi stage, terbaru lebih dulu
+func (s *AdminService) GetStageVersions(ctx context.Context, stageID string) (*models.StageVersionHistory, error) {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+
+	versions, err := s.versionRepo.FindByStageID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	published, err := s.versionRepo.FindPublished(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StageVersionHistory{
+		Versions:              versions,
+		HasUnpublishedChanges: draftChanged(stage, phrases, published),
+	}, nil
+}
+
+// draftChanged membandingkan draft dengan versi published. Kedua daftar
+// phrase sudah urut berdasarkan sequence_number.
+func draftChanged(stage *models.Stage, phrases []*models.Phrase, published *models.StageVersion) bool {
+	if published == nil {
+		return true
+	}
+	if stage.Name != published.Name || len(phrases) != len(published.Phrases) {
+		return true
+	}
+	if stage.WhitespacePolicy != published.WhitespacePolicy || stage.PhrasePoolSize != published.PhrasePoolSize ||
+		stage.PhraseLanguage != published.PhraseLanguage {
+		return true
+	}
+	for i, phrase := range phrases {
+		snapshot := published.Phrases[i]
+		if phrase.Text != snapshot.Text || phrase.BaseMultiplier != snapshot.BaseMultiplier {
+			return true
+		}
+	}
+	return false
+}
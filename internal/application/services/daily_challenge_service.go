@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDailyChallengeNotFound    = errors.New("daily challenge not found")
+	ErrDailyChallengeUnavailable = errors.New("no phrases available for daily challenge")
+	ErrInvalidChallengeDate      = errors.New("invalid challenge date")
+	ErrDailyTimeExceedsSession   = errors.New("total_time_ms is longer than the time since the session started")
+)
+
+// dailySessionClockSkew adalah toleransi pembulatan waktu client terhadap
+// waktu sejak sesi dimulai di server
+const dailySessionClockSkew = time.Second
+
+// DailyChallengeView adalah challenge beserta status attempt ranked pemain
+type DailyChallengeView struct {
+	Challenge *models.DailyChallenge
+	// IsToday: hanya challenge hari ini yang bisa dimainkan ranked
+	IsToday           bool
+	RankedAttemptUsed bool
+}
+
+// DailyChallengeResult adalah hasil submit attempt daily challenge. Rank
+// berisi posisi attempt ranked user (nil jika belum punya attempt ranked).
+type DailyChallengeResult struct {
+	Attempt *models.DailyChallengeAttempt
+	Rank    *models.LeaderboardEntry
+}
+
+type DailyChallengeService struct {
+	challengeRepo   repositories.DailyChallengeRepository
+	phraseRepo      repositories.PhraseRepository
+	transactor      repositories.Transactor
+	scoreCalculator *domainservices.ScoreCalculator
+	location        *time.Location
+	sessionTTL      time.Duration
+}
+
+// NewDailyChallengeService membuat service daily challenge. Pergantian hari
+// mengikuti location (timezone leaderboard); sessionTTL adalah umur sesi
+// sebelum harus disubmit.
+func NewDailyChallengeService(
+	challengeRepo repositories.DailyChallengeRepository,
+	phraseRepo repositories.PhraseRepository,
+	transactor repositories.Transactor,
+	location *time.Location,
+	sessionTTL time.Duration,
+) *DailyChallengeService {
+	return &DailyChallengeService{
+		challengeRepo:   challengeRepo,
+		phraseRepo:      phraseRepo,
+		transactor:      transactor,
+		scoreCalculator: domainservices.NewScoreCalculator(),
+		location:        location,
+		sessionTTL:      sessionTTL,
+	}
+}
+
+// GetChallenge mengembalikan challenge untuk date (YYYY-MM-DD, kosong =
+// hari ini). Challenge hari ini dibuat saat pertama kali diminta; challenge
+// lama hanya tersedia jika dulu pernah dibuat.
+func (s *DailyChallengeService) GetChallenge(ctx context.Context, userID, date string) (*DailyChallengeView, error) {
+	challenge, isToday, err := s.findChallenge(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.challengeRepo.HasRankedAttempt(ctx, challenge.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DailyChallengeView{
+		Challenge:         challenge,
+		IsToday:           isToday,
+		RankedAttemptUsed: used,
+	}, nil
+}
+
+// StartSession memulai run challenge pada date (kosong = hari ini), atau
+// memakai ulang sesi user yang belum disubmit dan belum kedaluwarsa untuk
+// challenge yang sama. Sesi challenge hari ini adalah sesi ranked.
+func (s *DailyChallengeService) StartSession(ctx context.Context, userID, date string) (*DailyChallengeView, *models.DailyChallengeSession, error) {
+	view, err := s.GetChallenge(ctx, userID, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session, err := s.challengeRepo.StartSession(ctx, &models.DailyChallengeSession{
+		ChallengeID: view.Challenge.ID,
+		UserID:      userID,
+		Ranked:      view.IsToday,
+		ExpiresAt:   now.Add(s.sessionTTL),
+	}, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return view, session, nil
+}
+
+// SubmitAttempt menyimpan attempt dari sesi yang dimulai lewat
+// StartSession. Challenge yang dinilai dan status ranked diambil dari sesi,
+// bukan dari tanggal saat submit, jadi run yang dimulai 23:59 tetap masuk
+// challenge hari itu. totalTimeMs tidak boleh melebihi waktu sejak sesi
+// dimulai di server. Hanya attempt ranked pertama user yang masuk
+// leaderboard; sisanya disimpan sebagai replay tanpa ranking.
+func (s *DailyChallengeService) SubmitAttempt(ctx context.Context, userID, sessionID string, totalTimeMs, totalErrors int) (*DailyChallengeResult, error) {
+	session, err := s.findSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if session.ExpiredAt(now) {
+		return nil, ErrGameSessionExpired
+	}
+	if time.Duration(totalTimeMs)*time.Millisecond > now.Sub(session.CreatedAt)+dailySessionClockSkew {
+		return nil, ErrDailyTimeExceedsSession
+	}
+
+	challenge, err := s.challengeRepo.FindByDate(ctx, session.ChallengeDate)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, ErrDailyChallengeNotFound
+	}
+
+	// Phrase challenge berasal dari banyak stage sehingga selalu diketik strict
+	score, err := calculateAttempt(s.scoreCalculator, challenge.Phrases, domainservices.WhitespaceStrict, totalTimeMs, totalErrors)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := &models.DailyChallengeAttempt{
+		ChallengeID: challenge.ID,
+		UserID:      userID,
+		FinalScore:  score.FinalScore,
+		TotalTimeMs: score.TotalTimeMs,
+		TotalErrors: score.TotalErrors,
+		TotalChars:  score.TotalChars,
+		Wpm:         score.Wpm,
+		Accuracy:    score.Accuracy,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		submitted, err := s.challengeRepo.MarkSessionSubmitted(ctx, session.ID, now)
+		if err != nil {
+			return err
+		}
+		if !submitted {
+			return ErrGameSessionSubmitted
+		}
+		return s.challengeRepo.CreateAttempt(ctx, attempt, session.Ranked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rank, err := s.challengeRepo.FindUserRank(ctx, challenge.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DailyChallengeResult{Attempt: attempt, Rank: rank}, nil
+}
+
+// findSession mengambil sesi daily challenge milik user yang belum disubmit
+func (s *DailyChallengeService) findSession(ctx context.Context, userID, sessionID string) (*models.DailyChallengeSession, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return nil, ErrGameSessionNotFound
+	}
+	session, err := s.challengeRepo.FindSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID {
+		return nil, ErrGameSessionNotFound
+	}
+	if session.SubmittedAt != nil {
+		return nil, ErrGameSessionSubmitted
+	}
+	return session, nil
+}
+
+// GetLeaderboard mengembalikan top attempt ranked challenge pada date
+func (s *DailyChallengeService) GetLeaderboard(ctx context.Context, date string, limit int) (*models.DailyChallenge, []*models.LeaderboardEntry, error) {
+	if limit < 1 || limit > MaxLeaderboardLimit {
+		return nil, nil, ErrInvalidLimit
+	}
+
+	challenge, _, err := s.findChallenge(ctx, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := s.challengeRepo.FindLeaderboard(ctx, challenge.ID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return challenge, entries, nil
+}
+
+func (s *DailyChallengeService) findChallenge(ctx context.Context, date string) (*models.DailyChallenge, bool, error) {
+	now := time.Now().In(s.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	day := today
+	if date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, false, ErrInvalidChallengeDate
+		}
+		day = parsed
+	}
+	if day.After(today) {
+		return nil, false, ErrDailyChallengeNotFound
+	}
+	isToday := day.Equal(today)
+
+	challenge, err := s.challengeRepo.FindByDate(ctx, day)
+	if err != nil {
+		return nil, false, err
+	}
+	if challenge != nil {
+		return challenge, isToday, nil
+	}
+	if !isToday {
+		return nil, false, ErrDailyChallengeNotFound
+	}
+
+	challenge, err = s.generate(ctx, day)
+	if err != nil {
+		return nil, false, err
+	}
+	return challenge, true, nil
+}
+
+// generate menyusun challenge untuk day lalu menyimpannya. Jika instance
+// lain lebih dulu menyimpan, challenge yang tersimpan yang dikembalikan.
+func (s *DailyChallengeService) generate(ctx context.Context, day time.Time) (*models.DailyChallenge, error) {
+	pool, err := s.phraseRepo.FindFromActiveStages(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seed := domainservices.DailySeed(day)
+	selected := domainservices.SelectDailyPhrases(pool, seed, domainservices.DailyChallengePhraseCount)
+	if len(selected) == 0 {
+		return nil, ErrDailyChallengeUnavailable
+	}
+
+	challenge := &models.DailyChallenge{Date: day, Seed: seed}
+	for i, phrase := range selected {
+		challenge.Phrases = append(challenge.Phrases, &models.Phrase{
+			ID:             phrase.ID,
+			StageID:        phrase.StageID,
+			Text:           phrase.Text,
+			SequenceNumber: i + 1,
+			BaseMultiplier: phrase.BaseMultiplier,
+		})
+	}
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.challengeRepo.FindByDate(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		return nil, ErrDailyChallengeNotFound
+	}
+	return saved, nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type memoryDailyRepo struct {
+	challenges map[string]*models.DailyChallenge // per tanggal YYYY-MM-DD
+	sessions   map[string]*models.DailyChallengeSession
+	attempts   []*models.DailyChallengeAttempt
+}
+
+func newMemoryDailyRepo(days ...time.Time) *memoryDailyRepo {
+	repo := &memoryDailyRepo{
+		challenges: map[string]*models.DailyChallenge{},
+		sessions:   map[string]*models.DailyChallengeSession{},
+	}
+	for _, day := range days {
+		repo.challenges[day.Format("2006-01-02")] = &models.DailyChallenge{
+			ID:   uuid.NewString(),
+			Date: day,
+			Phrases: []*models.Phrase{
+				{Text: "the quick brown fox", SequenceNumber: 1, BaseMultiplier: 1},
+				{Text: "jumps over the lazy dog", SequenceNumber: 2, BaseMultiplier: 1},
+			},
+		}
+	}
+	return repo
+}
+
+func (r *memoryDailyRepo) challenge(day time.Time) *models.DailyChallenge {
+	return r.challenges[day.Format("2006-01-02")]
+}
+
+func (r *memoryDailyRepo) FindByDate(ctx context.Context, date time.Time) (*models.DailyChallenge, error) {
+	return r.challenge(date), nil
+}
+
+func (r *memoryDailyRepo) Create(ctx context.Context, challenge *models.DailyChallenge) error {
+	r.challenges[challenge.Date.Format("2006-01-02")] = challenge
+	return nil
+}
+
+func (r *memoryDailyRepo) CreateAttempt(ctx context.Context, attempt *models.DailyChallengeAttempt, ranked bool) error {
+	if ranked {
+		used, _ := r.HasRankedAttempt(ctx, attempt.ChallengeID, attempt.UserID)
+		ranked = !used
+	}
+	attempt.ID = int64(len(r.attempts) + 1)
+	attempt.Ranked = ranked
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *memoryDailyRepo) HasRankedAttempt(ctx context.Context, challengeID, userID string) (bool, error) {
+	for _, attempt := range r.attempts {
+		if attempt.ChallengeID == challengeID && attempt.UserID == userID && attempt.Ranked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryDailyRepo) FindLeaderboard(ctx context.Context, challengeID string, limit int) ([]*models.LeaderboardEntry, error) {
+	return nil, nil
+}
+
+func (r *memoryDailyRepo) FindUserRank(ctx context.Context, challengeID, userID string) (*models.LeaderboardEntry, error) {
+	return nil, nil
+}
+
+func (r *memoryDailyRepo) StartSession(ctx context.Context, session *models.DailyChallengeSession, now time.Time) (*models.DailyChallengeSession, error) {
+	for id, open := range r.sessions {
+		if open.UserID != session.UserID || open.ChallengeID != session.ChallengeID || open.SubmittedAt != nil {
+			continue
+		}
+		if !open.ExpiredAt(now) {
+			return r.FindSession(ctx, id)
+		}
+		delete(r.sessions, id)
+	}
+	started := *session
+	started.ID = uuid.NewString()
+	started.CreatedAt = now
+	r.sessions[started.ID] = &started
+	return r.FindSession(ctx, started.ID)
+}
+
+func (r *memoryDailyRepo) FindSession(ctx context.Context, sessionID string) (*models.DailyChallengeSession, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	for _, challenge := range r.challenges {
+		if challenge.ID == session.ChallengeID {
+			copied.ChallengeDate = challenge.Date
+		}
+	}
+	return &copied, nil
+}
+
+func (r *memoryDailyRepo) MarkSessionSubmitted(ctx context.Context, sessionID string, submittedAt time.Time) (bool, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok || session.SubmittedAt != nil {
+		return false, nil
+	}
+	session.SubmittedAt = &submittedAt
+	return true, nil
+}
+
+// backdate memindahkan waktu mulai sesi ke masa lalu, seolah pemain sudah
+// mengetik selama elapsed
+func (r *memoryDailyRepo) backdate(sessionID string, elapsed time.Duration) {
+	session := r.sessions[sessionID]
+	session.CreatedAt = session.CreatedAt.Add(-elapsed)
+}
+
+func utcToday() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func TestDailySessionStartedBeforeMidnightScoresThatDay(t *testing.T) {
+	ctx := context.Background()
+	today := utcToday()
+	yesterday := today.AddDate(0, 0, -1)
+	repo := newMemoryDailyRepo(yesterday, today)
+	service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+	// Run dimulai 23:59 kemarin (sesi ranked untuk challenge kemarin) dan
+	// disubmit setelah tengah malam
+	now := time.Now()
+	started := &models.DailyChallengeSession{
+		ID:          uuid.NewString(),
+		ChallengeID: repo.challenge(yesterday).ID,
+		UserID:      testUserID,
+		Ranked:      true,
+		CreatedAt:   now.Add(-90 * time.Second),
+		ExpiresAt:   now.Add(28 * time.Minute),
+	}
+	repo.sessions[started.ID] = started
+
+	result, err := service.SubmitAttempt(ctx, testUserID, started.ID, 80000, 2)
+	if err != nil {
+		t.Fatalf("SubmitAttempt: %v", err)
+	}
+	if result.Attempt.ChallengeID != repo.challenge(yesterday).ID {
+		t.Errorf("attempt scored against %s, want yesterday's challenge %s", result.Attempt.ChallengeID, repo.challenge(yesterday).ID)
+	}
+	if !result.Attempt.Ranked {
+		t.Error("attempt from a session started on the challenge day was not ranked")
+	}
+	if used, _ := repo.HasRankedAttempt(ctx, repo.challenge(today).ID, testUserID); used {
+		t.Error("today's ranked attempt was used by yesterday's run")
+	}
+}
+
+func TestDailySessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	today := utcToday()
+	yesterday := today.AddDate(0, 0, -1)
+
+	t.Run("restart reuses the open session", func(t *testing.T) {
+		repo := newMemoryDailyRepo(today)
+		service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+		_, first, err := service.StartSession(ctx, testUserID, "")
+		if err != nil {
+			t.Fatalf("StartSession: %v", err)
+		}
+		_, again, err := service.StartSession(ctx, testUserID, "")
+		if err != nil {
+			t.Fatalf("StartSession again: %v", err)
+		}
+		if again.ID != first.ID || !again.CreatedAt.Equal(first.CreatedAt) {
+			t.Errorf("restart gave session %s started %v, want %s started %v", again.ID, again.CreatedAt, first.ID, first.CreatedAt)
+		}
+		if !first.Ranked || !first.ChallengeDate.Equal(today) {
+			t.Errorf("today's session ranked=%v date=%v, want ranked for %v", first.Ranked, first.ChallengeDate, today)
+		}
+	})
+
+	t.Run("replay of an old challenge is unranked", func(t *testing.T) {
+		repo := newMemoryDailyRepo(yesterday, today)
+		service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+		_, session, err := service.StartSession(ctx, testUserID, yesterday.Format("2006-01-02"))
+		if err != nil {
+			t.Fatalf("StartSession: %v", err)
+		}
+		repo.backdate(session.ID, time.Minute)
+		result, err := service.SubmitAttempt(ctx, testUserID, session.ID, 50000, 0)
+		if err != nil {
+			t.Fatalf("SubmitAttempt: %v", err)
+		}
+		if session.Ranked || result.Attempt.Ranked {
+			t.Errorf("replay session ranked=%v attempt ranked=%v, want both unranked", session.Ranked, result.Attempt.Ranked)
+		}
+	})
+
+	t.Run("time longer than the session is rejected", func(t *testing.T) {
+		repo := newMemoryDailyRepo(today)
+		service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+		_, session, err := service.StartSession(ctx, testUserID, "")
+		if err != nil {
+			t.Fatalf("StartSession: %v", err)
+		}
+		repo.backdate(session.ID, 20*time.Second)
+		if _, err := service.SubmitAttempt(ctx, testUserID, session.ID, 45000, 0); err != ErrDailyTimeExceedsSession {
+			t.Errorf("45s claimed after a 20s session: err = %v, want ErrDailyTimeExceedsSession", err)
+		}
+		if len(repo.attempts) != 0 || repo.sessions[session.ID].SubmittedAt != nil {
+			t.Error("rejected submit stored an attempt or consumed the session")
+		}
+		if _, err := service.SubmitAttempt(ctx, testUserID, session.ID, 19000, 0); err != nil {
+			t.Errorf("19s claimed after a 20s session: %v", err)
+		}
+	})
+
+	t.Run("session is single use", func(t *testing.T) {
+		repo := newMemoryDailyRepo(today)
+		service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+		_, session, err := service.StartSession(ctx, testUserID, "")
+		if err != nil {
+			t.Fatalf("StartSession: %v", err)
+		}
+		repo.backdate(session.ID, time.Minute)
+		if _, err := service.SubmitAttempt(ctx, testUserID, session.ID, 30000, 0); err != nil {
+			t.Fatalf("first submit: %v", err)
+		}
+		if _, err := service.SubmitAttempt(ctx, testUserID, session.ID, 30000, 0); err != ErrGameSessionSubmitted {
+			t.Errorf("second submit err = %v, want ErrGameSessionSubmitted", err)
+		}
+		if len(repo.attempts) != 1 {
+			t.Errorf("attempts stored = %d, want 1", len(repo.attempts))
+		}
+	})
+
+	t.Run("expired or foreign sessions are rejected", func(t *testing.T) {
+		repo := newMemoryDailyRepo(today)
+		service := NewDailyChallengeService(repo, nil, inlineTransactor{}, time.UTC, 30*time.Minute)
+
+		_, session, err := service.StartSession(ctx, testUserID, "")
+		if err != nil {
+			t.Fatalf("StartSession: %v", err)
+		}
+		if _, err := service.SubmitAttempt(ctx, uuid.NewString(), session.ID, 1000, 0); err != ErrGameSessionNotFound {
+			t.Errorf("other user's session err = %v, want ErrGameSessionNotFound", err)
+		}
+		repo.sessions[session.ID].ExpiresAt = time.Now().Add(-time.Second)
+		if _, err := service.SubmitAttempt(ctx, testUserID, session.ID, 1000, 0); err != ErrGameSessionExpired {
+			t.Errorf("expired session err = %v, want ErrGameSessionExpired", err)
+		}
+	})
+}
@@ -3,41 +3,298 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
 
 	"uwika_quick_typer_game/internal/domain/models"
 	"uwika_quick_typer_game/internal/domain/repositories"
 	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
 )
 
 var (
 	ErrStageNotFound = errors.New("stage not found")
+	ErrStageLocked   = errors.New("stage is locked")
+	// ErrStageNotAvailable: di luar jadwal available_from/available_until
+	ErrStageNotAvailable = errors.New("stage is not available")
+	// ErrStageVersionOutdated: attempt tanpa sesi hanya dinilai atas versi
+	// published saat ini
+	ErrStageVersionOutdated = errors.New("stage has a newer published version; reload the stage")
+	// ErrGameSessionNotFound: sesi tidak ada atau bukan milik user/stage tersebut
+	ErrGameSessionNotFound  = errors.New("game session not found")
+	ErrGameSessionSubmitted = errors.New("game session was already submitted")
+	ErrGameSessionExpired   = errors.New("game session has expired; start a new session")
+	ErrGameSessionRequired  = errors.New("session_id is required for this stage")
 )
 
 type GameService struct {
-	stageRepo       repositories.StageRepository
-	phraseRepo      repositories.PhraseRepository
-	scoreRepo       repositories.ScoreRepository
-	scoreCalculator *domainservices.ScoreCalculator
+	stageRepo        repositories.StageRepository
+	versionRepo      repositories.StageVersionRepository
+	sessionRepo      repositories.GameSessionRepository
+	scoreRepo        repositories.ScoreRepository
+	keyStatsRepo     repositories.KeyStatsRepository
+	prerequisiteRepo repositories.StagePrerequisiteRepository
+	transactor       repositories.Transactor
+	progress         *ProgressService
+	achievements     *AchievementService
+	scoreCalculator  *domainservices.ScoreCalculator
+	progression      *domainservices.StageProgression
+	closeGracePeriod time.Duration
+	sessionTTL       time.Duration
+}
+
+// SubmitResult adalah hasil submit score beserta XP/streak dan achievement
+// yang baru didapat dari attempt tersebut
+type SubmitResult struct {
+	Score           *models.Score
+	Status          string
+	Progress        *AttemptProgress
+	NewAchievements []*models.Achievement
 }
 
 func NewGameService(
 	stageRepo repositories.StageRepository,
-	phraseRepo repositories.PhraseRepository,
+	versionRepo repositories.StageVersionRepository,
+	sessionRepo repositories.GameSessionRepository,
 	scoreRepo repositories.ScoreRepository,
+	keyStatsRepo repositories.KeyStatsRepository,
+	prerequisiteRepo repositories.StagePrerequisiteRepository,
+	transactor repositories.Transactor,
+	progress *ProgressService,
+	achievements *AchievementService,
+	closeGracePeriod time.Duration,
+	sessionTTL time.Duration,
 ) *GameService {
+	scoreCalculator := domainservices.NewScoreCalculator()
 	return &GameService{
-		stageRepo:       stageRepo,
-		phraseRepo:      phraseRepo,
-		scoreRepo:       scoreRepo,
-		scoreCalculator: domainservices.NewScoreCalculator(),
+		stageRepo:        stageRepo,
+		versionRepo:      versionRepo,
+		sessionRepo:      sessionRepo,
+		scoreRepo:        scoreRepo,
+		keyStatsRepo:     keyStatsRepo,
+		prerequisiteRepo: prerequisiteRepo,
+		transactor:       transactor,
+		progress:         progress,
+		achievements:     achievements,
+		scoreCalculator:  scoreCalculator,
+		progression:      domainservices.NewStageProgression(scoreCalculator),
+		closeGracePeriod: closeGracePeriod,
+		sessionTTL:       sessionTTL,
+	}
+}
+
+// GetStagesForUser mengembalikan semua stage aktif yang sudah dipublish dan
+// belum tutup, beserta status terkunci dan progres prerequisite user. Stage
+// yang belum buka ikut dikembalikan dalam keadaan terkunci supaya pemain
+// bisa melihat countdown-nya. phraseLanguage tidak kosong hanya
+// mengembalikan stage dengan bahasa phrase tersebut.
+func (s *GameService) GetStagesForUser(ctx context.Context, userID, phraseLanguage string) ([]*models.StageProgress, error) {
+	phraseLanguage, err := normalizePhraseLanguage(phraseLanguage)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := s.stageRepo.FindAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.versionRepo.FindAllPublished(ctx)
+	if err != nil {
+		return nil, err
+	}
+	published := make(map[string]*models.StageVersion, len(versions))
+	for _, version := range versions {
+		published[version.StageID] = version
+	}
+	prerequisites, err := s.prerequisiteRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byStage := make(map[string][]*models.StagePrerequisite)
+	for _, prerequisite := range prerequisites {
+		byStage[prerequisite.StageID] = append(byStage[prerequisite.StageID], prerequisite)
+	}
+
+	bestAccuracy, err := s.scoreRepo.FindBestAccuracyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	progress := make([]*models.StageProgress, 0, len(stages))
+	for _, stage := range stages {
+		version, ok := published[stage.ID]
+		if !ok || (stage.AvailableUntil != nil && !now.Before(*stage.AvailableUntil)) {
+			continue
+		}
+		served := playerStage(stage, version)
+		if phraseLanguage != "" && served.PhraseLanguage != phraseLanguage {
+			continue
+		}
+		locked, requirements := s.progression.Evaluate(byStage[stage.ID], bestAccuracy)
+		if !stage.AvailableAt(now) {
+			// Belum buka: tampil terkunci, main dan submit tetap ditolak
+			locked = true
+		}
+		progress = append(progress, &models.StageProgress{
+			Stage:        served,
+			Locked:       locked,
+			Requirements: requirements,
+		})
+	}
+	return progress, nil
+}
+
+// GetStageForPlayer sama seperti GetPublishedStage, tetapi menolak stage
+// di luar jadwalnya atau yang masih terkunci untuk user. Untuk stage dengan
+// phrase pool, versi yang dikembalikan tidak berisi phrase; phrase baru
+// diundi saat sesi dimulai lewat StartSession.
+func (s *GameService) GetStageForPlayer(ctx context.Context, userID, stageID string) (*models.Stage, *models.StageVersion, error) {
+	stage, version, err := s.playableStage(ctx, userID, stageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stage.PhrasePoolSize > 0 {
+		served := *version
+		served.Phrases = nil
+		return stage, &served, nil
+	}
+	return stage, version, nil
+}
+
+// StartSession memulai sesi permainan untuk versi published stage, atau
+// memakai ulang sesi user yang belum disubmit dan belum kedaluwarsa untuk
+// versi yang sama, sehingga mengulang request tidak mengundi ulang phrase
+// pool. Versi yang dikembalikan hanya berisi phrase sesi (sequence 1..n
+// sesuai urutan tampil).
+func (s *GameService) StartSession(ctx context.Context, userID, stageID string) (*models.Stage, *models.StageVersion, *models.GameSession, error) {
+	stage, version, err := s.playableStage(ctx, userID, stageID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	candidate := &models.GameSession{
+		UserID:         userID,
+		StageID:        stageID,
+		StageVersionID: version.ID,
+		ExpiresAt:      now.Add(s.sessionTTL),
+	}
+	if stage.PhrasePoolSize > 0 {
+		candidate.Seed = rand.Int63()
+		for _, phrase := range domainservices.DrawPhrasePool(version.Phrases, candidate.Seed, stage.PhrasePoolSize) {
+			candidate.PhraseIDs = append(candidate.PhraseIDs, phrase.ID)
+		}
+	} else {
+		for _, phrase := range version.Phrases {
+			candidate.PhraseIDs = append(candidate.PhraseIDs, phrase.ID)
+		}
+	}
+
+	session, err := s.sessionRepo.Start(ctx, candidate, now)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	served, err := sessionVersion(version, session)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return stage, served, session, nil
+}
+
+// playableStage mengembalikan stage published yang sedang dalam jadwalnya
+// dan sudah terbuka untuk user
+func (s *GameService) playableStage(ctx context.Context, userID, stageID string) (*models.Stage, *models.StageVersion, error) {
+	stage, version, err := s.GetPublishedStage(ctx, stageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !stage.AvailableAt(time.Now()) {
+		return nil, nil, ErrStageNotAvailable
+	}
+	if err := s.checkUnlocked(ctx, userID, stageID); err != nil {
+		return nil, nil, err
+	}
+	return stage, version, nil
+}
+
+// findSession mengambil sesi milik user untuk stage tersebut yang belum
+// disubmit
+func (s *GameService) findSession(ctx context.Context, userID, stageID, sessionID string) (*models.GameSession, error) {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return nil, ErrGameSessionNotFound
+	}
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || session.StageID != stageID {
+		return nil, ErrGameSessionNotFound
+	}
+	if session.SubmittedAt != nil {
+		return nil, ErrGameSessionSubmitted
+	}
+	return session, nil
+}
+
+// sessionVersion mengembalikan salinan versi yang hanya berisi phrase sesi,
+// dengan sequence_number 1..n sesuai urutan tampil
+func sessionVersion(version *models.StageVersion, session *models.GameSession) (*models.StageVersion, error) {
+	byID := make(map[string]*models.Phrase, len(version.Phrases))
+	for _, phrase := range version.Phrases {
+		byID[phrase.ID] = phrase
+	}
+
+	served := *version
+	served.Phrases = make([]*models.Phrase, 0, len(session.PhraseIDs))
+	for i, phraseID := range session.PhraseIDs {
+		phrase, ok := byID[phraseID]
+		if !ok {
+			return nil, ErrGameSessionNotFound
+		}
+		copied := *phrase
+		copied.SequenceNumber = i + 1
+		served.Phrases = append(served.Phrases, &copied)
 	}
+	served.PhraseCount = len(served.Phrases)
+	return &served, nil
 }
 
-func (s *GameService) GetActiveStages(ctx context.Context) ([]*models.Stage, error) {
-	return s.stageRepo.FindAllActive(ctx)
+// checkSubmitWindow menerima attempt yang dimulai di dalam jadwal stage.
+// Attempt yang dimulai sebelum stage tutup masih diterima sampai
+// closeGracePeriod setelah available_until.
+func (s *GameService) checkSubmitWindow(stage *models.Stage, startedAt, now time.Time) error {
+	if !stage.AvailableAt(startedAt) {
+		return ErrStageNotAvailable
+	}
+	if stage.AvailableUntil != nil && now.After(stage.AvailableUntil.Add(s.closeGracePeriod)) {
+		return ErrStageNotAvailable
+	}
+	return nil
+}
+
+func (s *GameService) checkUnlocked(ctx context.Context, userID, stageID string) error {
+	prerequisites, err := s.prerequisiteRepo.FindByStageID(ctx, stageID)
+	if err != nil || len(prerequisites) == 0 {
+		return err
+	}
+
+	bestAccuracy, err := s.scoreRepo.FindBestAccuracyByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if locked, _ := s.progression.Evaluate(prerequisites, bestAccuracy); locked {
+		return ErrStageLocked
+	}
+	return nil
 }
 
-func (s *GameService) GetStageWithPhrases(ctx context.Context, stageID string) (*models.Stage, []*models.Phrase, error) {
+// GetPublishedStage mengembalikan stage beserta versi published terbarunya.
+// Stage yang belum pernah dipublish dianggap tidak ada bagi pemain.
+func (s *GameService) GetPublishedStage(ctx context.Context, stageID string) (*models.Stage, *models.StageVersion, error) {
 	stage, err := s.stageRepo.FindByID(ctx, stageID)
 	if err != nil {
 		return nil, nil, err
@@ -46,30 +303,149 @@ func (s *GameService) GetStageWithPhrases(ctx context.Context, stageID string) (
 		return nil, nil, ErrStageNotFound
 	}
 
-	phrases, err := s.phraseRepo.FindByStageID(ctx, stageID)
+	version, err := s.versionRepo.FindPublished(ctx, stageID)
 	if err != nil {
 		return nil, nil, err
 	}
+	if version == nil {
+		return nil, nil, ErrStageNotFound
+	}
+
+	return playerStage(stage, version), version, nil
+}
 
-	return stage, phrases, nil
+// playerStage adalah stage seperti yang dilihat pemain: field yang ikut
+// dipublish diambil dari snapshot versi, bukan dari draft yang sedang diedit
+func playerStage(stage *models.Stage, version *models.StageVersion) *models.Stage {
+	served := *stage
+	served.Name = version.Name
+	served.WhitespacePolicy = version.WhitespacePolicy
+	served.PhrasePoolSize = version.PhrasePoolSize
+	served.PhraseLanguage = version.PhraseLanguage
+	return &served
 }
 
-// SubmitScore - calculation dilakukan di domain service
-func (s *GameService) SubmitScore(ctx context.Context, userID, stageID string, totalTimeMs, totalErrors int) (*models.Score, string, error) {
-	// Get stage and phrases
-	stage, phrases, err := s.GetStageWithPhrases(ctx, stageID)
+// SubmitScore - calculation dilakukan di domain service. latencies dan
+// mistakes (opsional) adalah jeda setiap keystroke dan karakter yang salah
+// diketik, untuk statistik heatmap keyboard.
+// stageVersionID (opsional) adalah versi yang dimainkan; tanpa sesi hanya
+// versi published saat ini yang diterima (ErrStageVersionOutdated), supaya
+// versi lama yang lebih mudah tidak bisa terus dipakai untuk ranking.
+// Dengan sessionID, score dihitung atas versi dan phrase yang disajikan di
+// sesi itu; sesi wajib untuk stage dengan phrase pool, hanya bisa disubmit
+// sekali dan ditolak setelah kedaluwarsa (ErrGameSessionExpired).
+func (s *GameService) SubmitScore(ctx context.Context, userID, stageID, stageVersionID, sessionID string, totalTimeMs, totalErrors int, latencies []models.TypingLatency, mistakes []models.TypingMistake) (*SubmitResult, error) {
+	// Get stage and phrases (stage di luar jadwal atau terkunci ditolak)
+	stage, version, err := s.GetPublishedStage(ctx, stageID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	if stage == nil {
-		return nil, "", ErrStageNotFound
+
+	// Waktu mulai diambil dari sesi, atau diperkirakan dari totalTimeMs
+	now := time.Now()
+	startedAt := now.Add(-time.Duration(totalTimeMs) * time.Millisecond)
+	var session *models.GameSession
+	if sessionID != "" {
+		session, err = s.findSession(ctx, userID, stageID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.ExpiredAt(now) {
+			return nil, ErrGameSessionExpired
+		}
+		startedAt = session.CreatedAt
+	} else if stage.PhrasePoolSize > 0 {
+		return nil, ErrGameSessionRequired
 	}
 
+	if err := s.checkSubmitWindow(stage, startedAt, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkUnlocked(ctx, userID, stageID); err != nil {
+		return nil, err
+	}
+	if session != nil && session.StageVersionID != version.ID {
+		// Sesi dimulai sebelum publish terbaru: nilai dengan versi sesi
+		version, err = s.versionRepo.FindByID(ctx, session.StageVersionID)
+		if err != nil {
+			return nil, err
+		}
+		if version == nil {
+			return nil, ErrGameSessionNotFound
+		}
+	} else if session == nil && stageVersionID != "" && stageVersionID != version.ID {
+		return nil, ErrStageVersionOutdated
+	}
+	if session != nil {
+		version, err = sessionVersion(version, session)
+		if err != nil {
+			return nil, err
+		}
+	}
+	phrases := version.Phrases
+
+	// Statistik heatmap opsional: event yang tidak cocok diabaikan, tidak
+	// menggagalkan submit
+	keystrokes := domainservices.AggregateKeystrokes(phrases, version.WhitespacePolicy, latencies, mistakes)
+	if keystrokes.Dropped > 0 {
+		log.Printf("Ignored %d invalid keystroke events from user %s on stage %s", keystrokes.Dropped, userID, stageID)
+	}
+
+	score, err := calculateAttempt(s.scoreCalculator, phrases, version.WhitespacePolicy, totalTimeMs, totalErrors)
+	if err != nil {
+		return nil, err
+	}
+	score.UserID = userID
+	score.StageID = stageID
+	score.StageVersionID = version.ID
+
+	// Allow multiple attempts - always insert. XP, streak dan statistik
+	// heatmap diperbarui dalam transaksi yang sama dengan insert score.
+	result := &SubmitResult{Score: score, Status: "INSERTED"}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if session != nil {
+			submitted, err := s.sessionRepo.MarkSubmitted(ctx, session.ID, now)
+			if err != nil {
+				return err
+			}
+			if !submitted {
+				return ErrGameSessionSubmitted
+			}
+		}
+		if err := s.scoreRepo.Create(ctx, score); err != nil {
+			return err
+		}
+		if err := s.keyStatsRepo.Record(ctx, userID, keyStatList(keystrokes.Keys), keyStatList(keystrokes.Bigrams), substitutionList(keystrokes.Substitutions)); err != nil {
+			return err
+		}
+		progress, err := s.progress.ApplyAttempt(ctx, score, stage)
+		result.Progress = progress
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Score sudah tersimpan; gagal evaluasi achievement tidak menggagalkan
+	// submit, badge yang terlewat akan diberikan pada attempt berikutnya
+	// yang memenuhi rule
+	result.NewAchievements, err = s.achievements.EvaluateAfterScore(ctx, score, stage)
+	if err != nil {
+		log.Printf("Failed to evaluate achievements for user %s: %v", userID, err)
+	}
+
+	return result, nil
+}
+
+// calculateAttempt menghitung metrik dan final score satu attempt atas
+// phrases yang dimainkan. Jumlah karakter adalah karakter yang diketik
+// sesuai whitespace policy. UserID/StageID diisi oleh pemanggil.
+func calculateAttempt(calculator *domainservices.ScoreCalculator, phrases []*models.Phrase, whitespacePolicy string, totalTimeMs, totalErrors int) (*models.Score, error) {
 	// Calculate metrics for domain service
 	totalChars := 0
 	totalMultiplier := 0.0
 	for _, phrase := range phrases {
-		totalChars += len(phrase.Text)
+		totalChars += domainservices.TypedLength(phrase.Text, whitespacePolicy)
 		totalMultiplier += phrase.BaseMultiplier
 	}
 
@@ -105,33 +481,42 @@ func (s *GameService) SubmitScore(ctx context.Context, userID, stageID string, t
 	}
 
 	// Validate metrics
-	if err := s.scoreCalculator.ValidateMetrics(accuracy, typingSpeed, timeTakenSeconds); err != nil {
-		return nil, "", err
+	if err := calculator.ValidateMetrics(accuracy, typingSpeed, timeTakenSeconds); err != nil {
+		return nil, err
 	}
 
 	// Calculate final score using domain service
-	calcResult := s.scoreCalculator.CalculateScore(calcInput)
+	calcResult := calculator.CalculateScore(calcInput)
 
-	score := &models.Score{
-		UserID:      userID,
-		StageID:     stageID,
+	return &models.Score{
 		FinalScore:  float64(calcResult.FinalScore),
 		TotalTimeMs: totalTimeMs,
 		TotalErrors: totalErrors,
-	}
+		TotalChars:  totalChars,
+		Wpm:         math.Round(typingSpeed*100) / 100,
+		Accuracy:    math.Round(accuracy*100) / 100,
+	}, nil
+}
 
-	// Allow multiple attempts - always insert
-	err = s.scoreRepo.Create(ctx, score)
-	if err != nil {
-		return nil, "", err
+func keyStatList(stats map[string]*models.KeyStat) []*models.KeyStat {
+	list := make([]*models.KeyStat, 0, len(stats))
+	for _, stat := range stats {
+		list = append(list, stat)
 	}
-
-	return score, "INSERTED", nil
+	// Urutan tetap supaya upsert paralel tidak saling deadlock
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
 }
 
-func (s *GameService) GetLeaderboard(ctx context.Context, stageID string, limit int) ([]*models.Score, error) {
-	if limit <= 0 {
-		limit = 20
+func substitutionList(substitutions map[string]*models.KeySubstitution) []*models.KeySubstitution {
+	keys := make([]string, 0, len(substitutions))
+	for key := range substitutions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	list := make([]*models.KeySubstitution, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, substitutions[key])
 	}
-	return s.scoreRepo.FindLeaderboardByStage(ctx, stageID, limit)
+	return list
 }
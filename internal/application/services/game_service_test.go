@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Fake repository in-memory. Interface di-embed supaya method yang tidak
+// dipakai test ini tetap memenuhi interface (panic jika ternyata dipanggil).
+
+type fakeStageRepo struct {
+	repositories.StageRepository
+	stages map[string]*models.Stage
+}
+
+func (r *fakeStageRepo) FindByID(ctx context.Context, id string) (*models.Stage, error) {
+	stage, ok := r.stages[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *stage
+	return &copied, nil
+}
+
+type fakeVersionRepo struct {
+	repositories.StageVersionRepository
+	versions  map[string]*models.StageVersion
+	published map[string]string // stage id -> version id
+}
+
+func (r *fakeVersionRepo) FindPublished(ctx context.Context, stageID string) (*models.StageVersion, error) {
+	return r.FindByID(ctx, r.published[stageID])
+}
+
+func (r *fakeVersionRepo) FindByID(ctx context.Context, id string) (*models.StageVersion, error) {
+	version, ok := r.versions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *version
+	return &copied, nil
+}
+
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*models.GameSession
+	// staleReads membuat FindByID mengembalikan sesi seperti saat dimulai,
+	// meniru submit lain yang commit setelah sesi dibaca
+	staleReads bool
+}
+
+func (r *fakeSessionRepo) Start(ctx context.Context, session *models.GameSession, now time.Time) (*models.GameSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	started := *session
+	started.ID = uuid.NewString()
+	started.CreatedAt = now
+	r.sessions[started.ID] = &started
+	copied := started
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) FindByID(ctx context.Context, id string) (*models.GameSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	if r.staleReads {
+		copied.SubmittedAt = nil
+	}
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) MarkSubmitted(ctx context.Context, id string, submittedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.SubmittedAt != nil {
+		return false, nil
+	}
+	session.SubmittedAt = &submittedAt
+	return true, nil
+}
+
+type fakeScoreRepo struct {
+	repositories.ScoreRepository
+	created []*models.Score
+}
+
+func (r *fakeScoreRepo) Create(ctx context.Context, score *models.Score) error {
+	score.ID = int64(len(r.created) + 1)
+	r.created = append(r.created, score)
+	return nil
+}
+
+type fakeKeyStatsRepo struct {
+	repositories.KeyStatsRepository
+}
+
+func (fakeKeyStatsRepo) Record(ctx context.Context, userID string, keys, bigrams []*models.KeyStat, substitutions []*models.KeySubstitution) error {
+	return nil
+}
+
+type fakePrerequisiteRepo struct {
+	repositories.StagePrerequisiteRepository
+}
+
+func (fakePrerequisiteRepo) FindByStageID(ctx context.Context, stageID string) ([]*models.StagePrerequisite, error) {
+	return nil, nil
+}
+
+type fakeProgressRepo struct {
+	progress map[string]*models.UserProgress
+}
+
+func (r *fakeProgressRepo) FindByUserID(ctx context.Context, userID string) (*models.UserProgress, error) {
+	return r.progress[userID], nil
+}
+
+func (r *fakeProgressRepo) FindByUserIDForUpdate(ctx context.Context, userID string) (*models.UserProgress, error) {
+	if _, ok := r.progress[userID]; !ok {
+		r.progress[userID] = &models.UserProgress{UserID: userID}
+	}
+	return r.progress[userID], nil
+}
+
+func (r *fakeProgressRepo) Save(ctx context.Context, progress *models.UserProgress) error {
+	r.progress[progress.UserID] = progress
+	return nil
+}
+
+type fakeUserRepo struct {
+	repositories.UserRepository
+}
+
+func (fakeUserRepo) FindByID(ctx context.Context, id string) (*models.User, error) {
+	return nil, nil
+}
+
+type fakeAchievementRepo struct {
+	repositories.AchievementRepository
+}
+
+func (fakeAchievementRepo) FindActive(ctx context.Context) ([]*models.Achievement, error) {
+	return nil, nil
+}
+
+func (fakeAchievementRepo) FindByUser(ctx context.Context, userID string) ([]*models.UserAchievement, error) {
+	return nil, nil
+}
+
+// inlineTransactor menjalankan fn langsung; fake di atas tidak punya rollback
+type inlineTransactor struct{}
+
+func (inlineTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+const (
+	testUserID  = "2d7e3b1a-4c5f-4e6a-9b8c-7d6e5f4a3b2c"
+	testStageID = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+)
+
+type gameFixture struct {
+	service  *GameService
+	sessions *fakeSessionRepo
+	scores   *fakeScoreRepo
+}
+
+func newGameFixture(t *testing.T, sessionTTL time.Duration) *gameFixture {
+	t.Helper()
+	stage := &models.Stage{
+		ID:                 testStageID,
+		Name:               "Draft name",
+		IsActive:           true,
+		PublishedVersionID: "v1",
+		WhitespacePolicy:   "strict",
+	}
+	version := &models.StageVersion{
+		ID:               "v1",
+		StageID:          testStageID,
+		VersionNumber:    1,
+		Name:             "Home row",
+		WhitespacePolicy: "strict",
+		Phrases: []*models.Phrase{
+			{ID: "p1", Text: "asdf jkl", SequenceNumber: 1, BaseMultiplier: 1},
+			{ID: "p2", Text: "fall sad lad", SequenceNumber: 2, BaseMultiplier: 1},
+		},
+	}
+
+	f := &gameFixture{
+		sessions: &fakeSessionRepo{sessions: map[string]*models.GameSession{}},
+		scores:   &fakeScoreRepo{},
+	}
+	progress := NewProgressService(&fakeProgressRepo{progress: map[string]*models.UserProgress{}}, fakeUserRepo{}, time.UTC)
+	f.service = NewGameService(
+		&fakeStageRepo{stages: map[string]*models.Stage{testStageID: stage}},
+		&fakeVersionRepo{
+			versions:  map[string]*models.StageVersion{"v1": version},
+			published: map[string]string{testStageID: "v1"},
+		},
+		f.sessions,
+		f.scores,
+		fakeKeyStatsRepo{},
+		fakePrerequisiteRepo{},
+		inlineTransactor{},
+		progress,
+		NewAchievementService(fakeAchievementRepo{}, f.scores, progress),
+		time.Minute,
+		sessionTTL,
+	)
+	return f
+}
+
+func (f *gameFixture) submit(sessionID string) (*SubmitResult, error) {
+	return f.service.SubmitScore(context.Background(), testUserID, testStageID, "", sessionID, 6000, 1, nil, nil)
+}
+
+func TestSubmitScoreSessionIsSingleUse(t *testing.T) {
+	f := newGameFixture(t, 30*time.Minute)
+	_, _, session, err := f.service.StartSession(context.Background(), testUserID, testStageID)
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+
+	result, err := f.submit(session.ID)
+	if err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+	if result.Score.StageVersionID != "v1" || result.Progress == nil || result.Progress.XPGained <= 0 {
+		t.Errorf("first submit result = %+v, progress %+v", result.Score, result.Progress)
+	}
+
+	if _, err := f.submit(session.ID); err != ErrGameSessionSubmitted {
+		t.Errorf("second submit err = %v, want ErrGameSessionSubmitted", err)
+	}
+	if len(f.scores.created) != 1 {
+		t.Errorf("scores stored = %d, want 1", len(f.scores.created))
+	}
+}
+
+func TestSubmitScoreConcurrentSubmitLosesAtMarkSubmitted(t *testing.T) {
+	f := newGameFixture(t, 30*time.Minute)
+	_, _, session, err := f.service.StartSession(context.Background(), testUserID, testStageID)
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	if _, err := f.submit(session.ID); err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+
+	// Submit kedua sudah membaca sesi sebelum submit pertama commit; hanya
+	// MarkSubmitted di dalam transaksi yang bisa menolaknya
+	f.sessions.staleReads = true
+	if _, err := f.submit(session.ID); err != ErrGameSessionSubmitted {
+		t.Errorf("racing submit err = %v, want ErrGameSessionSubmitted", err)
+	}
+	if len(f.scores.created) != 1 {
+		t.Errorf("scores stored = %d, want 1", len(f.scores.created))
+	}
+}
+
+func TestSubmitScoreRejectsExpiredSession(t *testing.T) {
+	f := newGameFixture(t, 30*time.Minute)
+	_, _, session, err := f.service.StartSession(context.Background(), testUserID, testStageID)
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	if got := session.ExpiresAt.Sub(session.CreatedAt); got != 30*time.Minute {
+		t.Errorf("session lifetime = %v, want the 30m TTL", got)
+	}
+
+	// Sesi dimulai 31 menit lalu
+	stored := f.sessions.sessions[session.ID]
+	stored.CreatedAt = stored.CreatedAt.Add(-31 * time.Minute)
+	stored.ExpiresAt = stored.ExpiresAt.Add(-31 * time.Minute)
+
+	if _, err := f.submit(session.ID); err != ErrGameSessionExpired {
+		t.Fatalf("submit err = %v, want ErrGameSessionExpired", err)
+	}
+	if len(f.scores.created) != 0 {
+		t.Errorf("scores stored = %d, want 0", len(f.scores.created))
+	}
+	if stored.SubmittedAt != nil {
+		t.Error("expired session was marked submitted")
+	}
+}
+
+func TestSubmitScoreUnknownSession(t *testing.T) {
+	f := newGameFixture(t, 30*time.Minute)
+	for _, sessionID := range []string{"not-a-uuid", uuid.NewString()} {
+		if _, err := f.submit(sessionID); err != ErrGameSessionNotFound {
+			t.Errorf("submit(%q) err = %v, want ErrGameSessionNotFound", sessionID, err)
+		}
+	}
+}
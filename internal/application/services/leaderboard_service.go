@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPeriod      = errors.New("invalid leaderboard period")
+	ErrInvalidAggregation = errors.New("invalid aggregation method")
+	ErrInvalidDifficulty  = errors.New("invalid difficulty")
+	ErrThemeNotFound      = errors.New("theme not found")
+	ErrInvalidLimit       = errors.New("invalid limit")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrNoActiveSeason     = errors.New("no active season")
+)
+
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+	DefaultAroundRadius     = 5
+	MaxAroundRadius         = 25
+)
+
+// LeaderboardPage adalah satu halaman leaderboard. NextCursor kosong jika
+// sudah halaman terakhir.
+type LeaderboardPage struct {
+	Entries    []*models.LeaderboardEntry
+	NextCursor string
+	Limit      int
+	MaxLimit   int
+}
+
+type LeaderboardService struct {
+	scoreRepo  repositories.ScoreRepository
+	stageRepo  repositories.StageRepository
+	themeRepo  repositories.ThemeRepository
+	seasonRepo repositories.SeasonRepository
+	location   *time.Location
+}
+
+// NewLeaderboardService membuat service leaderboard. location dipakai untuk
+// menentukan batas hari/minggu/bulan (kelas berjalan di Asia/Jakarta).
+func NewLeaderboardService(
+	scoreRepo repositories.ScoreRepository,
+	stageRepo repositories.StageRepository,
+	themeRepo repositories.ThemeRepository,
+	seasonRepo repositories.SeasonRepository,
+	location *time.Location,
+) *LeaderboardService {
+	return &LeaderboardService{
+		scoreRepo:  scoreRepo,
+		stageRepo:  stageRepo,
+		themeRepo:  themeRepo,
+		seasonRepo: seasonRepo,
+		location:   location,
+	}
+}
+
+// ResolvePeriod mengembalikan period yang dipakai jika client tidak
+// mengirim period: season selama ada season aktif (board direset setiap
+// season baru), selain itu all_time. Period yang dikirim dikembalikan apa
+// adanya.
+func (s *LeaderboardService) ResolvePeriod(ctx context.Context, period string) (string, error) {
+	if period != "" {
+		return period, nil
+	}
+	season, err := s.seasonRepo.FindActive(ctx)
+	if err != nil {
+		return "", err
+	}
+	if season != nil {
+		return string(models.PeriodSeason), nil
+	}
+	return string(models.PeriodAllTime), nil
+}
+
+// GetLeaderboard mengembalikan satu halaman leaderboard stage. cursor adalah
+// NextCursor dari halaman sebelumnya (kosong untuk halaman pertama).
+func (s *LeaderboardService) GetLeaderboard(ctx context.Context, stageID, period, cursor string, limit int) (*LeaderboardPage, error) {
+	if limit < 1 || limit > MaxLeaderboardLimit {
+		return nil, ErrInvalidLimit
+	}
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+
+	window, err := s.window(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	board := newLeaderboardCursor(stage, period, window)
+
+	var after *models.LeaderboardCursor
+	if cursor != "" {
+		after, err = board.decode(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Ambil satu entry lebih untuk tahu apakah masih ada halaman berikutnya
+	entries, err := s.scoreRepo.FindLeaderboardByStage(ctx, repositories.LeaderboardQuery{
+		StageID: stageID,
+		Window:  window,
+		After:   after,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LeaderboardPage{Limit: limit, MaxLimit: MaxLeaderboardLimit}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		page.NextCursor = board.encode(&models.LeaderboardCursor{
+			FinalScore:  last.FinalScore,
+			TotalTimeMs: last.TotalTimeMs,
+			UserID:      last.UserID,
+		})
+	}
+	page.Entries = entries
+	return page, nil
+}
+
+// GetLeaderboardAroundUser mengembalikan radius entry di atas dan di bawah
+// posisi user. Hasilnya kosong jika user belum punya score di window tersebut.
+func (s *LeaderboardService) GetLeaderboardAroundUser(ctx context.Context, stageID, period, userID string, radius int) ([]*models.LeaderboardEntry, error) {
+	if radius < 1 || radius > MaxAroundRadius {
+		return nil, ErrInvalidLimit
+	}
+	if _, err := uuid.Parse(stageID); err != nil {
+		return nil, ErrStageNotFound
+	}
+
+	window, err := s.window(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreRepo.FindLeaderboardAroundUser(ctx, stageID, window, userID, radius)
+}
+
+// GetAggregateLeaderboard menggabungkan best score pemain di semua stage aktif,
+// atau hanya stage dalam satu theme dan/atau satu tingkat difficulty.
+func (s *LeaderboardService) GetAggregateLeaderboard(ctx context.Context, themeID, difficulty, method, period string, limit int) ([]*models.AggregateLeaderboardEntry, error) {
+	if limit < 1 || limit > MaxLeaderboardLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	m := models.AggregationNormalizedSum
+	if method != "" {
+		m = models.AggregationMethod(method)
+	}
+	if !m.IsValid() {
+		return nil, ErrInvalidAggregation
+	}
+
+	if difficulty != "" && !isValidDifficulty(difficulty) {
+		return nil, ErrInvalidDifficulty
+	}
+
+	if themeID != "" {
+		if _, err := uuid.Parse(themeID); err != nil {
+			return nil, ErrThemeNotFound
+		}
+		theme, err := s.themeRepo.FindByID(ctx, themeID)
+		if err != nil {
+			return nil, err
+		}
+		if theme == nil {
+			return nil, ErrThemeNotFound
+		}
+	}
+
+	window, err := s.window(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scoreRepo.FindAggregateLeaderboard(ctx, repositories.AggregateLeaderboardFilter{
+		ThemeID:    themeID,
+		Difficulty: difficulty,
+		Method:     m,
+		Window:     window,
+		Limit:      limit,
+	})
+}
+
+// window mem-parse period (default all_time) menjadi batas attempt yang
+// dihitung: awal window di timezone leaderboard, atau season yang aktif
+func (s *LeaderboardService) window(ctx context.Context, period string) (models.ScoreWindow, error) {
+	p := models.PeriodAllTime
+	if period != "" {
+		p = models.LeaderboardPeriod(period)
+	}
+	if !p.IsValid() {
+		return models.ScoreWindow{}, ErrInvalidPeriod
+	}
+
+	if p == models.PeriodSeason {
+		season, err := s.seasonRepo.FindActive(ctx)
+		if err != nil {
+			return models.ScoreWindow{}, err
+		}
+		if season == nil {
+			return models.ScoreWindow{}, ErrNoActiveSeason
+		}
+		return models.ScoreWindow{SeasonID: season.ID}, nil
+	}
+
+	return models.ScoreWindow{Since: p.WindowStart(time.Now(), s.location)}, nil
+}
+
+func isValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard:
+		return true
+	}
+	return false
+}
+
+// leaderboardCursor adalah isi cursor opaque. Selain posisi keyset, cursor
+// menyimpan identitas board (stage, period, awal window, season dan
+// leaderboard_min_version stage) agar cursor dari board lain ditolak,
+// termasuk board yang sama setelah season berganti, window bergeser ke
+// hari/minggu/bulan berikutnya atau leaderboard di-reset lewat publish.
+type leaderboardCursor struct {
+	StageID     string  `json:"st"`
+	Period      string  `json:"p"`
+	Since       int64   `json:"w,omitempty"`
+	SeasonID    string  `json:"sn,omitempty"`
+	MinVersion  int     `json:"v"`
+	FinalScore  float64 `json:"s"`
+	TotalTimeMs int     `json:"t"`
+	UserID      string  `json:"u"`
+}
+
+// newLeaderboardCursor mengisi identitas board yang sedang dibaca
+func newLeaderboardCursor(stage *models.Stage, period string, window models.ScoreWindow) leaderboardCursor {
+	board := leaderboardCursor{
+		StageID:    stage.ID,
+		Period:     period,
+		SeasonID:   window.SeasonID,
+		MinVersion: stage.LeaderboardMinVersion,
+	}
+	if !window.Since.IsZero() {
+		board.Since = window.Since.Unix()
+	}
+	return board
+}
+
+func (board leaderboardCursor) encode(c *models.LeaderboardCursor) string {
+	board.FinalScore = c.FinalScore
+	board.TotalTimeMs = c.TotalTimeMs
+	board.UserID = c.UserID
+	data, _ := json.Marshal(board)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (board leaderboardCursor) decode(cursor string) (*models.LeaderboardCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c leaderboardCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.StageID != board.StageID || c.Period != board.Period || c.Since != board.Since ||
+		c.SeasonID != board.SeasonID || c.MinVersion != board.MinVersion {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(c.UserID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &models.LeaderboardCursor{
+		FinalScore:  c.FinalScore,
+		TotalTimeMs: c.TotalTimeMs,
+		UserID:      c.UserID,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+func TestLeaderboardCursorBoundToBoard(t *testing.T) {
+	stage := &models.Stage{ID: "6f1c1f4e-8a53-4d55-9a39-2b7f9a0c4e11", LeaderboardMinVersion: 2}
+	seasonN := models.ScoreWindow{SeasonID: "0c5e8f4a-1d2b-4c3a-9e8f-7a6b5c4d3e2f"}
+	position := &models.LeaderboardCursor{FinalScore: 250.75, TotalTimeMs: 12000, UserID: "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"}
+
+	cursor := newLeaderboardCursor(stage, "season", seasonN).encode(position)
+
+	after, err := newLeaderboardCursor(stage, "season", seasonN).decode(cursor)
+	if err != nil {
+		t.Fatalf("decode on the same board: %v", err)
+	}
+	if *after != *position {
+		t.Errorf("decode = %+v, want %+v", *after, *position)
+	}
+
+	otherStage := *stage
+	otherStage.ID = "1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e"
+	reset := *stage
+	reset.LeaderboardMinVersion = 3
+	seasonN1 := models.ScoreWindow{SeasonID: "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b"}
+	jakarta := time.FixedZone("WIB", 7*3600)
+
+	stale := map[string]leaderboardCursor{
+		"next season":        newLeaderboardCursor(stage, "season", seasonN1),
+		"leaderboard reset":  newLeaderboardCursor(&reset, "season", seasonN),
+		"other stage":        newLeaderboardCursor(&otherStage, "season", seasonN),
+		"other period":       newLeaderboardCursor(stage, "all_time", models.ScoreWindow{}),
+		"season ended (all)": newLeaderboardCursor(stage, "season", models.ScoreWindow{}),
+	}
+	for name, board := range stale {
+		if _, err := board.decode(cursor); err != ErrInvalidCursor {
+			t.Errorf("%s: decode err = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+
+	today := models.ScoreWindow{Since: time.Date(2026, 10, 19, 0, 0, 0, 0, jakarta)}
+	tomorrow := models.ScoreWindow{Since: today.Since.AddDate(0, 0, 1)}
+	daily := newLeaderboardCursor(stage, "daily", today).encode(position)
+	if _, err := newLeaderboardCursor(stage, "daily", today).decode(daily); err != nil {
+		t.Errorf("daily cursor on the same day: %v", err)
+	}
+	if _, err := newLeaderboardCursor(stage, "daily", tomorrow).decode(daily); err != ErrInvalidCursor {
+		t.Errorf("daily cursor after midnight: err = %v, want ErrInvalidCursor", err)
+	}
+
+	for _, garbage := range []string{"not-base64!", "e30", "bnVsbA"} {
+		if _, err := newLeaderboardCursor(stage, "season", seasonN).decode(garbage); err != ErrInvalidCursor {
+			t.Errorf("decode(%q) err = %v, want ErrInvalidCursor", garbage, err)
+		}
+	}
+}
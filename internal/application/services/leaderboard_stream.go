@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+// StreamTopN adalah jumlah entry teratas yang dipantau oleh leaderboard stream
+const StreamTopN = 10
+
+// LeaderboardEvent adalah snapshot top-N leaderboard default sebuah stage
+// (season aktif, atau all-time jika tidak ada season).
+// ID adalah score_id terbesar di antara entry top-N: naik setiap kali score
+// baru masuk top-N, dipakai untuk membuang snapshot yang basi. Reset berarti
+// top-N bisa berubah tanpa ID naik (leaderboard di-reset atau score dihapus),
+// sehingga client harus mengganti seluruh board-nya.
+type LeaderboardEvent struct {
+	ID            int64
+	StageID       string
+	Entries       []*models.LeaderboardEntry
+	PreviousRanks map[string]int // user_id -> rank di event sebelumnya
+	Reset         bool
+}
+
+// EventID adalah SSE event id / Last-Event-ID: sidik jari isi top-N yang
+// sama di semua instance API. Berbeda dengan ID, nilainya juga berubah jika
+// top-N berubah karena reset atau penghapusan score.
+func (e *LeaderboardEvent) EventID() string {
+	var sum int64
+	for _, entry := range e.Entries {
+		sum += entry.ScoreID
+	}
+	return fmt.Sprintf("%d-%d-%d", e.ID, len(e.Entries), sum)
+}
+
+// LeaderboardStream menyebarkan perubahan top-N leaderboard ke subscriber
+// (koneksi SSE) di instance ini. Perubahan datang dari Postgres NOTIFY,
+// sehingga score yang disimpan instance lain juga ikut tersebar.
+type LeaderboardStream struct {
+	scoreRepo  repositories.ScoreRepository
+	seasonRepo repositories.SeasonRepository
+
+	mu     sync.Mutex
+	stages map[string]*streamStage
+}
+
+type streamStage struct {
+	subscribers map[chan *LeaderboardEvent]struct{}
+	last        *LeaderboardEvent
+}
+
+func NewLeaderboardStream(scoreRepo repositories.ScoreRepository, seasonRepo repositories.SeasonRepository) *LeaderboardStream {
+	return &LeaderboardStream{
+		scoreRepo:  scoreRepo,
+		seasonRepo: seasonRepo,
+		stages:     make(map[string]*streamStage),
+	}
+}
+
+// Subscribe mendaftarkan subscriber untuk stage. Channel hanya menyimpan event
+// terbaru; subscriber yang lambat akan melewatkan snapshot di tengah.
+func (s *LeaderboardStream) Subscribe(stageID string) (<-chan *LeaderboardEvent, func()) {
+	ch := make(chan *LeaderboardEvent, 1)
+
+	s.mu.Lock()
+	stage, ok := s.stages[stageID]
+	if !ok {
+		stage = &streamStage{subscribers: make(map[chan *LeaderboardEvent]struct{})}
+		s.stages[stageID] = stage
+	}
+	stage.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(stage.subscribers, ch)
+		if len(stage.subscribers) == 0 && s.stages[stageID] == stage {
+			delete(s.stages, stageID)
+		}
+	}
+	return ch, cancel
+}
+
+// Snapshot mengambil top-N leaderboard stage saat ini
+func (s *LeaderboardStream) Snapshot(ctx context.Context, stageID string) (*LeaderboardEvent, error) {
+	event, err := s.load(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Simpan sebagai pembanding untuk menghitung perubahan rank berikutnya
+	s.mu.Lock()
+	if stage, ok := s.stages[stageID]; ok && stage.last == nil {
+		stage.last = event
+	}
+	s.mu.Unlock()
+
+	return event, nil
+}
+
+// Publish dipanggil setiap ada best score baru di stage. Snapshot baru hanya
+// dikirim ke subscriber jika top-N berubah.
+func (s *LeaderboardStream) Publish(ctx context.Context, stageID string) error {
+	return s.publish(ctx, stageID, false)
+}
+
+func (s *LeaderboardStream) publish(ctx context.Context, stageID string, reset bool) error {
+	if !s.hasSubscribers(stageID) {
+		return nil
+	}
+
+	event, err := s.load(ctx, stageID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stage, ok := s.stages[stageID]
+	if !ok {
+		return nil
+	}
+	if !reset && stage.last != nil && event.ID <= stage.last.ID {
+		return nil
+	}
+	event.Reset = reset
+
+	event.PreviousRanks = make(map[string]int)
+	if stage.last != nil && !reset {
+		for _, entry := range stage.last.Entries {
+			event.PreviousRanks[entry.UserID] = entry.Rank
+		}
+	}
+	stage.last = event
+
+	for ch := range stage.subscribers {
+		// Buang event lama yang belum dibaca, snapshot terbaru sudah lengkap
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- event
+	}
+	return nil
+}
+
+// Reset mengirim snapshot stage yang leaderboard-nya di-reset atau yang
+// score-nya dihapus. ID event bisa turun, jadi snapshot selalu dikirim
+// sebagai event reset tanpa dibandingkan dengan snapshot terakhir.
+func (s *LeaderboardStream) Reset(ctx context.Context, stageID string) error {
+	return s.publish(ctx, stageID, true)
+}
+
+// Resync mengirim ulang snapshot untuk semua stage yang punya subscriber,
+// dipakai setelah listener NOTIFY tersambung ulang dan saat season dimulai
+// atau ditutup
+func (s *LeaderboardStream) Resync(ctx context.Context) {
+	s.mu.Lock()
+	stageIDs := make([]string, 0, len(s.stages))
+	for stageID := range s.stages {
+		stageIDs = append(stageIDs, stageID)
+	}
+	s.mu.Unlock()
+
+	// Notifikasi yang terlewat bisa berupa reset, jadi kirim sebagai reset
+	for _, stageID := range stageIDs {
+		s.Reset(ctx, stageID)
+	}
+}
+
+func (s *LeaderboardStream) load(ctx context.Context, stageID string) (*LeaderboardEvent, error) {
+	// Sama dengan board default GET /api/leaderboard
+	var window models.ScoreWindow
+	season, err := s.seasonRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if season != nil {
+		window.SeasonID = season.ID
+	}
+
+	entries, err := s.scoreRepo.FindLeaderboardByStage(ctx, repositories.LeaderboardQuery{
+		StageID: stageID,
+		Window:  window,
+		Limit:   StreamTopN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := &LeaderboardEvent{StageID: stageID, Entries: entries}
+	for _, entry := range entries {
+		if entry.ScoreID > event.ID {
+			event.ID = entry.ScoreID
+		}
+	}
+	return event, nil
+}
+
+func (s *LeaderboardStream) hasSubscribers(stageID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.stages[stageID]
+	return ok
+}
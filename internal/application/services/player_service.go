@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidDateRange   = errors.New("invalid date range")
+	ErrPlayerNotFound     = errors.New("player not found")
+	ErrInvalidVisibility  = errors.New("invalid profile visibility")
+	ErrInvalidDisplayName = errors.New("display name must be at most 50 characters")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+)
+
+const (
+	DefaultStatsDays = 90
+	MaxStatsDays     = 365
+
+	// Bigram baru dianggap bermakna setelah diketik beberapa kali
+	HeatmapBigramMinPresses  = 5
+	HeatmapBigramLimit       = 20
+	HeatmapSubstitutionLimit = 20
+
+	ProfileRecentActivityLimit = 5
+	ProfileTopPlacementMaxRank = 10
+	ProfileTopPlacementLimit   = 5
+	MaxDisplayNameLength       = 50
+)
+
+// ScoreHistoryPage adalah satu halaman riwayat attempt user. NextCursor kosong
+// jika sudah halaman terakhir.
+type ScoreHistoryPage struct {
+	Attempts   []*models.Attempt
+	NextCursor string
+	Limit      int
+	MaxLimit   int
+}
+
+// ScoreHistoryFilter membatasi riwayat attempt. From dan To berupa tanggal
+// (YYYY-MM-DD, di timezone leaderboard, To inklusif) atau RFC3339.
+type ScoreHistoryFilter struct {
+	StageID string
+	From    string
+	To      string
+	Cursor  string
+	Limit   int
+}
+
+// KeyHeatmap adalah statistik error per tombol keyboard (karakter dengan
+// dan tanpa shift digabung), bigram yang paling sering salah serta pasangan
+// karakter expected/typed yang paling sering tertukar
+type KeyHeatmap struct {
+	Layout        string
+	Keys          []*HeatmapKey
+	Bigrams       []*models.KeyStat
+	Substitutions []*models.KeySubstitution
+}
+
+type HeatmapKey struct {
+	Position domainservices.KeyPosition
+	Stat     models.KeyStat
+}
+
+// PlayerProfile adalah profil publik pemain beserta XP, level, dan streak
+type PlayerProfile struct {
+	*models.PlayerProfile
+	Progress *PlayerProgress
+}
+
+// PlayerSettingsUpdate berisi pengaturan yang ingin diubah; field nil tidak diubah
+type PlayerSettingsUpdate struct {
+	DisplayName          *string
+	ProfileVisibility    *string
+	LeaderboardAnonymous *bool
+	Timezone             *string // IANA timezone, string kosong = default server
+}
+
+type PlayerService struct {
+	userRepo     repositories.UserRepository
+	scoreRepo    repositories.ScoreRepository
+	keyStatsRepo repositories.KeyStatsRepository
+	progress     *ProgressService
+	location     *time.Location
+}
+
+func NewPlayerService(
+	userRepo repositories.UserRepository,
+	scoreRepo repositories.ScoreRepository,
+	keyStatsRepo repositories.KeyStatsRepository,
+	progress *ProgressService,
+	location *time.Location,
+) *PlayerService {
+	return &PlayerService{
+		userRepo:     userRepo,
+		scoreRepo:    scoreRepo,
+		keyStatsRepo: keyStatsRepo,
+		progress:     progress,
+		location:     location,
+	}
+}
+
+// GetPublicProfile mengembalikan profil publik pemain. Profil private hanya
+// bisa dilihat pemiliknya; pemain lain mendapat ErrPlayerNotFound.
+func (s *PlayerService) GetPublicProfile(ctx context.Context, viewerID, username string) (*PlayerProfile, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	isOwner := user != nil && user.ID == viewerID
+	if user == nil || (!user.IsProfilePublic() && !isOwner) {
+		return nil, ErrPlayerNotFound
+	}
+
+	summary, err := s.scoreRepo.FindPlayerSummary(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.progress.GetProgress(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &PlayerProfile{
+		PlayerProfile: &models.PlayerProfile{User: user, Summary: summary},
+		Progress:      progress,
+	}
+
+	// Rank maupun stage + score attempt terakhir di profil bisa dicocokkan
+	// dengan entry "Anonymous" di leaderboard, sehingga hanya ditampilkan
+	// untuk pemain yang tidak anonim (atau pemilik profil sendiri)
+	if user.LeaderboardAnonymous && !isOwner {
+		return profile, nil
+	}
+	profile.RecentAttempts, err = s.scoreRepo.FindByUserID(ctx, repositories.ScoreHistoryQuery{
+		UserID: user.ID,
+		Limit:  ProfileRecentActivityLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profile.TopPlacements, err = s.scoreRepo.FindTopPlacements(ctx, user.ID, ProfileTopPlacementMaxRank, ProfileTopPlacementLimit)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *PlayerService) GetSettings(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrPlayerNotFound
+	}
+	return user, nil
+}
+
+// UpdateSettings mengubah display name dan pengaturan privasi user. Perubahan
+// anonim di leaderboard yang sudah di-cache terlihat paling lambat setelah
+// TTL cache leaderboard.
+func (s *PlayerService) UpdateSettings(ctx context.Context, userID string, update PlayerSettingsUpdate) (*models.User, error) {
+	user, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.DisplayName != nil {
+		name := strings.TrimSpace(*update.DisplayName)
+		if utf8.RuneCountInString(name) > MaxDisplayNameLength {
+			return nil, ErrInvalidDisplayName
+		}
+		user.DisplayName = name
+	}
+	if update.ProfileVisibility != nil {
+		switch *update.ProfileVisibility {
+		case models.ProfileVisibilityPublic, models.ProfileVisibilityPrivate:
+			user.ProfileVisibility = *update.ProfileVisibility
+		default:
+			return nil, ErrInvalidVisibility
+		}
+	}
+	if update.LeaderboardAnonymous != nil {
+		user.LeaderboardAnonymous = *update.LeaderboardAnonymous
+	}
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if timezone != "" {
+			// "Local" bergantung pada server, bukan timezone pemain
+			if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+				return nil, ErrInvalidTimezone
+			}
+		}
+		user.Timezone = timezone
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetScoreHistory mengembalikan riwayat attempt user, terbaru lebih dulu
+func (s *PlayerService) GetScoreHistory(ctx context.Context, userID string, filter ScoreHistoryFilter) (*ScoreHistoryPage, error) {
+	if filter.Limit < 1 || filter.Limit > MaxLeaderboardLimit {
+		return nil, ErrInvalidLimit
+	}
+	if filter.StageID != "" {
+		if _, err := uuid.Parse(filter.StageID); err != nil {
+			return nil, ErrStageNotFound
+		}
+	}
+
+	from, err := s.parseBoundary(filter.From, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.parseBoundary(filter.To, true)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	var after *models.AttemptCursor
+	if filter.Cursor != "" {
+		after, err = decodeAttemptCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Ambil satu attempt lebih untuk tahu apakah masih ada halaman berikutnya
+	attempts, err := s.scoreRepo.FindByUserID(ctx, repositories.ScoreHistoryQuery{
+		UserID:  userID,
+		StageID: filter.StageID,
+		From:    from,
+		To:      to,
+		After:   after,
+		Limit:   filter.Limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ScoreHistoryPage{Limit: filter.Limit, MaxLimit: MaxLeaderboardLimit}
+	if len(attempts) > filter.Limit {
+		attempts = attempts[:filter.Limit]
+		last := attempts[len(attempts)-1]
+		page.NextCursor = encodeAttemptCursor(&models.AttemptCursor{
+			CompletedAt: last.CompletedAt,
+			ScoreID:     last.ID,
+		})
+	}
+	page.Attempts = attempts
+	return page, nil
+}
+
+// GetStageBests mengembalikan best attempt user di setiap stage yang pernah
+// dimainkan, terakhir dimainkan lebih dulu
+func (s *PlayerService) GetStageBests(ctx context.Context, userID string) ([]*models.StageBest, error) {
+	return s.scoreRepo.FindBestsByUser(ctx, userID)
+}
+
+// GetStats mengagregasi attempt user selama days hari terakhir (termasuk hari
+// ini, di timezone leaderboard) menjadi bucket harian dan mingguan
+func (s *PlayerService) GetStats(ctx context.Context, userID string, days int) (*models.PlayerStats, error) {
+	if days < 1 || days > MaxStatsDays {
+		return nil, ErrInvalidLimit
+	}
+
+	now := time.Now().In(s.location)
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location).AddDate(0, 0, -(days - 1))
+
+	return s.scoreRepo.FindPlayerStats(ctx, repositories.PlayerStatsQuery{
+		UserID:   userID,
+		Since:    since,
+		Location: s.location,
+	})
+}
+
+// GetHeatmap mengembalikan semua tombol layout QWERTY (termasuk yang belum
+// pernah diketik) beserta statistiknya. Karakter di luar layout diabaikan.
+func (s *PlayerService) GetHeatmap(ctx context.Context, userID string) (*KeyHeatmap, error) {
+	layout := domainservices.QWERTY
+
+	stats, err := s.keyStatsRepo.FindKeysByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &KeyHeatmap{Layout: layout.Name}
+	byKey := make(map[rune]*HeatmapKey)
+	for _, pos := range layout.Keys() {
+		key := &HeatmapKey{Position: pos, Stat: models.KeyStat{Key: string(pos.Key)}}
+		byKey[pos.Key] = key
+		heatmap.Keys = append(heatmap.Keys, key)
+	}
+
+	for _, stat := range stats {
+		runes := []rune(stat.Key)
+		if len(runes) != 1 {
+			continue
+		}
+		pos, ok := layout.Lookup(runes[0])
+		if !ok {
+			continue
+		}
+		key := byKey[pos.Key]
+		key.Stat.Presses += stat.Presses
+		key.Stat.Errors += stat.Errors
+		key.Stat.TotalLatencyMs += stat.TotalLatencyMs
+		key.Stat.LatencySamples += stat.LatencySamples
+		key.Stat.TotalMistakeLatencyMs += stat.TotalMistakeLatencyMs
+		key.Stat.MistakeLatencySamples += stat.MistakeLatencySamples
+	}
+
+	heatmap.Bigrams, err = s.keyStatsRepo.FindBigramsByUser(ctx, userID, HeatmapBigramMinPresses, HeatmapBigramLimit)
+	if err != nil {
+		return nil, err
+	}
+	heatmap.Substitutions, err = s.keyStatsRepo.FindSubstitutionsByUser(ctx, userID, HeatmapSubstitutionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return heatmap, nil
+}
+
+// parseBoundary mem-parse batas from/to. Tanggal tanpa jam dihitung di
+// timezone leaderboard; untuk batas akhir dipakai awal hari berikutnya
+// supaya tanggal tersebut ikut terhitung.
+func (s *PlayerService) parseBoundary(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, s.location)
+	if err != nil {
+		return time.Time{}, ErrInvalidDateRange
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// attemptCursor adalah isi cursor opaque riwayat attempt. completed_at
+// disimpan apa adanya (wall clock dari database) agar keyset tetap tepat.
+type attemptCursor struct {
+	CompletedAt time.Time `json:"c"`
+	ScoreID     int64     `json:"i"`
+}
+
+func encodeAttemptCursor(c *models.AttemptCursor) string {
+	data, _ := json.Marshal(attemptCursor{CompletedAt: c.CompletedAt, ScoreID: c.ScoreID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeAttemptCursor(cursor string) (*models.AttemptCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c attemptCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.CompletedAt.IsZero() || c.ScoreID < 1 {
+		return nil, ErrInvalidCursor
+	}
+	return &models.AttemptCursor{CompletedAt: c.CompletedAt, ScoreID: c.ScoreID}, nil
+}
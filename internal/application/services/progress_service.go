@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+)
+
+// PlayerProgress adalah XP, posisi di kurva level, dan streak user saat ini
+type PlayerProgress struct {
+	XP            int64
+	Level         domainservices.LevelInfo
+	CurrentStreak int
+	LongestStreak int
+}
+
+// AttemptProgress adalah perubahan progres dari satu attempt
+type AttemptProgress struct {
+	PlayerProgress
+	XPGained  int
+	LeveledUp bool
+}
+
+type ProgressService struct {
+	progressRepo repositories.UserProgressRepository
+	userRepo     repositories.UserRepository
+	location     *time.Location
+}
+
+// NewProgressService membuat service XP & streak. location dipakai untuk
+// batas hari user yang belum mengatur timezone.
+func NewProgressService(
+	progressRepo repositories.UserProgressRepository,
+	userRepo repositories.UserRepository,
+	location *time.Location,
+) *ProgressService {
+	return &ProgressService{
+		progressRepo: progressRepo,
+		userRepo:     userRepo,
+		location:     location,
+	}
+}
+
+// ApplyAttempt menambah XP dan memperbarui streak untuk score yang baru
+// disimpan. Dipanggil di dalam transaksi yang sama dengan
+// ScoreRepository.Create supaya attempt dan progres tidak pernah berbeda.
+func (s *ProgressService) ApplyAttempt(ctx context.Context, score *models.Score, stage *models.Stage) (*AttemptProgress, error) {
+	today, err := s.today(ctx, score.UserID, score.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.progressRepo.FindByUserIDForUpdate(ctx, score.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousLevel := domainservices.LevelForXP(progress.XP).Level
+	xpGained := domainservices.AttemptXP(score.FinalScore, stage.Difficulty)
+	progress.XP += int64(xpGained)
+	level := domainservices.LevelForXP(progress.XP)
+	progress.Level = level.Level
+	domainservices.AdvanceStreak(progress, today)
+
+	if err := s.progressRepo.Save(ctx, progress); err != nil {
+		return nil, err
+	}
+
+	return &AttemptProgress{
+		PlayerProgress: PlayerProgress{
+			XP:            progress.XP,
+			Level:         level,
+			CurrentStreak: progress.CurrentStreak,
+			LongestStreak: progress.LongestStreak,
+		},
+		XPGained:  xpGained,
+		LeveledUp: level.Level > previousLevel,
+	}, nil
+}
+
+// GetProgress mengembalikan progres user. Streak yang sudah terputus
+// (tidak bermain kemarin maupun hari ini) dilaporkan 0.
+func (s *ProgressService) GetProgress(ctx context.Context, userID string) (*PlayerProgress, error) {
+	progress, err := s.progressRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return &PlayerProgress{Level: domainservices.LevelForXP(0)}, nil
+	}
+
+	today, err := s.today(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlayerProgress{
+		XP:            progress.XP,
+		Level:         domainservices.LevelForXP(progress.XP),
+		CurrentStreak: domainservices.ActiveStreak(progress, today),
+		LongestStreak: progress.LongestStreak,
+	}, nil
+}
+
+// today mengembalikan tanggal at di timezone user sebagai 00:00 UTC
+func (s *ProgressService) today(ctx context.Context, userID string, at time.Time) (time.Time, error) {
+	loc := s.location
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user != nil {
+		loc = user.Location(s.location)
+	}
+
+	local := at.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), nil
+}
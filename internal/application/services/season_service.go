@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSeasonNotFound      = errors.New("season not found")
+	ErrActiveSeasonExists  = errors.New("another season is still active")
+	ErrSeasonAlreadyClosed = errors.New("season already closed")
+	ErrInvalidSeasonEnd    = errors.New("season end must be in the future")
+)
+
+// DefaultPodiumSize adalah jumlah standings per stage yang ditampilkan
+// jika limit tidak diisi
+const DefaultPodiumSize = 3
+
+type SeasonService struct {
+	seasonRepo repositories.SeasonRepository
+}
+
+func NewSeasonService(seasonRepo repositories.SeasonRepository) *SeasonService {
+	return &SeasonService{seasonRepo: seasonRepo}
+}
+
+// CreateSeason memulai season baru. Score yang disimpan setelah ini masuk ke
+// season tersebut sampai season ditutup.
+func (s *SeasonService) CreateSeason(ctx context.Context, name string, endsAt *time.Time) (*models.Season, error) {
+	if endsAt != nil && !endsAt.After(time.Now()) {
+		return nil, ErrInvalidSeasonEnd
+	}
+
+	active, err := s.seasonRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrActiveSeasonExists
+	}
+
+	season := &models.Season{
+		Name:   name,
+		EndsAt: endsAt,
+	}
+	if err := s.seasonRepo.Create(ctx, season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// CloseSeason menutup season dan membekukan final standings per stage
+func (s *SeasonService) CloseSeason(ctx context.Context, seasonID string) (*models.Season, error) {
+	if _, err := uuid.Parse(seasonID); err != nil {
+		return nil, ErrSeasonNotFound
+	}
+	season, err := s.seasonRepo.FindByID(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, ErrSeasonNotFound
+	}
+	if !season.IsActive() {
+		return nil, ErrSeasonAlreadyClosed
+	}
+
+	closed, err := s.seasonRepo.Close(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if closed == nil {
+		// Sudah ditutup oleh request / job lain
+		return nil, ErrSeasonAlreadyClosed
+	}
+	return closed, nil
+}
+
+// CloseExpiredSeasons menutup season aktif yang ends_at-nya sudah lewat.
+// Dipanggil berkala oleh closing job.
+func (s *SeasonService) CloseExpiredSeasons(ctx context.Context) error {
+	seasons, err := s.seasonRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, season := range seasons {
+		if _, err := s.CloseSeason(ctx, season.ID); err != nil && err != ErrSeasonAlreadyClosed {
+			return err
+		}
+		log.Printf("Season %q closed, final standings frozen", season.Name)
+	}
+	return nil
+}
+
+// RunClosingJob menjalankan CloseExpiredSeasons setiap interval sampai ctx selesai
+func (s *SeasonService) RunClosingJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CloseExpiredSeasons(ctx); err != nil {
+				log.Printf("Failed to close expired seasons: %v", err)
+			}
+		}
+	}
+}
+
+func (s *SeasonService) GetAllSeasons(ctx context.Context) ([]*models.Season, error) {
+	return s.seasonRepo.FindAll(ctx)
+}
+
+// GetStandings mengembalikan final standings season yang sudah ditutup,
+// limit entry teratas per stage (default podium 3)
+func (s *SeasonService) GetStandings(ctx context.Context, seasonID, stageID string, limit int) (*models.Season, []*models.SeasonStanding, error) {
+	if limit < 1 || limit > MaxLeaderboardLimit {
+		return nil, nil, ErrInvalidLimit
+	}
+	if _, err := uuid.Parse(seasonID); err != nil {
+		return nil, nil, ErrSeasonNotFound
+	}
+	if stageID != "" {
+		if _, err := uuid.Parse(stageID); err != nil {
+			return nil, nil, ErrStageNotFound
+		}
+	}
+
+	season, err := s.seasonRepo.FindByID(ctx, seasonID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if season == nil {
+		return nil, nil, ErrSeasonNotFound
+	}
+	if season.IsActive() {
+		// Season berjalan belum punya final standings; pakai leaderboard period=season
+		return season, nil, nil
+	}
+
+	standings, err := s.seasonRepo.FindStandings(ctx, seasonID, stageID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return season, standings, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// memorySeasonRepo meniru kontrak SeasonRepository di Postgres: score
+// dicap dengan season yang aktif saat disimpan, dan Close membekukan
+// ranking season ke standings sekali saja.
+type memorySeasonRepo struct {
+	seasons   []*models.Season
+	scores    []seasonScore
+	standings map[string][]*models.SeasonStanding
+}
+
+type seasonScore struct {
+	seasonID, stageID, userID string
+	finalScore                float64
+}
+
+func newMemorySeasonRepo() *memorySeasonRepo {
+	return &memorySeasonRepo{standings: map[string][]*models.SeasonStanding{}}
+}
+
+// record menyimpan score seperti insert ke tabel scores
+func (r *memorySeasonRepo) record(stageID, userID string, finalScore float64) {
+	score := seasonScore{stageID: stageID, userID: userID, finalScore: finalScore}
+	if active, _ := r.FindActive(context.Background()); active != nil {
+		score.seasonID = active.ID
+	}
+	r.scores = append(r.scores, score)
+}
+
+func (r *memorySeasonRepo) Create(ctx context.Context, season *models.Season) error {
+	season.ID = uuid.NewString()
+	season.Status = models.SeasonStatusActive
+	season.StartsAt = time.Now()
+	stored := *season
+	r.seasons = append(r.seasons, &stored)
+	return nil
+}
+
+func (r *memorySeasonRepo) FindByID(ctx context.Context, seasonID string) (*models.Season, error) {
+	for _, season := range r.seasons {
+		if season.ID == seasonID {
+			copied := *season
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memorySeasonRepo) FindAll(ctx context.Context) ([]*models.Season, error) {
+	return r.seasons, nil
+}
+
+func (r *memorySeasonRepo) FindActive(ctx context.Context) (*models.Season, error) {
+	for _, season := range r.seasons {
+		if season.IsActive() {
+			copied := *season
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memorySeasonRepo) FindExpired(ctx context.Context, now time.Time) ([]*models.Season, error) {
+	var expired []*models.Season
+	for _, season := range r.seasons {
+		if season.IsActive() && season.EndsAt != nil && !season.EndsAt.After(now) {
+			copied := *season
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+func (r *memorySeasonRepo) Close(ctx context.Context, seasonID string) (*models.Season, error) {
+	for _, season := range r.seasons {
+		if season.ID != seasonID || !season.IsActive() {
+			continue
+		}
+		now := time.Now()
+		season.Status = models.SeasonStatusClosed
+		season.ClosedAt = &now
+
+		var frozen []*models.SeasonStanding
+		for _, score := range r.scores {
+			if score.seasonID == seasonID {
+				frozen = append(frozen, &models.SeasonStanding{SeasonID: seasonID, StageID: score.stageID, UserID: score.userID, FinalScore: score.finalScore})
+			}
+		}
+		sort.SliceStable(frozen, func(i, j int) bool { return frozen[i].FinalScore > frozen[j].FinalScore })
+		for i, standing := range frozen {
+			standing.Rank = i + 1
+		}
+		r.standings[seasonID] = frozen
+
+		copied := *season
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memorySeasonRepo) FindStandings(ctx context.Context, seasonID, stageID string, limit int) ([]*models.SeasonStanding, error) {
+	var standings []*models.SeasonStanding
+	for _, standing := range r.standings[seasonID] {
+		if (stageID == "" || standing.StageID == stageID) && len(standings) < limit {
+			copied := *standing
+			standings = append(standings, &copied)
+		}
+	}
+	return standings, nil
+}
+
+func standingUsers(standings []*models.SeasonStanding) []string {
+	users := make([]string, 0, len(standings))
+	for _, standing := range standings {
+		users = append(users, standing.UserID)
+	}
+	return users
+}
+
+func TestSeasonLifecycleFreezesStandings(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemorySeasonRepo()
+	service := NewSeasonService(repo)
+	stageID := uuid.NewString()
+
+	first, err := service.CreateSeason(ctx, "Semester Ganjil", nil)
+	if err != nil {
+		t.Fatalf("CreateSeason: %v", err)
+	}
+	if _, err := service.CreateSeason(ctx, "Overlap", nil); err != ErrActiveSeasonExists {
+		t.Fatalf("second CreateSeason err = %v, want ErrActiveSeasonExists", err)
+	}
+
+	repo.record(stageID, "alice", 120)
+	repo.record(stageID, "bob", 300)
+
+	season, standings, err := service.GetStandings(ctx, first.ID, "", DefaultPodiumSize)
+	if err != nil {
+		t.Fatalf("GetStandings while active: %v", err)
+	}
+	if !season.IsActive() || standings != nil {
+		t.Fatalf("active season: status %q, standings %v; want active with no standings", season.Status, standings)
+	}
+
+	closed, err := service.CloseSeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("CloseSeason: %v", err)
+	}
+	if closed.IsActive() || closed.ClosedAt == nil {
+		t.Errorf("closed season = %+v", closed)
+	}
+	if _, err := service.CloseSeason(ctx, first.ID); err != ErrSeasonAlreadyClosed {
+		t.Errorf("closing twice err = %v, want ErrSeasonAlreadyClosed", err)
+	}
+
+	// Score setelah season ditutup, baik tanpa season maupun di season
+	// berikutnya, tidak boleh mengubah final standings
+	repo.record(stageID, "carol", 999)
+	if _, err := service.CreateSeason(ctx, "Semester Genap", nil); err != nil {
+		t.Fatalf("CreateSeason after close: %v", err)
+	}
+	repo.record(stageID, "alice", 500)
+
+	_, standings, err = service.GetStandings(ctx, first.ID, stageID, DefaultPodiumSize)
+	if err != nil {
+		t.Fatalf("GetStandings after close: %v", err)
+	}
+	got := standingUsers(standings)
+	if len(got) != 2 || got[0] != "bob" || got[1] != "alice" || standings[1].FinalScore != 120 {
+		t.Errorf("final standings = %v (%+v), want bob then alice at 120", got, standings)
+	}
+}
+
+func TestCloseExpiredSeasons(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemorySeasonRepo()
+	service := NewSeasonService(repo)
+
+	endsAt := time.Now().Add(time.Hour)
+	season, err := service.CreateSeason(ctx, "Ujian", &endsAt)
+	if err != nil {
+		t.Fatalf("CreateSeason: %v", err)
+	}
+	repo.record(uuid.NewString(), "alice", 80)
+
+	if err := service.CloseExpiredSeasons(ctx); err != nil {
+		t.Fatalf("CloseExpiredSeasons before ends_at: %v", err)
+	}
+	if stored, _ := repo.FindByID(ctx, season.ID); !stored.IsActive() {
+		t.Fatal("season closed before ends_at")
+	}
+
+	past := time.Now().Add(-time.Minute)
+	repo.seasons[0].EndsAt = &past
+	if err := service.CloseExpiredSeasons(ctx); err != nil {
+		t.Fatalf("CloseExpiredSeasons: %v", err)
+	}
+	if stored, _ := repo.FindByID(ctx, season.ID); stored.IsActive() {
+		t.Fatal("expired season still active")
+	}
+	_, standings, err := service.GetStandings(ctx, season.ID, "", DefaultPodiumSize)
+	if err != nil || len(standings) != 1 || standings[0].UserID != "alice" {
+		t.Errorf("standings = %v, err %v; want alice frozen", standingUsers(standings), err)
+	}
+
+	// Job berikutnya tidak menutup ulang season yang sama
+	if err := service.CloseExpiredSeasons(ctx); err != nil {
+		t.Errorf("CloseExpiredSeasons again: %v", err)
+	}
+}
+
+func TestCreateSeasonRejectsPastEnd(t *testing.T) {
+	past := time.Now().Add(-time.Second)
+	if _, err := NewSeasonService(newMemorySeasonRepo()).CreateSeason(context.Background(), "Lama", &past); err != ErrInvalidSeasonEnd {
+		t.Errorf("err = %v, want ErrInvalidSeasonEnd", err)
+	}
+}
@@ -0,0 +1,660 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+)
+
+// StageBundleService memindahkan konten stage antar environment lewat
+// bundle (theme, stage, phrase berurutan). Stage dicocokkan lewat slug
+// dan theme lewat nama sehingga import bisa diulang (upsert).
+type StageBundleService struct {
+	themeRepo  repositories.ThemeRepository
+	stageRepo  repositories.StageRepository
+	phraseRepo repositories.PhraseRepository
+	transactor repositories.Transactor
+
+	phraseLinter   *domainservices.PhraseLinter
+	phraseAnalyzer *domainservices.PhraseAnalyzer
+}
+
+func NewStageBundleService(
+	themeRepo repositories.ThemeRepository,
+	stageRepo repositories.StageRepository,
+	phraseRepo repositories.PhraseRepository,
+	transactor repositories.Transactor,
+	phraseLinter *domainservices.PhraseLinter,
+	phraseAnalyzer *domainservices.PhraseAnalyzer,
+) *StageBundleService {
+	return &StageBundleService{
+		themeRepo:      themeRepo,
+		stageRepo:      stageRepo,
+		phraseRepo:     phraseRepo,
+		transactor:     transactor,
+		phraseLinter:   phraseLinter,
+		phraseAnalyzer: phraseAnalyzer,
+	}
+}
+
+// ExportStages membuat bundle berisi stage dengan slug yang diminta (semua
+// stage jika slugs kosong) beserta theme yang dipakai, urut slug
+func (s *StageBundleService) ExportStages(ctx context.Context, slugs []string) (*models.StageBundle, error) {
+	stages, err := s.stageRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	themes, err := s.themeRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(slugs) > 0 {
+		bySlug := make(map[string]*models.Stage, len(stages))
+		for _, stage := range stages {
+			bySlug[stage.Slug] = stage
+		}
+		selected := make([]*models.Stage, 0, len(slugs))
+		for _, slug := range slugs {
+			stage, ok := bySlug[slug]
+			if !ok {
+				return nil, ErrStageNotFound
+			}
+			selected = append(selected, stage)
+		}
+		stages = selected
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i].Slug < stages[j].Slug })
+
+	themeNames := make(map[string]string, len(themes))
+	for _, theme := range themes {
+		themeNames[theme.ID] = theme.Name
+	}
+
+	bundle := &models.StageBundle{Version: models.StageBundleVersion}
+	usedThemes := make(map[string]bool)
+	for _, stage := range stages {
+		phrases, err := s.phraseRepo.FindByStageID(ctx, stage.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		isActive := stage.IsActive
+		exported := &models.BundleStage{
+			Slug:       stage.Slug,
+			Name:       stage.Name,
+			Theme:      themeNames[stage.ThemeID],
+			Difficulty: stage.Difficulty,
+			IsActive:   &isActive,
+			Phrases:    make([]*models.BundlePhrase, 0, len(phrases)),
+
+			PhraseCharset:    stage.PhraseCharset,
+			PhrasePoolSize:   &stage.PhrasePoolSize,
+			WhitespacePolicy: stage.WhitespacePolicy,
+			PhraseLanguage:   stage.PhraseLanguage,
+		}
+		for _, phrase := range phrases {
+			multiplier := phrase.BaseMultiplier
+			exported.Phrases = append(exported.Phrases, &models.BundlePhrase{
+				Text:       phrase.Text,
+				Multiplier: &multiplier,
+			})
+		}
+		bundle.Stages = append(bundle.Stages, exported)
+		usedThemes[stage.ThemeID] = true
+	}
+
+	// Theme mengikuti urutan sort_order
+	for _, theme := range themes {
+		if !usedThemes[theme.ID] {
+			continue
+		}
+		bundle.Themes = append(bundle.Themes, &models.BundleTheme{
+			Name:        theme.Name,
+			Description: theme.Description,
+			Color:       theme.Color,
+			IconKey:     theme.IconKey,
+			SortOrder:   theme.SortOrder,
+		})
+	}
+	return bundle, nil
+}
+
+// ImportStages menyimpan isi bundle dalam satu transaksi: theme di-upsert
+// berdasarkan nama, stage berdasarkan slug, dan daftar phrase tiap stage
+// disamakan persis dengan bundle. Stage yang tidak ada di bundle tidak
+// disentuh. Pada dry run hanya diff yang dihitung.
+func (s *StageBundleService) ImportStages(ctx context.Context, bundle *models.StageBundle, dryRun bool) (*models.ImportReport, error) {
+	problems := normalizeBundle(bundle)
+	if err := s.lintBundlePhrases(ctx, bundle, problems); err != nil {
+		return nil, err
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{DryRun: dryRun}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		themeIDs, err := s.importThemes(ctx, bundle, report, dryRun)
+		if err != nil {
+			return err
+		}
+		for _, stage := range bundle.Stages {
+			change, err := s.importStage(ctx, stage, themeIDs[stage.Theme], dryRun)
+			if err != nil {
+				return err
+			}
+			report.Stages = append(report.Stages, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importThemes mengembalikan id theme per nama. Theme baru pada dry run
+// belum punya id (string kosong).
+func (s *StageBundleService) importThemes(ctx context.Context, bundle *models.StageBundle, report *models.ImportReport, dryRun bool) (map[string]string, error) {
+	themeIDs := make(map[string]string)
+
+	for _, incoming := range bundle.Themes {
+		theme, err := s.themeRepo.FindByName(ctx, incoming.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		change := &models.ThemeChange{Name: incoming.Name}
+		if theme == nil {
+			change.Action = models.ImportActionCreate
+			theme = &models.Theme{Name: incoming.Name}
+		} else {
+			change.Fields = themeChangedFields(theme, incoming)
+			change.Action = models.ImportActionUnchanged
+			if len(change.Fields) > 0 {
+				change.Action = models.ImportActionUpdate
+			}
+		}
+		theme.Description = incoming.Description
+		theme.Color = incoming.Color
+		theme.IconKey = incoming.IconKey
+		theme.SortOrder = incoming.SortOrder
+
+		if !dryRun {
+			switch change.Action {
+			case models.ImportActionCreate:
+				err = s.themeRepo.Create(ctx, theme)
+			case models.ImportActionUpdate:
+				err = s.themeRepo.Update(ctx, theme)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		themeIDs[theme.Name] = theme.ID
+		report.Themes = append(report.Themes, change)
+	}
+
+	// Theme yang hanya disebut stage: pakai yang sudah ada atau buat baru
+	for _, stage := range bundle.Stages {
+		if _, ok := themeIDs[stage.Theme]; ok {
+			continue
+		}
+		theme, err := s.themeRepo.FindByName(ctx, stage.Theme)
+		if err != nil {
+			return nil, err
+		}
+		if theme == nil {
+			theme = &models.Theme{Name: stage.Theme}
+			if !dryRun {
+				if err := s.themeRepo.Create(ctx, theme); err != nil {
+					return nil, err
+				}
+			}
+			report.Themes = append(report.Themes, &models.ThemeChange{Name: stage.Theme, Action: models.ImportActionCreate})
+		}
+		themeIDs[stage.Theme] = theme.ID
+	}
+	return themeIDs, nil
+}
+
+func themeChangedFields(theme *models.Theme, incoming *models.BundleTheme) []string {
+	var fields []string
+	if theme.Description != incoming.Description {
+		fields = append(fields, "description")
+	}
+	if theme.Color != incoming.Color {
+		fields = append(fields, "color")
+	}
+	if theme.IconKey != incoming.IconKey {
+		fields = append(fields, "icon_key")
+	}
+	if theme.SortOrder != incoming.SortOrder {
+		fields = append(fields, "sort_order")
+	}
+	return fields
+}
+
+func (s *StageBundleService) importStage(ctx context.Context, incoming *models.BundleStage, themeID string, dryRun bool) (*models.StageChange, error) {
+	stage, err := s.stageRepo.FindBySlug(ctx, incoming.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &models.StageChange{Slug: incoming.Slug}
+	var existing []*models.Phrase
+	if stage == nil {
+		change.Action = models.ImportActionCreate
+		stage = &models.Stage{
+			Slug:             incoming.Slug,
+			IsActive:         true,
+			PhraseCharset:    domainservices.PhraseCharsetQWERTY,
+			WhitespacePolicy: domainservices.WhitespaceStrict,
+		}
+	} else {
+		change.StageID = stage.ID
+		change.Fields = stageChangedFields(stage, incoming, themeID)
+		existing, err = s.phraseRepo.FindByStageID(ctx, stage.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := planPhrases(existing, incoming.Phrases)
+	change.Phrases = plan.changes
+	if change.Action == "" {
+		change.Action = models.ImportActionUnchanged
+		if len(change.Fields) > 0 || len(change.Phrases) > 0 {
+			change.Action = models.ImportActionUpdate
+		}
+	}
+	if dryRun || change.Action == models.ImportActionUnchanged {
+		return change, nil
+	}
+
+	stage.Name = incoming.Name
+	stage.ThemeID = themeID
+	stage.Difficulty = incoming.Difficulty
+	if incoming.IsActive != nil {
+		stage.IsActive = *incoming.IsActive
+	}
+	if incoming.PhraseCharset != "" {
+		stage.PhraseCharset = incoming.PhraseCharset
+	}
+	if incoming.PhrasePoolSize != nil {
+		stage.PhrasePoolSize = *incoming.PhrasePoolSize
+	}
+	if incoming.WhitespacePolicy != "" {
+		stage.WhitespacePolicy = incoming.WhitespacePolicy
+	}
+	if incoming.PhraseLanguage != "" {
+		stage.PhraseLanguage = incoming.PhraseLanguage
+	}
+	if change.Action == models.ImportActionCreate {
+		err = s.stageRepo.Create(ctx, stage)
+	} else if len(change.Fields) > 0 {
+		err = s.stageRepo.Update(ctx, stage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	change.StageID = stage.ID
+
+	if len(change.Phrases) > 0 {
+		if err := s.applyPhrasePlan(ctx, stage.ID, existing, incoming.Phrases, plan); err != nil {
+			return nil, err
+		}
+	}
+	return change, nil
+}
+
+func stageChangedFields(stage *models.Stage, incoming *models.BundleStage, themeID string) []string {
+	var fields []string
+	if stage.Name != incoming.Name {
+		fields = append(fields, "name")
+	}
+	if stage.ThemeID != themeID {
+		fields = append(fields, "theme")
+	}
+	if stage.Difficulty != incoming.Difficulty {
+		fields = append(fields, "difficulty")
+	}
+	if incoming.IsActive != nil && stage.IsActive != *incoming.IsActive {
+		fields = append(fields, "is_active")
+	}
+	if incoming.PhraseCharset != "" && stage.PhraseCharset != incoming.PhraseCharset {
+		fields = append(fields, "phrase_charset")
+	}
+	if incoming.PhrasePoolSize != nil && stage.PhrasePoolSize != *incoming.PhrasePoolSize {
+		fields = append(fields, "phrase_pool_size")
+	}
+	if incoming.WhitespacePolicy != "" && stage.WhitespacePolicy != incoming.WhitespacePolicy {
+		fields = append(fields, "whitespace_policy")
+	}
+	if incoming.PhraseLanguage != "" && stage.PhraseLanguage != incoming.PhraseLanguage {
+		fields = append(fields, "phrase_language")
+	}
+	return fields
+}
+
+// phrasePlan memetakan phrase bundle ke phrase yang sudah ada. matches[i]
+// adalah index phrase lama untuk phrase bundle ke-i (-1 = phrase baru).
+type phrasePlan struct {
+	matches []int
+	removed []int
+	changes []*models.PhraseChange
+}
+
+// planPhrases mencocokkan phrase lama dan baru: pertama berdasarkan teks
+// yang sama persis, lalu sisanya dipasangkan berurutan sebagai phrase yang
+// diedit sehingga id phrase (dan riwayat yang merujuknya) tetap terjaga.
+func planPhrases(existing []*models.Phrase, incoming []*models.BundlePhrase) *phrasePlan {
+	plan := &phrasePlan{matches: make([]int, len(incoming))}
+	used := make([]bool, len(existing))
+
+	byText := make(map[string][]int)
+	for i, phrase := range existing {
+		byText[phrase.Text] = append(byText[phrase.Text], i)
+	}
+	for i, phrase := range incoming {
+		plan.matches[i] = -1
+		if candidates := byText[phrase.Text]; len(candidates) > 0 {
+			plan.matches[i] = candidates[0]
+			used[candidates[0]] = true
+			byText[phrase.Text] = candidates[1:]
+		}
+	}
+
+	next := 0
+	for i := range incoming {
+		if plan.matches[i] != -1 {
+			continue
+		}
+		for next < len(existing) && used[next] {
+			next++
+		}
+		if next < len(existing) {
+			plan.matches[i] = next
+			used[next] = true
+		}
+	}
+
+	stable := stableMatches(plan.matches)
+	for i, phrase := range incoming {
+		match := plan.matches[i]
+		if match == -1 {
+			plan.changes = append(plan.changes, &models.PhraseChange{
+				Position:   i + 1,
+				Action:     models.ImportActionAdd,
+				Text:       phrase.Text,
+				Multiplier: *phrase.Multiplier,
+			})
+			continue
+		}
+
+		old := existing[match]
+		moved := !stable[i]
+		if old.Text == phrase.Text && old.BaseMultiplier == *phrase.Multiplier && !moved {
+			continue
+		}
+		change := &models.PhraseChange{
+			Position:   i + 1,
+			Action:     models.ImportActionUpdate,
+			Text:       phrase.Text,
+			Multiplier: *phrase.Multiplier,
+			Moved:      moved,
+		}
+		if old.Text != phrase.Text {
+			change.OldText = old.Text
+		}
+		if old.BaseMultiplier != *phrase.Multiplier {
+			change.OldMultiplier = old.BaseMultiplier
+		}
+		plan.changes = append(plan.changes, change)
+	}
+
+	for i, phrase := range existing {
+		if used[i] {
+			continue
+		}
+		plan.removed = append(plan.removed, i)
+		plan.changes = append(plan.changes, &models.PhraseChange{
+			Position:   i + 1,
+			Action:     models.ImportActionRemove,
+			Text:       phrase.Text,
+			Multiplier: phrase.BaseMultiplier,
+		})
+	}
+	return plan
+}
+
+// stableMatches menandai phrase yang urutan relatifnya tidak berubah, yaitu
+// anggota longest increasing subsequence dari index phrase lama. Phrase
+// lain dilaporkan sebagai dipindah.
+func stableMatches(matches []int) []bool {
+	stable := make([]bool, len(matches))
+	var tails []int // index di matches, ujung subsequence terpendek per panjang
+	prev := make([]int, len(matches))
+	for i, match := range matches {
+		if match == -1 {
+			continue
+		}
+		length := sort.Search(len(tails), func(k int) bool { return matches[tails[k]] >= match })
+		prev[i] = -1
+		if length > 0 {
+			prev[i] = tails[length-1]
+		}
+		if length == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[length] = i
+		}
+	}
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i != -1; i = prev[i] {
+			stable[i] = true
+		}
+	}
+	return stable
+}
+
+func (s *StageBundleService) applyPhrasePlan(ctx context.Context, stageID string, existing []*models.Phrase, incoming []*models.BundlePhrase, plan *phrasePlan) error {
+	for _, index := range plan.removed {
+		if err := s.phraseRepo.Delete(ctx, existing[index].ID); err != nil {
+			return err
+		}
+	}
+
+	// Phrase baru disimpan sementara di belakang sequence yang ada, lalu
+	// seluruh stage diurutkan ulang sesuai bundle
+	maxSequence := 0
+	for _, phrase := range existing {
+		if phrase.SequenceNumber > maxSequence {
+			maxSequence = phrase.SequenceNumber
+		}
+	}
+
+	ordered := make([]string, 0, len(incoming))
+	for i, incomingPhrase := range incoming {
+		match := plan.matches[i]
+		if match == -1 {
+			maxSequence++
+			phrase := &models.Phrase{
+				StageID:        stageID,
+				Text:           incomingPhrase.Text,
+				SequenceNumber: maxSequence,
+				BaseMultiplier: *incomingPhrase.Multiplier,
+			}
+			if err := s.phraseRepo.Create(ctx, phrase); err != nil {
+				return err
+			}
+			ordered = append(ordered, phrase.ID)
+			continue
+		}
+
+		phrase := existing[match]
+		if phrase.Text != incomingPhrase.Text || phrase.BaseMultiplier != *incomingPhrase.Multiplier {
+			phrase.Text = incomingPhrase.Text
+			phrase.BaseMultiplier = *incomingPhrase.Multiplier
+			if err := s.phraseRepo.Update(ctx, phrase); err != nil {
+				return err
+			}
+		}
+		ordered = append(ordered, phrase.ID)
+	}
+
+	if len(ordered) == 0 {
+		return nil
+	}
+	return s.phraseRepo.Resequence(ctx, stageID, ordered)
+}
+
+// normalizeBundle memvalidasi struktur bundle dan merapikan nilainya (trim
+// nama, warna uppercase, multiplier dua desimal seperti kolom database).
+// Isi phrase diperiksa terpisah oleh lintBundlePhrases.
+func normalizeBundle(bundle *models.StageBundle) *ValidationError {
+	problems := &ValidationError{}
+	if bundle.Version != models.StageBundleVersion {
+		problems.add("version", "unsupported bundle version %d (expected %d)", bundle.Version, models.StageBundleVersion)
+	}
+
+	themeNames := make(map[string]bool)
+	for i, theme := range bundle.Themes {
+		path := "themes[" + strconv.Itoa(i) + "]"
+		theme.Name = strings.TrimSpace(theme.Name)
+		theme.Color = strings.ToUpper(strings.TrimSpace(theme.Color))
+		theme.IconKey = strings.TrimSpace(theme.IconKey)
+
+		switch {
+		case !validThemeName(theme.Name):
+			problems.add(path+".name", ErrInvalidThemeName.Error())
+		case themeNames[theme.Name]:
+			problems.add(path+".name", "duplicate theme %q", theme.Name)
+		}
+		themeNames[theme.Name] = true
+		if theme.Color != "" && !themeColorPattern.MatchString(theme.Color) {
+			problems.add(path+".color", ErrInvalidThemeColor.Error())
+		}
+		if theme.IconKey != "" && !iconKeyPattern.MatchString(theme.IconKey) {
+			problems.add(path+".icon_key", ErrInvalidIconKey.Error())
+		}
+		if theme.SortOrder < 0 {
+			problems.add(path+".sort_order", "must be 0 or greater")
+		}
+	}
+
+	slugs := make(map[string]bool)
+	for i, stage := range bundle.Stages {
+		path := "stages[" + strconv.Itoa(i) + "]"
+		stage.Slug = strings.TrimSpace(stage.Slug)
+		stage.Name = strings.TrimSpace(stage.Name)
+		stage.Theme = strings.TrimSpace(stage.Theme)
+
+		switch {
+		case !domainservices.ValidSlug(stage.Slug):
+			problems.add(path+".slug", ErrInvalidStageSlug.Error())
+		case slugs[stage.Slug]:
+			problems.add(path+".slug", "duplicate slug %q", stage.Slug)
+		}
+		slugs[stage.Slug] = true
+		if stage.Name == "" {
+			problems.add(path+".name", "is required")
+		}
+		// Theme yang belum ada dibuat otomatis dengan nama ini
+		if !validThemeName(stage.Theme) {
+			problems.add(path+".theme", ErrInvalidThemeName.Error())
+		}
+		switch stage.Difficulty {
+		case "", models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard:
+		default:
+			problems.add(path+".difficulty", "must be easy, medium or hard")
+		}
+		stage.PhraseCharset = strings.TrimSpace(stage.PhraseCharset)
+		if stage.PhraseCharset != "" && !domainservices.ValidPhraseCharset(stage.PhraseCharset) {
+			problems.add(path+".phrase_charset", ErrInvalidPhraseCharset.Error())
+		}
+		if stage.PhrasePoolSize != nil && *stage.PhrasePoolSize < 0 {
+			problems.add(path+".phrase_pool_size", ErrInvalidPhrasePoolSize.Error())
+		}
+		stage.WhitespacePolicy = strings.TrimSpace(stage.WhitespacePolicy)
+		if stage.WhitespacePolicy != "" && !domainservices.ValidWhitespacePolicy(stage.WhitespacePolicy) {
+			problems.add(path+".whitespace_policy", ErrInvalidWhitespacePolicy.Error())
+		}
+		if language, err := normalizePhraseLanguage(stage.PhraseLanguage); err != nil {
+			problems.add(path+".phrase_language", err.Error())
+		} else {
+			stage.PhraseLanguage = language
+		}
+	}
+	return problems
+}
+
+// lintBundlePhrases menormalisasi teks phrase lalu memeriksanya dengan
+// linter memakai charset stage (dari bundle, stage yang sudah ada, atau
+// qwerty untuk stage baru). Teks yang sama dua kali dalam satu stage ditolak.
+// Multiplier yang tidak diisi dan difficulty kosong diisi saran
+// PhraseAnalyzer sesuai whitespace policy stage yang ditentukan dengan cara
+// yang sama; multiplier 0 yang ditulis eksplisit tetap ditolak linter.
+func (s *StageBundleService) lintBundlePhrases(ctx context.Context, bundle *models.StageBundle, problems *ValidationError) error {
+	for i, stage := range bundle.Stages {
+		charset, policy := stage.PhraseCharset, stage.WhitespacePolicy
+		if (charset == "" || policy == "") && domainservices.ValidSlug(stage.Slug) {
+			existing, err := s.stageRepo.FindBySlug(ctx, stage.Slug)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				if charset == "" {
+					charset = existing.PhraseCharset
+				}
+				if policy == "" {
+					policy = existing.WhitespacePolicy
+				}
+			}
+		}
+		if !domainservices.ValidPhraseCharset(charset) {
+			charset = domainservices.PhraseCharsetQWERTY
+		}
+		if !domainservices.ValidWhitespacePolicy(policy) {
+			policy = domainservices.WhitespaceStrict
+		}
+
+		seen := make(map[string]int)
+		for j, phrase := range stage.Phrases {
+			path := "stages[" + strconv.Itoa(i) + "].phrases[" + strconv.Itoa(j) + "]."
+			phrase.Text = s.phraseLinter.NormalizeText(phrase.Text)
+			multiplier := s.phraseAnalyzer.Analyze(phrase.Text, policy).SuggestedMultiplier
+			if phrase.Multiplier != nil {
+				multiplier = *phrase.Multiplier
+			}
+			multiplier = math.Round(multiplier*100) / 100
+			phrase.Multiplier = &multiplier
+			problems.addPhraseIssues(path, "multiplier", s.phraseLinter.Lint(phrase.Text, multiplier, charset))
+
+			if first, ok := seen[phrase.Text]; ok && phrase.Text != "" {
+				problems.add(path+"text", "duplicates phrases[%d] in this stage", first)
+				continue
+			}
+			seen[phrase.Text] = j
+		}
+
+		if stage.Difficulty == "" {
+			phrases := make([]*models.Phrase, 0, len(stage.Phrases))
+			for _, phrase := range stage.Phrases {
+				phrases = append(phrases, &models.Phrase{Text: phrase.Text})
+			}
+			stage.Difficulty = s.phraseAnalyzer.AnalyzeStage(phrases, policy).SuggestedDifficulty
+			if stage.Difficulty == "" {
+				problems.add("stages["+strconv.Itoa(i)+"].difficulty", "is required for a stage without phrases")
+			}
+		}
+	}
+	return nil
+}
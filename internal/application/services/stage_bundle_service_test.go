@@ -0,0 +1,224 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+func TestPlanPhrases(t *testing.T) {
+	existing := func(texts ...string) []*models.Phrase {
+		phrases := make([]*models.Phrase, 0, len(texts))
+		for i, text := range texts {
+			phrases = append(phrases, &models.Phrase{Text: text, SequenceNumber: i + 1, BaseMultiplier: 1})
+		}
+		return phrases
+	}
+	incoming := func(texts ...string) []*models.BundlePhrase {
+		phrases := make([]*models.BundlePhrase, 0, len(texts))
+		for _, text := range texts {
+			multiplier := 1.0
+			phrases = append(phrases, &models.BundlePhrase{Text: text, Multiplier: &multiplier})
+		}
+		return phrases
+	}
+	withMultiplier := func(phrases []*models.BundlePhrase, index int, multiplier float64) []*models.BundlePhrase {
+		phrases[index].Multiplier = &multiplier
+		return phrases
+	}
+
+	tests := []struct {
+		name     string
+		existing []*models.Phrase
+		incoming []*models.BundlePhrase
+		matches  []int
+		removed  []int
+		changes  []*models.PhraseChange
+	}{
+		{
+			name:     "unchanged",
+			existing: existing("a", "b", "c"),
+			incoming: incoming("a", "b", "c"),
+			matches:  []int{0, 1, 2},
+		},
+		{
+			name:     "new stage",
+			existing: existing(),
+			incoming: incoming("a", "b"),
+			matches:  []int{-1, -1},
+			changes: []*models.PhraseChange{
+				{Position: 1, Action: models.ImportActionAdd, Text: "a", Multiplier: 1},
+				{Position: 2, Action: models.ImportActionAdd, Text: "b", Multiplier: 1},
+			},
+		},
+		{
+			name:     "multiplier changed",
+			existing: existing("a", "b"),
+			incoming: withMultiplier(incoming("a", "b"), 1, 1.5),
+			matches:  []int{0, 1},
+			changes: []*models.PhraseChange{
+				{Position: 2, Action: models.ImportActionUpdate, Text: "b", Multiplier: 1.5, OldMultiplier: 1},
+			},
+		},
+		{
+			name:     "edited text keeps position match",
+			existing: existing("a", "b", "c"),
+			incoming: incoming("a", "B", "c"),
+			matches:  []int{0, 1, 2},
+			changes: []*models.PhraseChange{
+				{Position: 2, Action: models.ImportActionUpdate, Text: "B", OldText: "b", Multiplier: 1},
+			},
+		},
+		{
+			name:     "exact text wins over position",
+			existing: existing("a", "b"),
+			incoming: incoming("x", "a"),
+			matches:  []int{1, 0},
+			changes: []*models.PhraseChange{
+				{Position: 1, Action: models.ImportActionUpdate, Text: "x", OldText: "b", Multiplier: 1, Moved: true},
+			},
+		},
+		{
+			name:     "moved phrase",
+			existing: existing("a", "b", "c"),
+			incoming: incoming("c", "a", "b"),
+			matches:  []int{2, 0, 1},
+			changes: []*models.PhraseChange{
+				{Position: 1, Action: models.ImportActionUpdate, Text: "c", Multiplier: 1, Moved: true},
+			},
+		},
+		{
+			name:     "appended phrase",
+			existing: existing("a", "b"),
+			incoming: incoming("a", "b", "c"),
+			matches:  []int{0, 1, -1},
+			changes: []*models.PhraseChange{
+				{Position: 3, Action: models.ImportActionAdd, Text: "c", Multiplier: 1},
+			},
+		},
+		{
+			name:     "removed phrase",
+			existing: existing("a", "b", "c"),
+			incoming: incoming("a", "c"),
+			matches:  []int{0, 2},
+			removed:  []int{1},
+			changes: []*models.PhraseChange{
+				{Position: 2, Action: models.ImportActionRemove, Text: "b", Multiplier: 1},
+			},
+		},
+		{
+			name:     "duplicate existing text",
+			existing: existing("a", "a"),
+			incoming: incoming("a"),
+			matches:  []int{0},
+			removed:  []int{1},
+			changes: []*models.PhraseChange{
+				{Position: 2, Action: models.ImportActionRemove, Text: "a", Multiplier: 1},
+			},
+		},
+		{
+			name:     "all phrases removed",
+			existing: existing("a", "b"),
+			incoming: incoming(),
+			matches:  []int{},
+			removed:  []int{0, 1},
+			changes: []*models.PhraseChange{
+				{Position: 1, Action: models.ImportActionRemove, Text: "a", Multiplier: 1},
+				{Position: 2, Action: models.ImportActionRemove, Text: "b", Multiplier: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planPhrases(tt.existing, tt.incoming)
+			if !reflect.DeepEqual(plan.matches, tt.matches) {
+				t.Errorf("matches = %v, want %v", plan.matches, tt.matches)
+			}
+			if !reflect.DeepEqual(plan.removed, tt.removed) {
+				t.Errorf("removed = %v, want %v", plan.removed, tt.removed)
+			}
+			if !reflect.DeepEqual(plan.changes, tt.changes) {
+				t.Errorf("changes = %+v, want %+v", formatChanges(plan.changes), formatChanges(tt.changes))
+			}
+		})
+	}
+}
+
+func TestStableMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []int
+		want    []bool
+	}{
+		{"empty", []int{}, []bool{}},
+		{"in order", []int{0, 1, 2}, []bool{true, true, true}},
+		{"last moved to front", []int{2, 0, 1}, []bool{false, true, true}},
+		{"first moved to back", []int{1, 2, 0}, []bool{true, true, false}},
+		{"new phrases are not stable", []int{0, -1, 1}, []bool{true, false, true}},
+		{"reversed keeps one", []int{2, 1, 0}, []bool{false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stableMatches(tt.matches); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stableMatches(%v) = %v, want %v", tt.matches, got, tt.want)
+			}
+		})
+	}
+}
+
+func formatChanges(changes []*models.PhraseChange) []models.PhraseChange {
+	values := make([]models.PhraseChange, 0, len(changes))
+	for _, change := range changes {
+		values = append(values, *change)
+	}
+	return values
+}
+
+func TestNormalizeBundleValidatesAutoCreatedThemes(t *testing.T) {
+	bundle := &models.StageBundle{
+		Version: models.StageBundleVersion,
+		Themes:  []*models.BundleTheme{{Name: "  Science  "}},
+		Stages: []*models.BundleStage{
+			{Slug: "ok", Name: "Ok", Theme: "  Programming ", Difficulty: models.DifficultyEasy},
+			{Slug: "blank-theme", Name: "Blank", Theme: "   ", Difficulty: models.DifficultyEasy},
+			{Slug: "long-theme", Name: "Long", Theme: strings.Repeat("é", maxThemeNameLength+1), Difficulty: models.DifficultyEasy},
+		},
+	}
+
+	problems := normalizeBundle(bundle)
+
+	var fields []string
+	for _, field := range problems.Fields {
+		fields = append(fields, field.Field)
+		if field.Message != ErrInvalidThemeName.Error() {
+			t.Errorf("%s: message %q, want %q", field.Field, field.Message, ErrInvalidThemeName.Error())
+		}
+	}
+	if want := []string{"stages[1].theme", "stages[2].theme"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("invalid fields = %v, want %v", fields, want)
+	}
+	if bundle.Themes[0].Name != "Science" || bundle.Stages[0].Theme != "Programming" {
+		t.Errorf("theme names not trimmed: %q, %q", bundle.Themes[0].Name, bundle.Stages[0].Theme)
+	}
+}
+
+func TestStageChangedFieldsIsActive(t *testing.T) {
+	inactive, active := false, true
+	stage := &models.Stage{Name: "Basics", ThemeID: "theme-1", Difficulty: models.DifficultyEasy, IsActive: false}
+	incoming := &models.BundleStage{Name: "Basics", Difficulty: models.DifficultyEasy}
+
+	// Bundle tanpa is_active tidak mengaktifkan ulang stage yang dinonaktifkan admin
+	if fields := stageChangedFields(stage, incoming, "theme-1"); len(fields) != 0 {
+		t.Errorf("omitted is_active: changed %v, want none", fields)
+	}
+	incoming.IsActive = &inactive
+	if fields := stageChangedFields(stage, incoming, "theme-1"); len(fields) != 0 {
+		t.Errorf("same is_active: changed %v, want none", fields)
+	}
+	incoming.IsActive = &active
+	if fields := stageChangedFields(stage, incoming, "theme-1"); !reflect.DeepEqual(fields, []string{"is_active"}) {
+		t.Errorf("is_active true: changed %v, want [is_active]", fields)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+// Publish untuk fakeVersionRepo (game_service_test.go): snapshot phrase
+// mendapat ID baru seperti tabel stage_version_phrases
+func (r *fakeVersionRepo) Publish(ctx context.Context, version *models.StageVersion) error {
+	number := 1
+	for _, existing := range r.versions {
+		if existing.StageID == version.StageID && existing.VersionNumber >= number {
+			number = existing.VersionNumber + 1
+		}
+	}
+	version.ID = fmt.Sprintf("%s-v%d", version.StageID, number)
+	version.VersionNumber = number
+	version.PublishedAt = time.Now()
+	version.PhraseCount = len(version.Phrases)
+
+	snapshot := *version
+	snapshot.Phrases = nil
+	for _, phrase := range version.Phrases {
+		phrase.ID = fmt.Sprintf("%s-%d", version.ID, phrase.SequenceNumber)
+		copied := *phrase
+		snapshot.Phrases = append(snapshot.Phrases, &copied)
+	}
+	r.versions[version.ID] = &snapshot
+	r.published[version.StageID] = version.ID
+	return nil
+}
+
+type draftPhraseRepo struct {
+	repositories.PhraseRepository
+	phrases []*models.Phrase
+}
+
+func (r *draftPhraseRepo) FindByStageID(ctx context.Context, stageID string) ([]*models.Phrase, error) {
+	var phrases []*models.Phrase
+	for _, phrase := range r.phrases {
+		if phrase.StageID == stageID {
+			copied := *phrase
+			phrases = append(phrases, &copied)
+		}
+	}
+	return phrases, nil
+}
+
+// TestPublishedVersionIsolatesPlayersFromDraft mengikuti satu stage dari
+// draft, publish, edit draft, sampai publish berikutnya, dan memeriksa
+// apa yang dilihat dan dinilai untuk pemain di setiap langkah.
+func TestPublishedVersionIsolatesPlayersFromDraft(t *testing.T) {
+	ctx := context.Background()
+	stages := &fakeStageRepo{stages: map[string]*models.Stage{
+		testStageID: {ID: testStageID, Name: "Home row", IsActive: true, WhitespacePolicy: "strict"},
+	}}
+	versions := &fakeVersionRepo{versions: map[string]*models.StageVersion{}, published: map[string]string{}}
+	draft := &draftPhraseRepo{phrases: []*models.Phrase{
+		{ID: "draft-1", StageID: testStageID, Text: "asdf jkl", SequenceNumber: 1, BaseMultiplier: 1},
+		{ID: "draft-2", StageID: testStageID, Text: "fall sad", SequenceNumber: 2, BaseMultiplier: 1},
+	}}
+	admin := NewAdminService(stages, draft, nil, nil, nil, versions, inlineTransactor{}, nil, nil)
+	scores := &fakeScoreRepo{}
+	progress := NewProgressService(&fakeProgressRepo{progress: map[string]*models.UserProgress{}}, fakeUserRepo{}, time.UTC)
+	game := NewGameService(stages, versions, &fakeSessionRepo{sessions: map[string]*models.GameSession{}}, scores,
+		fakeKeyStatsRepo{}, fakePrerequisiteRepo{}, inlineTransactor{}, progress,
+		NewAchievementService(fakeAchievementRepo{}, scores, progress), time.Minute, 30*time.Minute)
+
+	// Draft yang belum pernah dipublish tidak terlihat pemain
+	if _, _, err := game.GetPublishedStage(ctx, testStageID); err != ErrStageNotFound {
+		t.Fatalf("unpublished stage err = %v, want ErrStageNotFound", err)
+	}
+
+	v1, err := admin.PublishStage(ctx, testStageID, "admin", false)
+	if err != nil {
+		t.Fatalf("first publish: %v", err)
+	}
+	if v1.VersionNumber != 1 || v1.Phrases[0].ID == "draft-1" {
+		t.Fatalf("v1 = #%d with phrase %q, want #1 with a snapshot phrase id", v1.VersionNumber, v1.Phrases[0].ID)
+	}
+	_, _, v1Session, err := game.StartSession(ctx, testUserID, testStageID)
+	if err != nil {
+		t.Fatalf("StartSession on v1: %v", err)
+	}
+
+	// Admin mengedit draft: nama dan teks phrase berubah, pemain tetap
+	// melihat versi 1
+	stages.stages[testStageID].Name = "Home row II"
+	draft.phrases[0].Text = "asdf jkl asdf jkl"
+	stage, served, err := game.GetPublishedStage(ctx, testStageID)
+	if err != nil {
+		t.Fatalf("GetPublishedStage after draft edit: %v", err)
+	}
+	if stage.Name != "Home row" || served.ID != v1.ID || served.Phrases[0].Text != "asdf jkl" {
+		t.Errorf("served after draft edit = %q / %s / %q, want v1 unchanged", stage.Name, served.ID, served.Phrases[0].Text)
+	}
+	if _, err := game.SubmitScore(ctx, testUserID, testStageID, v1.ID, "", 5000, 0, nil, nil); err != nil {
+		t.Errorf("submit on current v1: %v", err)
+	}
+
+	v2, err := admin.PublishStage(ctx, testStageID, "admin", false)
+	if err != nil {
+		t.Fatalf("second publish: %v", err)
+	}
+	if _, err := admin.PublishStage(ctx, testStageID, "admin", false); err != ErrNothingToPublish {
+		t.Errorf("publish without draft changes err = %v, want ErrNothingToPublish", err)
+	}
+	stage, served, err = game.GetPublishedStage(ctx, testStageID)
+	if err != nil {
+		t.Fatalf("GetPublishedStage after v2: %v", err)
+	}
+	if stage.Name != "Home row II" || served.ID != v2.ID || served.Phrases[0].Text != "asdf jkl asdf jkl" {
+		t.Errorf("served after publish = %q / %s / %q, want v2", stage.Name, served.ID, served.Phrases[0].Text)
+	}
+
+	// Tanpa sesi, versi lama ditolak; sesi yang dimulai di v1 tetap
+	// dinilai atas phrase v1
+	if _, err := game.SubmitScore(ctx, testUserID, testStageID, v1.ID, "", 5000, 0, nil, nil); err != ErrStageVersionOutdated {
+		t.Errorf("submit on outdated v1 err = %v, want ErrStageVersionOutdated", err)
+	}
+	result, err := game.SubmitScore(ctx, testUserID, testStageID, "", v1Session.ID, 5000, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("submit v1 session after v2: %v", err)
+	}
+	if result.Score.StageVersionID != v1.ID || result.Score.TotalChars != len("asdf jkl")+len("fall sad") {
+		t.Errorf("v1 session scored on %s with %d chars, want v1 with %d", result.Score.StageVersionID, result.Score.TotalChars, len("asdf jkl")+len("fall sad"))
+	}
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidLocale = errors.New("locale must be a language code such as en or id")
+	// ErrDefaultLocaleTranslation: teks locale default adalah nama/deskripsi
+	// stage atau theme itu sendiri
+	ErrDefaultLocaleTranslation = errors.New("the default locale is the stage or theme text itself; update the stage or theme instead")
+	ErrTranslationNotFound      = errors.New("translation not found")
+)
+
+// TranslationService mengelola terjemahan nama stage serta nama dan
+// deskripsi theme, dan memilih teks yang ditampilkan ke pemain sesuai
+// locale yang diminta. Teks asli stage/theme dianggap berbahasa
+// defaultLocale.
+type TranslationService struct {
+	translationRepo repositories.TranslationRepository
+	stageRepo       repositories.StageRepository
+	versionRepo     repositories.StageVersionRepository
+	themeRepo       repositories.ThemeRepository
+	defaultLocale   string
+}
+
+func NewTranslationService(
+	translationRepo repositories.TranslationRepository,
+	stageRepo repositories.StageRepository,
+	versionRepo repositories.StageVersionRepository,
+	themeRepo repositories.ThemeRepository,
+	defaultLocale string,
+) *TranslationService {
+	return &TranslationService{
+		translationRepo: translationRepo,
+		stageRepo:       stageRepo,
+		versionRepo:     versionRepo,
+		themeRepo:       themeRepo,
+		defaultLocale:   defaultLocale,
+	}
+}
+
+// DefaultLocale adalah locale teks asli stage dan theme
+func (s *TranslationService) DefaultLocale() string {
+	return s.defaultLocale
+}
+
+// Stage Translations
+
+func (s *TranslationService) GetStageTranslations(ctx context.Context, stageID string) ([]*models.StageTranslation, error) {
+	if err := s.checkStage(ctx, stageID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.FindStageTranslations(ctx, stageID)
+}
+
+// SetStageTranslation membuat atau mengganti nama stage dalam locale.
+// Locale seperti "en-US" disimpan sebagai "en".
+func (s *TranslationService) SetStageTranslation(ctx context.Context, stageID, locale, name string) (*models.StageTranslation, error) {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkStage(ctx, stageID); err != nil {
+		return nil, err
+	}
+
+	translation := &models.StageTranslation{
+		StageID: stageID,
+		Locale:  locale,
+		Name:    strings.TrimSpace(name),
+	}
+	if err := s.translationRepo.UpsertStageTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (s *TranslationService) DeleteStageTranslation(ctx context.Context, stageID, locale string) error {
+	locale = domainservices.NormalizeLocale(locale)
+	if locale == "" {
+		return ErrTranslationNotFound
+	}
+	if err := s.checkStage(ctx, stageID); err != nil {
+		return err
+	}
+	deleted, err := s.translationRepo.DeleteStageTranslation(ctx, stageID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// Theme Translations
+
+func (s *TranslationService) GetThemeTranslations(ctx context.Context, themeID string) ([]*models.ThemeTranslation, error) {
+	if err := s.checkTheme(ctx, themeID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.FindThemeTranslations(ctx, themeID)
+}
+
+// SetThemeTranslation membuat atau mengganti nama dan deskripsi theme
+// dalam locale
+func (s *TranslationService) SetThemeTranslation(ctx context.Context, themeID, locale, name, description string) (*models.ThemeTranslation, error) {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTheme(ctx, themeID); err != nil {
+		return nil, err
+	}
+
+	translation := &models.ThemeTranslation{
+		ThemeID:     themeID,
+		Locale:      locale,
+		Name:        strings.TrimSpace(name),
+		Description: description,
+	}
+	if err := s.translationRepo.UpsertThemeTranslation(ctx, translation); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (s *TranslationService) DeleteThemeTranslation(ctx context.Context, themeID, locale string) error {
+	locale = domainservices.NormalizeLocale(locale)
+	if locale == "" {
+		return ErrTranslationNotFound
+	}
+	if err := s.checkTheme(ctx, themeID); err != nil {
+		return err
+	}
+	deleted, err := s.translationRepo.DeleteThemeTranslation(ctx, themeID, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+func (s *TranslationService) translationLocale(locale string) (string, error) {
+	locale = domainservices.NormalizeLocale(locale)
+	if locale == "" {
+		return "", ErrInvalidLocale
+	}
+	if locale == s.defaultLocale {
+		return "", ErrDefaultLocaleTranslation
+	}
+	return locale, nil
+}
+
+func (s *TranslationService) checkStage(ctx context.Context, stageID string) error {
+	if _, err := uuid.Parse(stageID); err != nil {
+		return ErrStageNotFound
+	}
+	stage, err := s.stageRepo.FindByID(ctx, stageID)
+	if err != nil {
+		return err
+	}
+	if stage == nil {
+		return ErrStageNotFound
+	}
+	return nil
+}
+
+func (s *TranslationService) checkTheme(ctx context.Context, themeID string) error {
+	if _, err := uuid.Parse(themeID); err != nil {
+		return ErrThemeNotFound
+	}
+	theme, err := s.themeRepo.FindByID(ctx, themeID)
+	if err != nil {
+		return err
+	}
+	if theme == nil {
+		return ErrThemeNotFound
+	}
+	return nil
+}
+
+// Localization
+
+// LocalizeStages mengganti Name setiap stage dengan terjemahan dari locale
+// pertama di preferred yang tersedia untuk stage tersebut. Stage tanpa
+// terjemahan yang cocok tetap memakai teks asli (locale default).
+func (s *TranslationService) LocalizeStages(ctx context.Context, stages []*models.Stage, preferred []string) error {
+	names := make(map[string]string, len(stages))
+	for _, stage := range stages {
+		names[stage.ID] = stage.Name
+	}
+	if err := s.LocalizeStageNames(ctx, names, preferred); err != nil {
+		return err
+	}
+	for _, stage := range stages {
+		stage.Name = names[stage.ID]
+	}
+	return nil
+}
+
+// LocalizeStageNames sama seperti LocalizeStages untuk nama stage yang
+// sudah ada di response lain (riwayat, profil, standings, prerequisite):
+// names adalah stage id → nama asli dan diganti di tempat.
+func (s *TranslationService) LocalizeStageNames(ctx context.Context, names map[string]string, preferred []string) error {
+	locales := s.candidateLocales(preferred)
+	if len(locales) == 0 || len(names) == 0 {
+		return nil
+	}
+	translations, err := s.translationRepo.FindStageTranslationsByLocales(ctx, locales)
+	if err != nil {
+		return err
+	}
+
+	translated := make(map[string]map[string]string)
+	for _, translation := range translations {
+		if translated[translation.StageID] == nil {
+			translated[translation.StageID] = make(map[string]string)
+		}
+		translated[translation.StageID][translation.Locale] = translation.Name
+	}
+	for stageID := range names {
+		byLocale := translated[stageID]
+		locale := domainservices.ResolveLocale(locales, s.defaultLocale, func(locale string) bool {
+			_, ok := byLocale[locale]
+			return ok
+		})
+		if name, ok := byLocale[locale]; ok {
+			names[stageID] = name
+		}
+	}
+	return nil
+}
+
+// PlayerStageNames mengembalikan nama versi published stage-stage
+// stageIDs, dilokalisasi seperti LocalizeStageNames. Stage yang belum
+// pernah dipublish tidak ada di hasil.
+func (s *TranslationService) PlayerStageNames(ctx context.Context, stageIDs []string, preferred []string) (map[string]string, error) {
+	wanted := make(map[string]bool, len(stageIDs))
+	for _, stageID := range stageIDs {
+		wanted[stageID] = true
+	}
+	versions, err := s.versionRepo.FindAllPublished(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(wanted))
+	for _, version := range versions {
+		if wanted[version.StageID] {
+			names[version.StageID] = version.Name
+		}
+	}
+	if err := s.LocalizeStageNames(ctx, names, preferred); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// GetThemes mengembalikan semua theme (urut sort_order) dengan nama dan
+// deskripsi dalam locale pertama di preferred yang tersedia
+func (s *TranslationService) GetThemes(ctx context.Context, preferred []string) ([]*models.Theme, error) {
+	themes, err := s.themeRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locales := s.candidateLocales(preferred)
+	if len(locales) == 0 || len(themes) == 0 {
+		return themes, nil
+	}
+	translations, err := s.translationRepo.FindThemeTranslationsByLocales(ctx, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	byTheme := make(map[string]map[string]*models.ThemeTranslation)
+	for _, translation := range translations {
+		if byTheme[translation.ThemeID] == nil {
+			byTheme[translation.ThemeID] = make(map[string]*models.ThemeTranslation)
+		}
+		byTheme[translation.ThemeID][translation.Locale] = translation
+	}
+	for _, theme := range themes {
+		byLocale := byTheme[theme.ID]
+		locale := domainservices.ResolveLocale(locales, s.defaultLocale, func(locale string) bool {
+			_, ok := byLocale[locale]
+			return ok
+		})
+		if translation, ok := byLocale[locale]; ok {
+			theme.Name = translation.Name
+			theme.Description = translation.Description
+		}
+	}
+	return themes, nil
+}
+
+// candidateLocales adalah locale di preferred sebelum locale default;
+// locale setelahnya tidak pernah dipakai karena teks asli selalu tersedia
+func (s *TranslationService) candidateLocales(preferred []string) []string {
+	var locales []string
+	for _, locale := range preferred {
+		if locale == s.defaultLocale {
+			break
+		}
+		locales = append(locales, locale)
+	}
+	return locales
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+)
+
+// FieldError menjelaskan satu field input yang tidak valid. Field memakai
+// path seperti "stages[0].phrases[2].text".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError mengumpulkan semua field yang tidak valid dari satu
+// request agar bisa dilaporkan sekaligus
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err mengembalikan nil jika tidak ada field yang tidak valid
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// addPhraseIssues menambahkan hasil PhraseLinter. path adalah prefix field
+// (mis. "stages[0].phrases[2]."), multiplierField nama field multiplier di
+// request tersebut.
+func (e *ValidationError) addPhraseIssues(path, multiplierField string, issues []domainservices.PhraseLintIssue) {
+	for _, issue := range issues {
+		field := issue.Field
+		if field == domainservices.PhraseFieldMultiplier {
+			field = multiplierField
+		}
+		e.add(path+field, "%s", issue.Message)
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Achievement adalah definisi badge yang dikelola admin. Params adalah
+// parameter JSON untuk RuleType (mis. {"min_wpm": 60} untuk wpm_reached).
+type Achievement struct {
+	ID          string
+	Code        string
+	Name        string
+	Description string
+	RuleType    string
+	Params      json.RawMessage
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// UserAchievement adalah badge yang sudah didapat user. ScoreID adalah
+// attempt yang memicu badge (0 jika attempt sudah dihapus).
+type UserAchievement struct {
+	AchievementID string
+	ScoreID       int64
+	AwardedAt     time.Time
+}
+
+// PlayerFacts adalah ringkasan progres user yang dibutuhkan rule achievement
+// di luar attempt yang sedang dievaluasi
+type PlayerFacts struct {
+	StreakDays      int
+	TotalAttempts   int
+	StagesCompleted int
+	// CompletedThemes berisi theme yang semua stage aktifnya sudah diselesaikan
+	CompletedThemes map[string]bool
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Attempt adalah satu score di riwayat permainan user, beserta nama stage
+type Attempt struct {
+	Score
+	StageName string
+}
+
+// StageBest adalah best attempt user di satu stage
+type StageBest struct {
+	StageID      string
+	StageName    string
+	Best         Score
+	AttemptCount int
+	LastPlayedAt time.Time
+}
+
+// AttemptCursor adalah posisi keyset di riwayat attempt (completed_at DESC, id DESC)
+type AttemptCursor struct {
+	CompletedAt time.Time
+	ScoreID     int64
+}
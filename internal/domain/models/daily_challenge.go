@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// DailyChallenge adalah set phrase harian yang sama untuk semua pemain.
+// Phrases adalah snapshot: ID/StageID menunjuk phrase asal (kosong jika
+// phrase asal sudah dihapus).
+type DailyChallenge struct {
+	ID        string
+	Date      time.Time // tanggal challenge, 00:00 UTC
+	Seed      int64
+	Phrases   []*Phrase
+	CreatedAt time.Time
+}
+
+// DailyChallengeAttempt adalah satu attempt daily challenge. Hanya attempt
+// pertama di hari yang sama yang ranked; sisanya replay tanpa ranking.
+type DailyChallengeAttempt struct {
+	ID          int64
+	ChallengeID string
+	UserID      string
+	FinalScore  float64
+	TotalTimeMs int
+	TotalErrors int
+	TotalChars  int
+	Wpm         float64
+	Accuracy    float64
+	Ranked      bool
+	CompletedAt time.Time
+}
+
+// DailyChallengeSession adalah satu run daily challenge yang dimulai di
+// server. Tanggal challenge dan status ranked ditentukan saat sesi dimulai,
+// sehingga run yang melewati pergantian hari tetap dinilai untuk challenge
+// yang dimainkan.
+type DailyChallengeSession struct {
+	ID            string
+	ChallengeID   string
+	ChallengeDate time.Time // tanggal challenge, 00:00 UTC
+	UserID        string
+	Ranked        bool // challenge adalah challenge hari ini saat sesi dimulai
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	SubmittedAt   *time.Time // nil selama belum disubmit
+}
+
+// ExpiredAt bernilai true jika sesi sudah tidak bisa disubmit pada t
+func (s *DailyChallengeSession) ExpiredAt(t time.Time) bool {
+	return !t.Before(s.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// GameSession mencatat phrase yang disajikan ke pemain saat memulai stage,
+// supaya score dinilai dari versi dan phrase yang benar-benar dimainkan
+type GameSession struct {
+	ID             string
+	UserID         string
+	StageID        string
+	StageVersionID string
+	Seed           int64
+	PhraseIDs      []string // id phrase versi, sesuai urutan tampil
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+	SubmittedAt    *time.Time // nil selama belum disubmit
+}
+
+// ExpiredAt bernilai true jika sesi sudah tidak bisa disubmit pada t
+func (s *GameSession) ExpiredAt(t time.Time) bool {
+	return !t.Before(s.ExpiresAt)
+}
@@ -0,0 +1,65 @@
+package models
+
+// TypingMistake adalah satu karakter yang salah diketik di sebuah phrase.
+// Position adalah index karakter (rune) di teks phrase yang diketik (setelah
+// whitespace policy stage diterapkan); Typed adalah karakter yang diketik
+// pemain sebagai ganti Expected (kosong jika tidak diketahui); LatencyMs
+// adalah jeda sejak keystroke sebelumnya (0 jika tidak diketahui).
+type TypingMistake struct {
+	PhraseID  string
+	Position  int
+	Expected  string
+	Typed     string
+	LatencyMs int
+}
+
+// TypingLatency adalah jeda sebelum setiap karakter phrase diketik.
+// LatenciesMs[i] untuk karakter ke-i teks yang diketik (0 jika tidak
+// diketahui).
+type TypingLatency struct {
+	PhraseID    string
+	LatenciesMs []int
+}
+
+// KeyStat adalah statistik kumulatif user untuk satu karakter atau bigram.
+// Presses dihitung dari teks phrase yang dimainkan; latency dari semua
+// keystroke yang jedanya dikirim client, mistake latency hanya dari
+// keystroke yang salah.
+type KeyStat struct {
+	Key                   string
+	Presses               int
+	Errors                int
+	TotalLatencyMs        int64
+	LatencySamples        int
+	TotalMistakeLatencyMs int64
+	MistakeLatencySamples int
+}
+
+// KeySubstitution adalah jumlah kumulatif user salah mengetik Typed
+// sebagai ganti Expected, untuk melihat karakter mana yang sering tertukar
+type KeySubstitution struct {
+	Expected string
+	Typed    string
+	Count    int
+}
+
+func (s *KeyStat) ErrorRate() float64 {
+	if s.Presses == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Presses) * 100
+}
+
+func (s *KeyStat) AvgLatencyMs() float64 {
+	if s.LatencySamples == 0 {
+		return 0
+	}
+	return float64(s.TotalLatencyMs) / float64(s.LatencySamples)
+}
+
+func (s *KeyStat) AvgMistakeLatencyMs() float64 {
+	if s.MistakeLatencySamples == 0 {
+		return 0
+	}
+	return float64(s.TotalMistakeLatencyMs) / float64(s.MistakeLatencySamples)
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+)
+
+// LeaderboardPeriod menentukan jendela waktu yang dipakai untuk ranking
+type LeaderboardPeriod string
+
+const (
+	PeriodDaily   LeaderboardPeriod = "daily"
+	PeriodWeekly  LeaderboardPeriod = "weekly"
+	PeriodMonthly LeaderboardPeriod = "monthly"
+	PeriodAllTime LeaderboardPeriod = "all_time"
+	// PeriodSeason hanya menghitung score di season yang sedang aktif
+	PeriodSeason LeaderboardPeriod = "season"
+)
+
+func (p LeaderboardPeriod) IsValid() bool {
+	switch p {
+	case PeriodDaily, PeriodWeekly, PeriodMonthly, PeriodAllTime, PeriodSeason:
+		return true
+	}
+	return false
+}
+
+// WindowStart returns the inclusive start of the window containing now,
+// computed on the wall clock of loc. Weeks start on Monday. The zero time
+// is returned for PeriodAllTime and PeriodSeason.
+func (p LeaderboardPeriod) WindowStart(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	year, month, day := local.Date()
+
+	switch p {
+	case PeriodDaily:
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case PeriodWeekly:
+		offset := (int(local.Weekday()) + 6) % 7 // Monday = 0
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, loc)
+	case PeriodMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+	return time.Time{}
+}
+
+// ScoreWindow membatasi attempt yang dihitung di leaderboard. Zero value
+// berarti all-time.
+type ScoreWindow struct {
+	Since    time.Time
+	SeasonID string
+}
+
+func (w ScoreWindow) IsAllTime() bool {
+	return w.Since.IsZero() && w.SeasonID == ""
+}
+
+// AggregationMethod menentukan cara menggabungkan best score tiap stage
+// menjadi satu nilai pada leaderboard agregat
+type AggregationMethod string
+
+const (
+	// AggregationNormalizedSum: best score tiap stage dibagi best score
+	// tertinggi di stage tersebut (skala 0-1000), lalu dijumlahkan
+	AggregationNormalizedSum AggregationMethod = "normalized_sum"
+	// AggregationAveragePercentile: rata-rata percentile (0-100) pemain
+	// di setiap stage dalam scope
+	AggregationAveragePercentile AggregationMethod = "average_percentile"
+)
+
+func (m AggregationMethod) IsValid() bool {
+	return m == AggregationNormalizedSum || m == AggregationAveragePercentile
+}
+
+// AggregateLeaderboardEntry adalah satu baris leaderboard agregat.
+// Stage dalam scope yang belum diselesaikan pemain dihitung 0 poin
+// (atau percentile 0), sehingga StagesCompleted ikut ditampilkan.
+type AggregateLeaderboardEntry struct {
+	UserID          string
+	Username        string
+	Anonymous       bool
+	AggregateScore  float64
+	StagesCompleted int
+	StagesTotal     int
+	TotalTimeMs     int
+}
+
+// LeaderboardEntry adalah best score satu pemain beserta posisinya
+type LeaderboardEntry struct {
+	Rank        int
+	ScoreID     int64
+	UserID      string
+	Username    string
+	Anonymous   bool // pemain memilih tampil anonim di leaderboard
+	FinalScore  float64
+	TotalTimeMs int
+	TotalErrors int
+	CompletedAt time.Time
+}
+
+// LeaderboardCursor adalah posisi keyset di leaderboard. Urutan ranking:
+// final_score DESC, total_time_ms ASC, user_id ASC.
+type LeaderboardCursor struct {
+	FinalScore  float64
+	TotalTimeMs int
+	UserID      string
+}
@@ -0,0 +1,27 @@
+package models
+
+// PhraseAnalysis adalah hasil analisis kompleksitas satu phrase. Semua
+// fitur bernilai 0..1 kecuali Length dan FingerTravel.
+type PhraseAnalysis struct {
+	PhraseID       string // kosong untuk teks yang belum disimpan
+	Text           string
+	BaseMultiplier float64 // multiplier saat ini, 0 untuk teks yang belum disimpan
+	Length         int     // jumlah karakter (rune) yang diketik sesuai whitespace policy
+
+	SymbolDensity   float64 // proporsi angka dan tanda baca
+	ShiftRatio      float64 // proporsi karakter yang butuh shift
+	FingerTravel    float64 // rata-rata jarak jari per karakter (satuan lebar tombol)
+	RareBigramRatio float64 // proporsi bigram huruf yang jarang muncul
+
+	Score               float64 // 0 (mudah) .. 1 (sulit)
+	SuggestedMultiplier float64
+}
+
+// StageAnalysis adalah ringkasan analisis semua phrase stage
+type StageAnalysis struct {
+	StageID             string
+	Difficulty          string // difficulty stage saat ini, kosong untuk teks bebas
+	Score               float64
+	SuggestedDifficulty string
+	Phrases             []*PhraseAnalysis
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// StatsBucket adalah agregat attempt user dalam satu hari atau satu minggu.
+// ErrorRate adalah jumlah error per 100 karakter; Rolling* adalah rata-rata
+// bergulir dari beberapa bucket terakhir (termasuk bucket ini).
+type StatsBucket struct {
+	Start              time.Time
+	Attempts           int
+	AvgWpm             float64
+	BestWpm            float64
+	AvgAccuracy        float64
+	BestAccuracy       float64
+	ErrorRate          float64
+	TimePlayedMs       int64
+	RollingAvgWpm      float64
+	RollingAvgAccuracy float64
+}
+
+// PlayerStats adalah statistik perkembangan user sejak Since. WpmTrend dan
+// AccuracyTrend adalah slope regresi linear per hari (positif berarti membaik).
+type PlayerStats struct {
+	Since         time.Time
+	Attempts      int
+	Daily         []*StatsBucket
+	Weekly        []*StatsBucket
+	WpmTrend      float64
+	AccuracyTrend float64
+}
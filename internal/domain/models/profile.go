@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayerSummary adalah ringkasan aktivitas pemain untuk profil publik
+type PlayerSummary struct {
+	TotalAttempts   int
+	StagesCompleted int
+	BestWpm         float64
+	LastPlayedAt    *time.Time
+}
+
+// Placement adalah posisi best score pemain di leaderboard all-time sebuah stage
+type Placement struct {
+	StageID     string
+	StageName   string
+	Rank        int
+	FinalScore  float64
+	TotalTimeMs int
+}
+
+// PlayerProfile adalah profil publik pemain. TopPlacements kosong jika
+// pemain memilih anonim di leaderboard dan yang melihat bukan pemain itu sendiri.
+type PlayerProfile struct {
+	User           *User
+	Summary        *PlayerSummary
+	RecentAttempts []*Attempt
+	TopPlacements  []*Placement
+}
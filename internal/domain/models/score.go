@@ -5,11 +5,21 @@ import (
 )
 
 type Score struct {
+	ID          int64
 	UserID      string
 	StageID     string
+	SeasonID    string
 	FinalScore  float64
 	TotalTimeMs int
 	TotalErrors int
+	TotalChars  int
+	Wpm         float64
+	Accuracy    float64
 	CompletedAt time.Time
-}
 
+	// StageVersionID adalah versi stage yang dimainkan
+	StageVersionID string
+
+	// IsPersonalBest diisi oleh ScoreRepository.Create
+	IsPersonalBest bool
+}
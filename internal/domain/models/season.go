@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+type Season struct {
+	ID        string
+	Name      string
+	Status    string
+	StartsAt  time.Time
+	EndsAt    *time.Time
+	ClosedAt  *time.Time
+	CreatedAt time.Time
+}
+
+const (
+	SeasonStatusActive = "active"
+	SeasonStatusClosed = "closed"
+)
+
+func (s *Season) IsActive() bool {
+	return s.Status == SeasonStatusActive
+}
+
+// SeasonStanding adalah satu baris final standings yang dibekukan saat
+// season ditutup
+type SeasonStanding struct {
+	SeasonID    string
+	StageID     string
+	StageName   string
+	Rank        int
+	UserID      string
+	Username    string
+	Anonymous   bool // diambil dari pengaturan privasi user saat ini
+	FinalScore  float64
+	TotalTimeMs int
+	TotalErrors int
+	CompletedAt time.Time
+}
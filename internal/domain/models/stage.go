@@ -6,12 +6,51 @@ import (
 
 type Stage struct {
 	ID         string
+	Slug       string // identitas stabil antar environment (import/export)
 	Name       string
 	ThemeID    string
 	Difficulty string
 	IsActive   bool
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+
+	// PublishedVersionID kosong jika stage belum pernah dipublish
+	PublishedVersionID string
+	// LeaderboardMinVersion: score dari versi lebih lama tidak masuk leaderboard
+	LeaderboardMinVersion int
+
+	// Jadwal buka/tutup stage (nil = tanpa batas), dipakai untuk event
+	// seperti ujian. Berlaku bersama IsActive.
+	AvailableFrom  *time.Time
+	AvailableUntil *time.Time
+
+	// PhraseCharset adalah himpunan karakter yang boleh dipakai phrase
+	// (qwerty, latin atau unicode)
+	PhraseCharset string
+
+	// PhrasePoolSize > 0: setiap sesi memainkan sejumlah phrase acak dari
+	// versi published; 0 berarti semua phrase sesuai urutan
+	PhrasePoolSize int
+
+	// WhitespacePolicy menentukan spasi mana dari phrase multi-baris yang
+	// diketik pemain (strict, collapse atau auto_indent)
+	WhitespacePolicy string
+
+	// PhraseLanguage adalah bahasa teks phrase (mis. "en"), kosong jika
+	// belum ditentukan
+	PhraseLanguage string
+}
+
+// AvailableAt melaporkan apakah t berada di dalam jadwal stage
+// (AvailableFrom inklusif, AvailableUntil eksklusif)
+func (s *Stage) AvailableAt(t time.Time) bool {
+	if s.AvailableFrom != nil && t.Before(*s.AvailableFrom) {
+		return false
+	}
+	if s.AvailableUntil != nil && !t.Before(*s.AvailableUntil) {
+		return false
+	}
+	return true
 }
 
 const (
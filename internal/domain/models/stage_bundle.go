@@ -0,0 +1,84 @@
+package models
+
+// StageBundleVersion adalah versi format bundle yang didukung import/export
+const StageBundleVersion = 1
+
+// StageBundle adalah konten stage yang bisa dipindah antar environment.
+// Stage diidentifikasi lewat slug dan theme lewat nama, bukan UUID.
+type StageBundle struct {
+	Version int
+	Themes  []*BundleTheme
+	Stages  []*BundleStage
+}
+
+// BundleTheme membawa metadata theme. Theme yang hanya disebut oleh stage
+// (tanpa entri di sini) dipakai apa adanya atau dibuat tanpa metadata.
+type BundleTheme struct {
+	Name        string
+	Description string
+	Color       string
+	IconKey     string
+	SortOrder   int
+}
+
+type BundleStage struct {
+	Slug       string
+	Name       string
+	Theme      string // nama theme
+	Difficulty string
+	IsActive   *bool           // nil = tidak diubah (true untuk stage baru)
+	Phrases    []*BundlePhrase // urutan = sequence_number
+
+	PhraseCharset    string // kosong = tidak diubah (qwerty untuk stage baru)
+	PhrasePoolSize   *int   // nil = tidak diubah (0 untuk stage baru)
+	WhitespacePolicy string // kosong = tidak diubah (strict untuk stage baru)
+	PhraseLanguage   string // kosong = tidak diubah
+}
+
+type BundlePhrase struct {
+	Text       string
+	Multiplier *float64 // nil = pakai saran PhraseAnalyzer
+}
+
+// Aksi pada hasil import
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionAdd       = "add"
+	ImportActionRemove    = "remove"
+)
+
+// ImportReport adalah diff antara bundle dan isi database. Pada dry run
+// report dihitung tanpa ada perubahan yang disimpan.
+type ImportReport struct {
+	DryRun bool
+	Themes []*ThemeChange
+	Stages []*StageChange
+}
+
+type ThemeChange struct {
+	Name   string
+	Action string
+	Fields []string // field yang berubah (untuk update)
+}
+
+type StageChange struct {
+	Slug    string
+	StageID string // kosong untuk stage baru pada dry run
+	Action  string
+	Fields  []string
+	Phrases []*PhraseChange // hanya phrase yang berubah
+}
+
+// PhraseChange menjelaskan perubahan satu phrase. Position berbasis 1 dan
+// merujuk ke urutan baru (add/update) atau urutan lama (remove).
+type PhraseChange struct {
+	Position      int
+	Action        string
+	Text          string
+	OldText       string
+	Multiplier    float64
+	OldMultiplier float64
+	Moved         bool
+}
@@ -0,0 +1,27 @@
+package models
+
+// StagePrerequisite adalah syarat untuk membuka StageID: attempt terbaik di
+// RequiredStageID harus mencapai MinStars bintang dan MinAccuracy persen
+type StagePrerequisite struct {
+	StageID             string
+	RequiredStageID     string
+	RequiredStageName   string
+	RequiredStageActive bool
+	MinStars            int
+	MinAccuracy         float64
+}
+
+// RequirementStatus adalah progres user terhadap satu prerequisite
+type RequirementStatus struct {
+	Prerequisite *StagePrerequisite
+	BestAccuracy float64
+	Stars        int
+	Met          bool
+}
+
+// StageProgress adalah stage beserta status terkunci untuk seorang user
+type StageProgress struct {
+	Stage        *Stage
+	Locked       bool
+	Requirements []*RequirementStatus
+}
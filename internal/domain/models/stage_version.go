@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// StageVersion adalah snapshot immutable dari draft stage saat dipublish.
+// Pemain memainkan versi published terbaru; setiap score mencatat versi
+// yang dimainkan.
+type StageVersion struct {
+	ID               string
+	StageID          string
+	VersionNumber    int
+	Name             string
+	WhitespacePolicy string // policy penilaian phrase versi ini
+	PhrasePoolSize   int    // 0 = semua phrase berurutan
+	PhraseLanguage   string // bahasa teks phrase, kosong jika tidak diisi
+	LeaderboardReset bool   // publish ini me-reset leaderboard stage
+	PublishedBy      string // kosong jika admin sudah dihapus
+	PublishedAt      time.Time
+	PhraseCount      int
+	Phrases          []*Phrase // hanya diisi saat versi diambil lengkap
+}
+
+// StageVersionHistory adalah daftar versi stage (terbaru lebih dulu) dan
+// apakah draft berbeda dari versi published terakhir
+type StageVersionHistory struct {
+	Versions              []*StageVersion
+	HasUnpublishedChanges bool
+}
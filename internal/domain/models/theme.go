@@ -8,6 +8,9 @@ type Theme struct {
 	ID          string
 	Name        string
 	Description string
+	Color       string // hex #RRGGBB, kosong = default client
+	IconKey     string
+	SortOrder   int
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
-
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// StageTranslation adalah nama stage dalam locale selain locale default.
+// Locale adalah subtag bahasa utama (mis. "en").
+type StageTranslation struct {
+	StageID   string
+	Locale    string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ThemeTranslation adalah nama dan deskripsi theme dalam locale selain
+// locale default
+type ThemeTranslation struct {
+	ThemeID     string
+	Locale      string
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -5,12 +5,16 @@ import (
 )
 
 type User struct {
-	ID           string
-	Username     string
-	PasswordHash string
-	Role         string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                   string
+	Username             string
+	PasswordHash         string
+	Role                 string
+	DisplayName          string
+	ProfileVisibility    string // private: profil publik disembunyikan dari pemain lain
+	LeaderboardAnonymous bool   // username disembunyikan di leaderboard
+	Timezone             string // IANA timezone untuk streak harian; kosong = default server
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 const (
@@ -18,6 +22,11 @@ const (
 	RoleAdmin = "admin"
 )
 
+const (
+	ProfileVisibilityPublic  = "public"
+	ProfileVisibilityPrivate = "private"
+)
+
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
@@ -25,3 +34,27 @@ func (u *User) IsAdmin() bool {
 func (u *User) IsUser() bool {
 	return u.Role == RoleUser
 }
+
+// Name adalah nama yang ditampilkan: display name jika ada, selain itu username
+func (u *User) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
+func (u *User) IsProfilePublic() bool {
+	return u.ProfileVisibility != ProfileVisibilityPrivate
+}
+
+// Location mengembalikan timezone user, atau fallback jika kosong/tidak valid
+func (u *User) Location(fallback *time.Location) *time.Location {
+	if u.Timezone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// UserProgress adalah XP, level, dan streak harian user. LastPlayedOn adalah
+// tanggal (00:00 UTC) attempt terakhir di timezone user; zero jika belum
+// pernah bermain.
+type UserProgress struct {
+	UserID        string
+	XP            int64
+	Level         int
+	CurrentStreak int
+	LongestStreak int
+	LastPlayedOn  time.Time
+	UpdatedAt     time.Time
+}
@@ -2,9 +2,27 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"uwika_quick_typer_game/internal/domain/models"
 )
 
+var (
+	// ErrDuplicate dikembalikan Create/Update jika melanggar unique constraint
+	ErrDuplicate = errors.New("record already exists")
+	// ErrReferenced dikembalikan Delete jika baris masih direferensikan
+	// tabel lain (foreign key ON DELETE RESTRICT)
+	ErrReferenced = errors.New("record is still referenced")
+)
+
+// Transactor menjalankan fn dalam satu transaksi database. Repository yang
+// dipanggil dengan ctx dari fn ikut dalam transaksi tersebut; transaksi
+// di-commit jika fn mengembalikan nil dan di-rollback jika tidak.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	FindByID(ctx context.Context, userID string) (*models.User, error)
@@ -22,31 +40,226 @@ type TokenRepository interface {
 }
 
 type ThemeRepository interface {
+	Create(ctx context.Context, theme *models.Theme) error
+	// FindAll mengembalikan semua theme urut sort_order lalu nama
 	FindAll(ctx context.Context) ([]*models.Theme, error)
 	FindByID(ctx context.Context, themeID string) (*models.Theme, error)
+	FindByName(ctx context.Context, name string) (*models.Theme, error)
+	Update(ctx context.Context, theme *models.Theme) error
+	Delete(ctx context.Context, themeID string) error
+}
+
+// TranslationRepository menyimpan terjemahan teks tampilan stage dan theme
+type TranslationRepository interface {
+	// FindStageTranslations mengembalikan semua terjemahan stage, urut locale
+	FindStageTranslations(ctx context.Context, stageID string) ([]*models.StageTranslation, error)
+	// FindStageTranslationsByLocales mengembalikan terjemahan semua stage
+	// dalam locale yang diminta
+	FindStageTranslationsByLocales(ctx context.Context, locales []string) ([]*models.StageTranslation, error)
+	// UpsertStageTranslation membuat atau mengganti terjemahan (stage, locale)
+	UpsertStageTranslation(ctx context.Context, translation *models.StageTranslation) error
+	// DeleteStageTranslation mengembalikan false jika terjemahan tidak ada
+	DeleteStageTranslation(ctx context.Context, stageID, locale string) (bool, error)
+
+	FindThemeTranslations(ctx context.Context, themeID string) ([]*models.ThemeTranslation, error)
+	FindThemeTranslationsByLocales(ctx context.Context, locales []string) ([]*models.ThemeTranslation, error)
+	UpsertThemeTranslation(ctx context.Context, translation *models.ThemeTranslation) error
+	DeleteThemeTranslation(ctx context.Context, themeID, locale string) (bool, error)
 }
 
 type StageRepository interface {
 	Create(ctx context.Context, stage *models.Stage) error
 	FindByID(ctx context.Context, stageID string) (*models.Stage, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Stage, error)
 	FindAll(ctx context.Context) ([]*models.Stage, error)
 	FindAllActive(ctx context.Context) ([]*models.Stage, error)
 	Update(ctx context.Context, stage *models.Stage) error
 	Delete(ctx context.Context, stageID string) error
 }
 
+// StageVersionRepository menyimpan versi stage yang sudah dipublish.
+// Versi tidak pernah diubah setelah dibuat.
+type StageVersionRepository interface {
+	// Publish menyimpan versi baru beserta phrase-nya dengan nomor versi
+	// berikutnya dan menjadikannya versi published stage. Jika
+	// version.LeaderboardReset, leaderboard stage dimulai ulang dari versi ini.
+	// ID phrase di version.Phrases adalah phrase draft asal dan diganti
+	// dengan ID snapshot.
+	Publish(ctx context.Context, version *models.StageVersion) error
+	// FindByID mengembalikan versi beserta phrase-nya (nil jika tidak ada)
+	FindByID(ctx context.Context, versionID string) (*models.StageVersion, error)
+	// FindPublished mengembalikan versi published stage beserta phrase-nya
+	// (nil jika stage belum pernah dipublish)
+	FindPublished(ctx context.Context, stageID string) (*models.StageVersion, error)
+	// FindByStageID mengembalikan semua versi stage tanpa phrase, terbaru dulu
+	FindByStageID(ctx context.Context, stageID string) ([]*models.StageVersion, error)
+	// FindAllPublished mengembalikan versi published setiap stage tanpa phrase
+	FindAllPublished(ctx context.Context) ([]*models.StageVersion, error)
+}
+
+// GameSessionRepository menyimpan phrase yang disajikan per sesi permainan
+type GameSessionRepository interface {
+	// Start menyimpan session sebagai sesi terbuka user untuk stage dan versi
+	// tersebut, kecuali sudah ada sesi terbuka yang belum kedaluwarsa: sesi
+	// itu yang dikembalikan. Sesi terbuka stage tersebut yang sudah
+	// kedaluwarsa pada now dihapus.
+	Start(ctx context.Context, session *models.GameSession, now time.Time) (*models.GameSession, error)
+	// FindByID mengembalikan sesi (nil jika tidak ada)
+	FindByID(ctx context.Context, sessionID string) (*models.GameSession, error)
+	// MarkSubmitted menandai sesi sudah disubmit. Mengembalikan false jika
+	// sesi sudah pernah disubmit sebelumnya.
+	MarkSubmitted(ctx context.Context, sessionID string, submittedAt time.Time) (bool, error)
+}
+
+type StagePrerequisiteRepository interface {
+	FindAll(ctx context.Context) ([]*models.StagePrerequisite, error)
+	FindByStageID(ctx context.Context, stageID string) ([]*models.StagePrerequisite, error)
+	// ReplaceForStage mengganti semua prerequisite stage dalam satu transaksi
+	ReplaceForStage(ctx context.Context, stageID string, prerequisites []*models.StagePrerequisite) error
+}
+
 type PhraseRepository interface {
 	Create(ctx context.Context, phrase *models.Phrase) error
 	FindByID(ctx context.Context, phraseID string) (*models.Phrase, error)
 	FindByStageID(ctx context.Context, stageID string) ([]*models.Phrase, error)
+	// FindFromActiveStages mengembalikan phrase versi published semua stage
+	// aktif yang sedang dalam jadwalnya pada now. ID adalah phrase draft asalnya; phrase yang draft-nya sudah
+	// dihapus dilewati.
+	FindFromActiveStages(ctx context.Context, now time.Time) ([]*models.Phrase, error)
 	Update(ctx context.Context, phrase *models.Phrase) error
 	Delete(ctx context.Context, phraseID string) error
+	// Resequence memberi sequence_number 1..n sesuai urutan phraseIDs dalam
+	// satu transaksi. phraseIDs harus berisi semua phrase milik stage.
+	Resequence(ctx context.Context, stageID string, phraseIDs []string) error
+}
+
+type DailyChallengeRepository interface {
+	// FindByDate mengembalikan challenge beserta phrase-nya (nil jika belum dibuat)
+	FindByDate(ctx context.Context, date time.Time) (*models.DailyChallenge, error)
+	// Create menyimpan challenge beserta phrase-nya. Jika challenge untuk
+	// tanggal yang sama sudah ada (dibuat instance lain), tidak ada yang diubah.
+	Create(ctx context.Context, challenge *models.DailyChallenge) error
+	// CreateAttempt menyimpan attempt. Attempt disimpan ranked hanya jika
+	// ranked true dan user belum punya attempt ranked di challenge ini;
+	// attempt.Ranked diisi sesuai hasilnya.
+	CreateAttempt(ctx context.Context, attempt *models.DailyChallengeAttempt, ranked bool) error
+	HasRankedAttempt(ctx context.Context, challengeID, userID string) (bool, error)
+	FindLeaderboard(ctx context.Context, challengeID string, limit int) ([]*models.LeaderboardEntry, error)
+	// FindUserRank mengembalikan posisi attempt ranked user (nil jika belum ada)
+	FindUserRank(ctx context.Context, challengeID, userID string) (*models.LeaderboardEntry, error)
+	// StartSession menyimpan session sebagai sesi terbuka user untuk
+	// challenge tersebut, kecuali sudah ada sesi terbuka yang belum
+	// kedaluwarsa: sesi itu yang dikembalikan. Sesi terbuka challenge
+	// tersebut yang sudah kedaluwarsa pada now dihapus.
+	StartSession(ctx context.Context, session *models.DailyChallengeSession, now time.Time) (*models.DailyChallengeSession, error)
+	// FindSession mengembalikan sesi beserta tanggal challenge-nya (nil jika tidak ada)
+	FindSession(ctx context.Context, sessionID string) (*models.DailyChallengeSession, error)
+	// MarkSessionSubmitted menandai sesi sudah disubmit. Mengembalikan false
+	// jika sesi sudah pernah disubmit sebelumnya.
+	MarkSessionSubmitted(ctx context.Context, sessionID string, submittedAt time.Time) (bool, error)
+}
+
+type UserProgressRepository interface {
+	// FindByUserID mengembalikan progres user (nil jika belum pernah bermain)
+	FindByUserID(ctx context.Context, userID string) (*models.UserProgress, error)
+	// FindByUserIDForUpdate membuat baris progres jika belum ada lalu
+	// mengunci baris tersebut. Harus dipanggil di dalam Transactor.
+	FindByUserIDForUpdate(ctx context.Context, userID string) (*models.UserProgress, error)
+	Save(ctx context.Context, progress *models.UserProgress) error
 }
 
 type ScoreRepository interface {
 	Create(ctx context.Context, score *models.Score) error
 	FindByUserAndStage(ctx context.Context, userID, stageID string) (*models.Score, error)
-	FindLeaderboardByStage(ctx context.Context, stageID string, limit int) ([]*models.Score, error)
-	FindByUserID(ctx context.Context, userID string) ([]*models.Score, error)
+	FindLeaderboardByStage(ctx context.Context, query LeaderboardQuery) ([]*models.LeaderboardEntry, error)
+	FindLeaderboardAroundUser(ctx context.Context, stageID string, window models.ScoreWindow, userID string, radius int) ([]*models.LeaderboardEntry, error)
+	FindByUserID(ctx context.Context, query ScoreHistoryQuery) ([]*models.Attempt, error)
+	FindBestsByUser(ctx context.Context, userID string) ([]*models.StageBest, error)
+	// FindBestAccuracyByUser mengembalikan accuracy terbaik user per stage
+	FindBestAccuracyByUser(ctx context.Context, userID string) (map[string]float64, error)
+	// FindLatestScoreID mengembalikan id attempt terakhir user (0 jika belum ada)
+	FindLatestScoreID(ctx context.Context, userID string) (int64, error)
+	FindPlayerStats(ctx context.Context, query PlayerStatsQuery) (*models.PlayerStats, error)
+	FindPlayerSummary(ctx context.Context, userID string) (*models.PlayerSummary, error)
+	// FindCompletedThemes mengembalikan theme yang semua stage aktif dan
+	// published-nya sudah punya attempt dari user
+	FindCompletedThemes(ctx context.Context, userID string) (map[string]bool, error)
+	// FindTopPlacements mengembalikan stage aktif di mana best score user
+	// berada di rank <= maxRank leaderboard all-time, rank terbaik lebih dulu
+	FindTopPlacements(ctx context.Context, userID string, maxRank, limit int) ([]*models.Placement, error)
+	FindAggregateLeaderboard(ctx context.Context, filter AggregateLeaderboardFilter) ([]*models.AggregateLeaderboardEntry, error)
+}
+
+// LeaderboardQuery mengambil satu halaman leaderboard stage. After nil berarti
+// halaman pertama.
+type LeaderboardQuery struct {
+	StageID string
+	Window  models.ScoreWindow
+	After   *models.LeaderboardCursor
+	Limit   int
+}
+
+// ScoreHistoryQuery mengambil riwayat attempt user, terbaru lebih dulu.
+// StageID, From (inklusif) dan To (eksklusif) opsional.
+type ScoreHistoryQuery struct {
+	UserID  string
+	StageID string
+	From    time.Time
+	To      time.Time
+	After   *models.AttemptCursor
+	Limit   int
+}
+
+// AggregateLeaderboardFilter membatasi stage yang ikut dihitung. ThemeID dan
+// Difficulty kosong berarti semua stage aktif (global).
+type AggregateLeaderboardFilter struct {
+	ThemeID    string
+	Difficulty string
+	Method     models.AggregationMethod
+	Window     models.ScoreWindow
+	Limit      int
 }
 
+// PlayerStatsQuery mengagregasi attempt user sejak Since ke dalam bucket
+// harian dan mingguan. Batas hari/minggu mengikuti Location.
+type PlayerStatsQuery struct {
+	UserID   string
+	Since    time.Time
+	Location *time.Location
+}
+
+// KeyStatsRepository menyimpan statistik kumulatif per karakter dan per
+// bigram untuk heatmap keyboard
+type KeyStatsRepository interface {
+	// Record menambahkan delta statistik dari satu attempt
+	Record(ctx context.Context, userID string, keys, bigrams []*models.KeyStat, substitutions []*models.KeySubstitution) error
+	FindKeysByUser(ctx context.Context, userID string) ([]*models.KeyStat, error)
+	FindBigramsByUser(ctx context.Context, userID string, minPresses, limit int) ([]*models.KeyStat, error)
+	// FindSubstitutionsByUser mengembalikan pasangan expected/typed yang
+	// paling sering tertukar
+	FindSubstitutionsByUser(ctx context.Context, userID string, limit int) ([]*models.KeySubstitution, error)
+}
+
+type AchievementRepository interface {
+	Create(ctx context.Context, achievement *models.Achievement) error
+	FindByID(ctx context.Context, achievementID string) (*models.Achievement, error)
+	FindAll(ctx context.Context) ([]*models.Achievement, error)
+	FindActive(ctx context.Context) ([]*models.Achievement, error)
+	Update(ctx context.Context, achievement *models.Achievement) error
+	Delete(ctx context.Context, achievementID string) error
+	FindByUser(ctx context.Context, userID string) ([]*models.UserAchievement, error)
+	// Award mencatat badge untuk user; false jika user sudah memilikinya
+	Award(ctx context.Context, userID, achievementID string, scoreID int64) (bool, error)
+}
+
+type SeasonRepository interface {
+	Create(ctx context.Context, season *models.Season) error
+	FindByID(ctx context.Context, seasonID string) (*models.Season, error)
+	FindAll(ctx context.Context) ([]*models.Season, error)
+	FindActive(ctx context.Context) (*models.Season, error)
+	FindExpired(ctx context.Context, now time.Time) ([]*models.Season, error)
+	// Close menutup season aktif dan membekukan final standings per stage
+	// dalam satu transaksi
+	Close(ctx context.Context, seasonID string) (*models.Season, error)
+	FindStandings(ctx context.Context, seasonID, stageID string, limit int) ([]*models.SeasonStanding, error)
+}
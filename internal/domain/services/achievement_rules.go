@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+// Rule type achievement. Parameter tiap rule:
+//
+//	wpm_reached       {"min_wpm": 60, "difficulty": "hard"}        difficulty opsional
+//	accuracy_reached  {"min_accuracy": 100, "difficulty": "hard"}  difficulty opsional
+//	score_reached     {"min_score": 500, "stage_id": "..."}        stage_id opsional
+//	attempts_count    {"count": 100}
+//	stages_completed  {"count": 10}
+//	daily_streak      {"days": 7}
+//	theme_completed   {"theme_id": "..."}                          theme_id opsional (theme mana saja)
+const (
+	RuleWpmReached      = "wpm_reached"
+	RuleAccuracyReached = "accuracy_reached"
+	RuleScoreReached    = "score_reached"
+	RuleAttemptsCount   = "attempts_count"
+	RuleStagesCompleted = "stages_completed"
+	RuleDailyStreak     = "daily_streak"
+	RuleThemeCompleted  = "theme_completed"
+)
+
+var (
+	ErrUnknownRuleType   = &DomainError{Code: "UNKNOWN_RULE_TYPE", Message: "unknown achievement rule type"}
+	ErrInvalidRuleParams = &DomainError{Code: "INVALID_RULE_PARAMS", Message: "invalid achievement rule params"}
+)
+
+// AchievementInput adalah data yang tersedia saat evaluasi setelah score
+// disimpan. Facts hanya diisi jika ada rule yang membutuhkannya.
+type AchievementInput struct {
+	Score *models.Score
+	Stage *models.Stage
+	Facts *models.PlayerFacts
+}
+
+// AchievementRule memeriksa apakah satu achievement terpenuhi
+type AchievementRule interface {
+	Satisfied(input *AchievementInput) bool
+	// NeedsFacts bernilai true jika rule membutuhkan input.Facts
+	NeedsFacts() bool
+}
+
+// ParseAchievementRule membuat rule dari rule_type dan params, sekaligus
+// memvalidasi params. Field yang tidak dikenal ditolak agar salah ketik admin
+// tidak diam-diam membuat achievement yang selalu terpenuhi.
+func ParseAchievementRule(ruleType string, params json.RawMessage) (AchievementRule, error) {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	var rule AchievementRule
+	switch ruleType {
+	case RuleWpmReached:
+		rule = &wpmReachedRule{}
+	case RuleAccuracyReached:
+		rule = &accuracyReachedRule{}
+	case RuleScoreReached:
+		rule = &scoreReachedRule{}
+	case RuleAttemptsCount:
+		rule = &attemptsCountRule{}
+	case RuleStagesCompleted:
+		rule = &stagesCompletedRule{}
+	case RuleDailyStreak:
+		rule = &dailyStreakRule{}
+	case RuleThemeCompleted:
+		rule = &themeCompletedRule{}
+	default:
+		return nil, ErrUnknownRuleType
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(rule); err != nil {
+		return nil, ErrInvalidRuleParams
+	}
+	if v, ok := rule.(interface{ valid() bool }); ok && !v.valid() {
+		return nil, ErrInvalidRuleParams
+	}
+	return rule, nil
+}
+
+type wpmReachedRule struct {
+	MinWpm     float64 `json:"min_wpm"`
+	Difficulty string  `json:"difficulty"`
+}
+
+func (r *wpmReachedRule) valid() bool {
+	return r.MinWpm > 0 && validRuleDifficulty(r.Difficulty)
+}
+
+func (r *wpmReachedRule) Satisfied(input *AchievementInput) bool {
+	return matchesDifficulty(input.Stage, r.Difficulty) && input.Score.Wpm >= r.MinWpm
+}
+
+func (r *wpmReachedRule) NeedsFacts() bool { return false }
+
+type accuracyReachedRule struct {
+	MinAccuracy float64 `json:"min_accuracy"`
+	Difficulty  string  `json:"difficulty"`
+}
+
+func (r *accuracyReachedRule) valid() bool {
+	return r.MinAccuracy > 0 && r.MinAccuracy <= 100 && validRuleDifficulty(r.Difficulty)
+}
+
+func (r *accuracyReachedRule) Satisfied(input *AchievementInput) bool {
+	return matchesDifficulty(input.Stage, r.Difficulty) && input.Score.Accuracy >= r.MinAccuracy
+}
+
+func (r *accuracyReachedRule) NeedsFacts() bool { return false }
+
+type scoreReachedRule struct {
+	MinScore float64 `json:"min_score"`
+	StageID  string  `json:"stage_id"`
+}
+
+func (r *scoreReachedRule) valid() bool {
+	return r.MinScore > 0
+}
+
+func (r *scoreReachedRule) Satisfied(input *AchievementInput) bool {
+	if r.StageID != "" && input.Score.StageID != r.StageID {
+		return false
+	}
+	return input.Score.FinalScore >= r.MinScore
+}
+
+func (r *scoreReachedRule) NeedsFacts() bool { return false }
+
+type attemptsCountRule struct {
+	Count int `json:"count"`
+}
+
+func (r *attemptsCountRule) valid() bool { return r.Count > 0 }
+
+func (r *attemptsCountRule) Satisfied(input *AchievementInput) bool {
+	return input.Facts.TotalAttempts >= r.Count
+}
+
+func (r *attemptsCountRule) NeedsFacts() bool { return true }
+
+type stagesCompletedRule struct {
+	Count int `json:"count"`
+}
+
+func (r *stagesCompletedRule) valid() bool { return r.Count > 0 }
+
+func (r *stagesCompletedRule) Satisfied(input *AchievementInput) bool {
+	return input.Facts.StagesCompleted >= r.Count
+}
+
+func (r *stagesCompletedRule) NeedsFacts() bool { return true }
+
+type dailyStreakRule struct {
+	Days int `json:"days"`
+}
+
+func (r *dailyStreakRule) valid() bool { return r.Days > 0 }
+
+func (r *dailyStreakRule) Satisfied(input *AchievementInput) bool {
+	return input.Facts.StreakDays >= r.Days
+}
+
+func (r *dailyStreakRule) NeedsFacts() bool { return true }
+
+type themeCompletedRule struct {
+	ThemeID string `json:"theme_id"`
+}
+
+func (r *themeCompletedRule) Satisfied(input *AchievementInput) bool {
+	if r.ThemeID != "" {
+		return input.Facts.CompletedThemes[r.ThemeID]
+	}
+	return len(input.Facts.CompletedThemes) > 0
+}
+
+func (r *themeCompletedRule) NeedsFacts() bool { return true }
+
+func validRuleDifficulty(difficulty string) bool {
+	switch difficulty {
+	case "", models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard:
+		return true
+	}
+	return false
+}
+
+func matchesDifficulty(stage *models.Stage, difficulty string) bool {
+	return difficulty == "" || stage.Difficulty == difficulty
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+// DailyChallengePhraseCount adalah jumlah phrase dalam satu daily challenge
+const DailyChallengePhraseCount = 10
+
+// DailySeed menurunkan seed deterministik dari tanggal (YYYYMMDD)
+func DailySeed(date time.Time) int64 {
+	year, month, day := date.Date()
+	return int64(year*10000 + int(month)*100 + day)
+}
+
+// SelectDailyPhrases memilih count phrase dari pool secara acak dengan seed
+// tertentu
+func SelectDailyPhrases(pool []*models.Phrase, seed int64, count int) []*models.Phrase {
+	return drawPhrases(pool, seed, count)
+}
+
+// DrawPhrasePool memilih count phrase dalam urutan acak dari phrase versi
+// stage untuk satu sesi permainan. count lebih besar dari isi versi berarti
+// semua phrase diacak urutannya.
+func DrawPhrasePool(phrases []*models.Phrase, seed int64, count int) []*models.Phrase {
+	return drawPhrases(phrases, seed, count)
+}
+
+// drawPhrases mengacak pool dengan seed lalu mengambil count phrase
+// pertama. Pool diurutkan dulu berdasarkan ID sehingga hasilnya hanya
+// bergantung pada isi pool dan seed, bukan urutan dari database.
+func drawPhrases(pool []*models.Phrase, seed int64, count int) []*models.Phrase {
+	candidates := make([]*models.Phrase, 0, len(pool))
+	for _, phrase := range pool {
+		if phrase.Text != "" {
+			candidates = append(candidates, phrase)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	return candidates
+}
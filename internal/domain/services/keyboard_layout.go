@@ -0,0 +1,141 @@
+package services
+
+// Finger adalah jari yang menekan sebuah tombol (touch typing standar)
+type Finger int
+
+const (
+	LeftPinky Finger = iota
+	LeftRing
+	LeftMiddle
+	LeftIndex
+	Thumb
+	RightIndex
+	RightMiddle
+	RightRing
+	RightPinky
+)
+
+// IsLeftHand - thumb dianggap netral (bukan tangan kiri maupun kanan)
+func (f Finger) IsLeftHand() bool {
+	return f < Thumb
+}
+
+// KeyPosition adalah posisi fisik karakter di keyboard. Key adalah label
+// tombol tanpa shift (mis. 'a' untuk 'A', '1' untuk '!').
+type KeyPosition struct {
+	Key    rune
+	Row    int // 0 = baris angka, 1 = QWERTY, 2 = home row, 3 = baris bawah, 4 = spasi
+	Column int
+	Finger Finger
+	Shift  bool
+}
+
+// X adalah posisi horizontal tombol dalam satuan lebar tombol, termasuk
+// pergeseran (stagger) tiap baris
+func (p KeyPosition) X() float64 {
+	return float64(p.Column) + rowStagger[p.Row]
+}
+
+var rowStagger = [...]float64{0, 1.5, 1.75, 2.25, 5}
+
+// KeyboardLayout memetakan karakter ke posisi tombol
+type KeyboardLayout struct {
+	Name string
+	keys map[rune]KeyPosition
+	home map[Finger]KeyPosition
+}
+
+// Lookup mengembalikan posisi tombol untuk karakter r
+func (l *KeyboardLayout) Lookup(r rune) (KeyPosition, bool) {
+	pos, ok := l.keys[r]
+	return pos, ok
+}
+
+// Home mengembalikan tombol home row untuk jari
+func (l *KeyboardLayout) Home(finger Finger) KeyPosition {
+	return l.home[finger]
+}
+
+// Keys mengembalikan semua tombol (tanpa shift) secara berurutan baris lalu kolom
+func (l *KeyboardLayout) Keys() []KeyPosition {
+	var keys []KeyPosition
+	for _, row := range qwertyRows {
+		for _, r := range row.keys {
+			keys = append(keys, l.keys[r])
+		}
+	}
+	return keys
+}
+
+type layoutRow struct {
+	keys    string
+	shifted string
+	fingers []Finger
+}
+
+var qwertyRows = []layoutRow{
+	{
+		keys:    "`1234567890-=",
+		shifted: "~!@#$%^&*()_+",
+		fingers: []Finger{LeftPinky, LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky},
+	},
+	{
+		keys:    "qwertyuiop[]\\",
+		shifted: "QWERTYUIOP{}|",
+		fingers: []Finger{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky, RightPinky},
+	},
+	{
+		keys:    "asdfghjkl;'",
+		shifted: "ASDFGHJKL:\"",
+		fingers: []Finger{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky},
+	},
+	{
+		keys:    "zxcvbnm,./",
+		shifted: "ZXCVBNM<>?",
+		fingers: []Finger{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky},
+	},
+	{
+		keys:    " ",
+		shifted: "",
+		fingers: []Finger{Thumb},
+	},
+}
+
+// qwertyHomeKeys adalah posisi istirahat tiap jari (asdf jkl; + spasi)
+var qwertyHomeKeys = map[Finger]rune{
+	LeftPinky: 'a', LeftRing: 's', LeftMiddle: 'd', LeftIndex: 'f', Thumb: ' ',
+	RightIndex: 'j', RightMiddle: 'k', RightRing: 'l', RightPinky: ';',
+}
+
+// qwertyEnter - Enter berada tepat di kanan tombol ' pada home row. Bisa
+// di-Lookup sebagai '\n' tetapi tidak termasuk Keys() karena heatmap hanya
+// menampilkan tombol karakter.
+var qwertyEnter = KeyPosition{Key: '\n', Row: 2, Column: 11, Finger: RightPinky}
+
+// QWERTY adalah layout US QWERTY yang dipakai client
+var QWERTY = newKeyboardLayout("qwerty", qwertyRows, qwertyHomeKeys, qwertyEnter)
+
+func newKeyboardLayout(name string, rows []layoutRow, homeKeys map[Finger]rune, enter KeyPosition) *KeyboardLayout {
+	layout := &KeyboardLayout{
+		Name: name,
+		keys: make(map[rune]KeyPosition),
+		home: make(map[Finger]KeyPosition),
+	}
+	for rowIndex, row := range rows {
+		keys := []rune(row.keys)
+		shifted := []rune(row.shifted)
+		for col, r := range keys {
+			pos := KeyPosition{Key: r, Row: rowIndex, Column: col, Finger: row.fingers[col]}
+			layout.keys[r] = pos
+			if col < len(shifted) {
+				pos.Shift = true
+				layout.keys[shifted[col]] = pos
+			}
+		}
+	}
+	layout.keys['\n'] = enter
+	for finger, r := range homeKeys {
+		layout.home[finger] = layout.keys[r]
+	}
+	return layout
+}
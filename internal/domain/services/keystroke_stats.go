@@ -0,0 +1,187 @@
+package services
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+// MaxMistakesPerSubmission membatasi jumlah event per submit; event setelah
+// batas ini diabaikan
+const MaxMistakesPerSubmission = 2000
+
+// KeystrokeStats adalah tambahan statistik dari satu attempt, per karakter,
+// per bigram (dua karakter berurutan tanpa whitespace) dan per pasangan
+// karakter expected/typed. Dropped adalah jumlah entry latency atau mistake
+// event yang diabaikan karena tidak cocok dengan phrase.
+type KeystrokeStats struct {
+	Keys          map[string]*models.KeyStat
+	Bigrams       map[string]*models.KeyStat
+	Substitutions map[string]*models.KeySubstitution // expected + typed
+	Dropped       int
+}
+
+// AggregateKeystrokes menghitung presses dari semua phrase stage, latency
+// setiap keystroke dari latencies, lalu menambahkan error, mistake latency
+// dan substitusi dari mistake events. Mistake harus menunjuk phrase di stage,
+// karakter Expected harus sama dengan teks phrase dan Typed (jika ada) satu
+// karakter lain; Position adalah indeks di teks yang diketik sesuai
+// whitespace policy. Latency per phrase harus berisi tepat satu nilai
+// non-negatif per karakter yang diketik.
+//
+// Heatmap hanya telemetry opsional: entry yang tidak valid diabaikan (dan
+// dihitung di Dropped) supaya tidak pernah menggagalkan submit score.
+func AggregateKeystrokes(phrases []*models.Phrase, whitespacePolicy string, latencies []models.TypingLatency, mistakes []models.TypingMistake) *KeystrokeStats {
+	stats := &KeystrokeStats{
+		Keys:          make(map[string]*models.KeyStat),
+		Bigrams:       make(map[string]*models.KeyStat),
+		Substitutions: make(map[string]*models.KeySubstitution),
+	}
+
+	texts := make(map[string][]rune, len(phrases))
+	for _, phrase := range phrases {
+		text := []rune(TypedText(phrase.Text, whitespacePolicy))
+		texts[phrase.ID] = text
+		for i, r := range text {
+			if key, ok := keyOf(r); ok {
+				stats.key(key).Presses++
+			}
+			if bigram, ok := bigramAt(text, i); ok {
+				stats.bigram(bigram).Presses++
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(latencies))
+	for _, latency := range latencies {
+		text, ok := texts[latency.PhraseID]
+		if !ok || seen[latency.PhraseID] || !validLatencies(latency.LatenciesMs, len(text)) {
+			stats.Dropped++
+			continue
+		}
+		seen[latency.PhraseID] = true
+		for i, latencyMs := range latency.LatenciesMs {
+			if key, ok := keyOf(text[i]); ok {
+				recordLatency(stats.key(key), latencyMs)
+			}
+			if bigram, ok := bigramAt(text, i); ok {
+				recordLatency(stats.bigram(bigram), latencyMs)
+			}
+		}
+	}
+
+	if len(mistakes) > MaxMistakesPerSubmission {
+		stats.Dropped += len(mistakes) - MaxMistakesPerSubmission
+		mistakes = mistakes[:MaxMistakesPerSubmission]
+	}
+	for _, mistake := range mistakes {
+		text, ok := texts[mistake.PhraseID]
+		if !ok || !validMistake(mistake, text) {
+			stats.Dropped++
+			continue
+		}
+
+		expected := text[mistake.Position]
+		if key, ok := keyOf(expected); ok {
+			recordMistake(stats.key(key), mistake.LatencyMs)
+		}
+		if bigram, ok := bigramAt(text, mistake.Position); ok {
+			recordMistake(stats.bigram(bigram), mistake.LatencyMs)
+		}
+		if mistake.Typed != "" {
+			stats.substitution(mistake.Expected, mistake.Typed).Count++
+		}
+	}
+
+	return stats
+}
+
+func validLatencies(latencies []int, length int) bool {
+	if len(latencies) != length {
+		return false
+	}
+	for _, latencyMs := range latencies {
+		if latencyMs < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// validMistake - Typed kosong berarti client tidak mengirim karakter yang
+// diketik; selain itu harus tepat satu karakter yang berbeda dari Expected
+func validMistake(mistake models.TypingMistake, text []rune) bool {
+	if mistake.Position < 0 || mistake.Position >= len(text) || mistake.LatencyMs < 0 {
+		return false
+	}
+	if mistake.Expected != string(text[mistake.Position]) {
+		return false
+	}
+	if mistake.Typed == "" {
+		return true
+	}
+	typed, size := utf8.DecodeRuneInString(mistake.Typed)
+	if typed == utf8.RuneError || size != len(mistake.Typed) || mistake.Typed == mistake.Expected {
+		return false
+	}
+	return typed == ' ' || typed == '\n' || unicode.IsPrint(typed)
+}
+
+func (s *KeystrokeStats) key(key string) *models.KeyStat {
+	stat, ok := s.Keys[key]
+	if !ok {
+		stat = &models.KeyStat{Key: key}
+		s.Keys[key] = stat
+	}
+	return stat
+}
+
+func (s *KeystrokeStats) bigram(bigram string) *models.KeyStat {
+	stat, ok := s.Bigrams[bigram]
+	if !ok {
+		stat = &models.KeyStat{Key: bigram}
+		s.Bigrams[bigram] = stat
+	}
+	return stat
+}
+
+func (s *KeystrokeStats) substitution(expected, typed string) *models.KeySubstitution {
+	stat, ok := s.Substitutions[expected+typed]
+	if !ok {
+		stat = &models.KeySubstitution{Expected: expected, Typed: typed}
+		s.Substitutions[expected+typed] = stat
+	}
+	return stat
+}
+
+// keyOf - spasi dihitung, whitespace lain (tab, newline) tidak
+func keyOf(r rune) (string, bool) {
+	if r != ' ' && unicode.IsSpace(r) {
+		return "", false
+	}
+	return string(r), true
+}
+
+// bigramAt adalah pasangan karakter yang diakhiri text[i]
+func bigramAt(text []rune, i int) (string, bool) {
+	if i == 0 || unicode.IsSpace(text[i-1]) || unicode.IsSpace(text[i]) {
+		return "", false
+	}
+	return string(text[i-1 : i+1]), true
+}
+
+func recordLatency(stat *models.KeyStat, latencyMs int) {
+	if latencyMs > 0 {
+		stat.TotalLatencyMs += int64(latencyMs)
+		stat.LatencySamples++
+	}
+}
+
+func recordMistake(stat *models.KeyStat, latencyMs int) {
+	stat.Errors++
+	if latencyMs > 0 {
+		stat.TotalMistakeLatencyMs += int64(latencyMs)
+		stat.MistakeLatencySamples++
+	}
+}
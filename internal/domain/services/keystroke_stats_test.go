@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+func TestAggregateKeystrokesCountsSubstitutions(t *testing.T) {
+	phrases := []*models.Phrase{{ID: "p1", Text: "hi there"}}
+	mistakes := []models.TypingMistake{
+		{PhraseID: "p1", Position: 1, Expected: "i", Typed: "o", LatencyMs: 300},
+		{PhraseID: "p1", Position: 7, Expected: "e", Typed: "r"},
+		{PhraseID: "p1", Position: 5, Expected: "e", Typed: "r"},
+		{PhraseID: "p1", Position: 3, Expected: "t"}, // typed tidak dikirim client lama
+	}
+
+	stats := AggregateKeystrokes(phrases, WhitespaceStrict, nil, mistakes)
+
+	if stats.Dropped != 0 {
+		t.Fatalf("Dropped = %d, want 0", stats.Dropped)
+	}
+	if got := stats.Keys["e"]; got.Presses != 2 || got.Errors != 2 {
+		t.Errorf("key e = %d presses %d errors, want 2 and 2", got.Presses, got.Errors)
+	}
+	if got := stats.Keys["t"].Errors; got != 1 {
+		t.Errorf("key t errors = %d, want 1", got)
+	}
+	if got := stats.Keys["i"]; got.MistakeLatencySamples != 1 || got.TotalMistakeLatencyMs != 300 {
+		t.Errorf("key i mistake latency = %d ms over %d samples, want 300 over 1", got.TotalMistakeLatencyMs, got.MistakeLatencySamples)
+	}
+
+	want := map[string]models.KeySubstitution{
+		"io": {Expected: "i", Typed: "o", Count: 1},
+		"er": {Expected: "e", Typed: "r", Count: 2},
+	}
+	if len(stats.Substitutions) != len(want) {
+		t.Fatalf("Substitutions = %d entries, want %d", len(stats.Substitutions), len(want))
+	}
+	for key, substitution := range want {
+		if got := stats.Substitutions[key]; got == nil || *got != substitution {
+			t.Errorf("Substitutions[%q] = %+v, want %+v", key, got, substitution)
+		}
+	}
+}
+
+func TestAggregateKeystrokesDropsInvalidTelemetry(t *testing.T) {
+	phrases := []*models.Phrase{
+		{ID: "p1", Text: "abc"},
+		{ID: "p2", Text: "de"},
+	}
+	latencies := []models.TypingLatency{
+		{PhraseID: "p1", LatenciesMs: []int{0, 100, 120}},
+		{PhraseID: "p1", LatenciesMs: []int{0, 1, 2}},     // duplikat
+		{PhraseID: "p2", LatenciesMs: []int{0, 100, 120}}, // kelebihan satu nilai
+		{PhraseID: "px", LatenciesMs: []int{0}},           // bukan phrase stage
+	}
+	mistakes := []models.TypingMistake{
+		{PhraseID: "p1", Position: 0, Expected: "a", Typed: "s"},
+		{PhraseID: "p1", Position: 0, Expected: "b", Typed: "s"},  // expected salah
+		{PhraseID: "p1", Position: 9, Expected: "a"},              // di luar teks
+		{PhraseID: "p2", Position: 0, Expected: "d", Typed: "d"},  // typed sama dengan expected
+		{PhraseID: "p2", Position: 0, Expected: "d", Typed: "fg"}, // typed lebih dari satu karakter
+		{PhraseID: "p2", Position: 1, Expected: "e", LatencyMs: -5},
+	}
+
+	stats := AggregateKeystrokes(phrases, WhitespaceStrict, latencies, mistakes)
+
+	if stats.Dropped != 8 {
+		t.Errorf("Dropped = %d, want 8", stats.Dropped)
+	}
+	// Presses selalu dihitung dari teks phrase, apa pun isi telemetry
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		if got := stats.Keys[key].Presses; got != 1 {
+			t.Errorf("key %s presses = %d, want 1", key, got)
+		}
+	}
+	if got := stats.Keys["c"]; got.LatencySamples != 1 || got.TotalLatencyMs != 120 {
+		t.Errorf("key c latency = %d ms over %d samples, want 120 over 1", got.TotalLatencyMs, got.LatencySamples)
+	}
+	if got := stats.Keys["e"].LatencySamples; got != 0 {
+		t.Errorf("key e latency samples = %d, want 0 (invalid p2 latencies ignored)", got)
+	}
+	if got := stats.Keys["a"].Errors; got != 1 {
+		t.Errorf("key a errors = %d, want 1", got)
+	}
+	if got := stats.Keys["d"].Errors + stats.Keys["e"].Errors; got != 0 {
+		t.Errorf("p2 errors = %d, want 0", got)
+	}
+	if len(stats.Substitutions) != 1 || stats.Substitutions["as"] == nil {
+		t.Errorf("Substitutions = %v, want only a→s", stats.Substitutions)
+	}
+}
+
+func TestAggregateKeystrokesCapsMistakes(t *testing.T) {
+	phrases := []*models.Phrase{{ID: "p1", Text: "a"}}
+	mistakes := make([]models.TypingMistake, MaxMistakesPerSubmission+5)
+	for i := range mistakes {
+		mistakes[i] = models.TypingMistake{PhraseID: "p1", Position: 0, Expected: "a"}
+	}
+
+	stats := AggregateKeystrokes(phrases, WhitespaceStrict, nil, mistakes)
+
+	if got := stats.Keys["a"].Errors; got != MaxMistakesPerSubmission {
+		t.Errorf("errors = %d, want %d", got, MaxMistakesPerSubmission)
+	}
+	if stats.Dropped != 5 {
+		t.Errorf("Dropped = %d, want 5", stats.Dropped)
+	}
+}
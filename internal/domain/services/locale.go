@@ -0,0 +1,79 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// localePattern adalah subtag bahasa utama BCP 47 (ISO 639), mis. "en", "id"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// NormalizeLocale mengambil subtag bahasa utama dari language tag
+// ("en-US" dan "EN_us" menjadi "en"). Mengembalikan "" jika tag tidak valid.
+func NormalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if !localePattern.MatchString(tag) {
+		return ""
+	}
+	return tag
+}
+
+// ParseAcceptLanguage mengembalikan locale dari header Accept-Language urut
+// prioritas (q tertinggi dulu, lalu urutan di header). Tag yang tidak valid,
+// wildcard "*" dan q=0 diabaikan; locale yang sama hanya muncul sekali.
+func ParseAcceptLanguage(header string) []string {
+	type preference struct {
+		locale string
+		q      float64
+	}
+	var preferences []preference
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+
+		locale := NormalizeLocale(tag)
+		if locale == "" || q <= 0 || seen[locale] {
+			continue
+		}
+		seen[locale] = true
+		preferences = append(preferences, preference{locale: locale, q: q})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].q > preferences[j].q
+	})
+	locales := make([]string, 0, len(preferences))
+	for _, p := range preferences {
+		locales = append(locales, p.locale)
+	}
+	return locales
+}
+
+// ResolveLocale memilih locale pertama dari preferred yang tersedia.
+// defaultLocale selalu tersedia (teks asli) dan dipakai jika tidak ada
+// locale yang cocok.
+func ResolveLocale(preferred []string, defaultLocale string, available func(locale string) bool) string {
+	for _, locale := range preferred {
+		if locale == defaultLocale || available(locale) {
+			return locale
+		}
+	}
+	return defaultLocale
+}
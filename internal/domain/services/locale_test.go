@@ -0,0 +1,34 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"empty header", "", []string{}},
+		{"single tag", "id", []string{"id"}},
+		{"region is dropped", "en-US", []string{"en"}},
+		{"higher q first", "en;q=0.5, id;q=0.9", []string{"id", "en"}},
+		{"missing q is 1", "en;q=0.8, id", []string{"id", "en"}},
+		{"equal q keeps header order", "fr;q=0.7, de;q=0.7, en;q=0.7", []string{"fr", "de", "en"}},
+		{"browser header", "fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr", "en", "de"}},
+		{"duplicate keeps first occurrence", "en;q=0.2, id;q=0.5, en-GB;q=0.9", []string{"id", "en"}},
+		{"q=0 is ignored", "en;q=0, id", []string{"id"}},
+		{"invalid q is ignored", "en;q=abc, id;q=0.1", []string{"id"}},
+		{"wildcard and invalid tags are ignored", "*, 123, x, ja", []string{"ja"}},
+		{"whitespace around params", " en ; q = 0.3 ,id ; q=0.6", []string{"id", "en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
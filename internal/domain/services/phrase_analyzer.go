@@ -0,0 +1,214 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+// ComplexityFactor adalah bobot satu fitur kompleksitas. Saturation adalah
+// nilai mentah fitur yang sudah dianggap paling sulit (skor fitur = 1).
+type ComplexityFactor struct {
+	Weight     float64
+	Saturation float64
+}
+
+// PhraseAnalyzerConfig menentukan bobot fitur, rentang multiplier yang
+// disarankan dan batas skor tiap difficulty. Nilainya sengaja dipisah dari
+// kode supaya bisa dikalibrasi ulang dari data attempt.
+type PhraseAnalyzerConfig struct {
+	Length        ComplexityFactor // jumlah karakter
+	SymbolDensity ComplexityFactor // proporsi angka dan tanda baca
+	ShiftUsage    ComplexityFactor // proporsi karakter dengan shift
+	FingerTravel  ComplexityFactor // rata-rata jarak dari home row
+	RareBigrams   ComplexityFactor // proporsi bigram huruf yang jarang
+
+	MinMultiplier float64
+	MaxMultiplier float64
+
+	// Skor stage >= MediumScore menjadi medium, >= HardScore menjadi hard
+	MediumScore float64
+	HardScore   float64
+}
+
+// DefaultPhraseAnalyzerConfig - simbol dan jarak jari diberi bobot lebih
+// karena paling sering membuat pemain salah ketik pada phrase kode
+var DefaultPhraseAnalyzerConfig = PhraseAnalyzerConfig{
+	Length:        ComplexityFactor{Weight: 1.0, Saturation: 80},
+	SymbolDensity: ComplexityFactor{Weight: 1.5, Saturation: 0.4},
+	ShiftUsage:    ComplexityFactor{Weight: 1.0, Saturation: 0.25},
+	FingerTravel:  ComplexityFactor{Weight: 1.5, Saturation: 2.0},
+	RareBigrams:   ComplexityFactor{Weight: 1.0, Saturation: 0.5},
+
+	MinMultiplier: 0.8,
+	MaxMultiplier: 2.5,
+
+	MediumScore: 0.35,
+	HardScore:   0.6,
+}
+
+// PhraseAnalyzer menilai seberapa sulit phrase diketik di sebuah layout
+// keyboard lalu menyarankan multiplier phrase dan difficulty stage
+type PhraseAnalyzer struct {
+	layout *KeyboardLayout
+	config PhraseAnalyzerConfig
+}
+
+func NewPhraseAnalyzer(layout *KeyboardLayout, config PhraseAnalyzerConfig) *PhraseAnalyzer {
+	return &PhraseAnalyzer{layout: layout, config: config}
+}
+
+// Analyze menghitung fitur dan skor satu phrase atas karakter yang diketik
+// sesuai whitespace policy (lihat TypedText); baris baru dihitung sebagai
+// Enter. Karakter yang tidak ada di layout dihitung sebagai simbol dengan
+// jarak jari maksimum.
+func (a *PhraseAnalyzer) Analyze(text, whitespacePolicy string) *models.PhraseAnalysis {
+	runes := []rune(TypedText(text, whitespacePolicy))
+	analysis := &models.PhraseAnalysis{Text: text, Length: len(runes)}
+	if len(runes) == 0 {
+		analysis.SuggestedMultiplier = a.multiplier(0)
+		return analysis
+	}
+
+	var symbols, shifted int
+	var travel float64
+	for _, r := range runes {
+		pos, ok := a.layout.Lookup(r)
+		switch {
+		case !ok:
+			// mis. huruf beraksen yang butuh dead key atau input khusus
+			symbols++
+			travel += a.config.FingerTravel.Saturation
+			continue
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbols++
+		}
+		if pos.Shift {
+			shifted++
+		}
+		travel += keyDistance(a.layout.Home(pos.Finger), pos)
+	}
+
+	count := float64(len(runes))
+	analysis.SymbolDensity = round4(float64(symbols) / count)
+	analysis.ShiftRatio = round4(float64(shifted) / count)
+	analysis.FingerTravel = round4(travel / count)
+	analysis.RareBigramRatio = round4(rareBigramRatio(runes))
+
+	factors := []struct {
+		factor ComplexityFactor
+		value  float64
+	}{
+		{a.config.Length, float64(analysis.Length)},
+		{a.config.SymbolDensity, analysis.SymbolDensity},
+		{a.config.ShiftUsage, analysis.ShiftRatio},
+		{a.config.FingerTravel, analysis.FingerTravel},
+		{a.config.RareBigrams, analysis.RareBigramRatio},
+	}
+	var score, weights float64
+	for _, f := range factors {
+		if f.factor.Weight <= 0 || f.factor.Saturation <= 0 {
+			continue
+		}
+		score += f.factor.Weight * math.Min(f.value/f.factor.Saturation, 1)
+		weights += f.factor.Weight
+	}
+	if weights > 0 {
+		score /= weights
+	}
+
+	analysis.Score = round4(score)
+	analysis.SuggestedMultiplier = a.multiplier(score)
+	return analysis
+}
+
+// AnalyzeStage menganalisis semua phrase dan menyarankan difficulty dari
+// rata-rata skor yang dibobot panjang phrase
+func (a *PhraseAnalyzer) AnalyzeStage(phrases []*models.Phrase, whitespacePolicy string) *models.StageAnalysis {
+	stage := &models.StageAnalysis{Phrases: make([]*models.PhraseAnalysis, 0, len(phrases))}
+	var weighted float64
+	var totalLength int
+	for _, phrase := range phrases {
+		analysis := a.Analyze(phrase.Text, whitespacePolicy)
+		analysis.PhraseID = phrase.ID
+		analysis.BaseMultiplier = phrase.BaseMultiplier
+		stage.Phrases = append(stage.Phrases, analysis)
+		weighted += analysis.Score * float64(analysis.Length)
+		totalLength += analysis.Length
+	}
+	if totalLength == 0 {
+		return stage
+	}
+
+	stage.Score = round4(weighted / float64(totalLength))
+	stage.SuggestedDifficulty = a.Difficulty(stage.Score)
+	return stage
+}
+
+// Difficulty memetakan skor ke tier easy/medium/hard
+func (a *PhraseAnalyzer) Difficulty(score float64) string {
+	switch {
+	case score >= a.config.HardScore:
+		return models.DifficultyHard
+	case score >= a.config.MediumScore:
+		return models.DifficultyMedium
+	}
+	return models.DifficultyEasy
+}
+
+// multiplier memetakan skor 0..1 secara linear ke rentang multiplier,
+// dibulatkan ke kelipatan 0.05
+func (a *PhraseAnalyzer) multiplier(score float64) float64 {
+	value := a.config.MinMultiplier + score*(a.config.MaxMultiplier-a.config.MinMultiplier)
+	return math.Round(math.Round(value*20)/20*100) / 100
+}
+
+// keyDistance adalah jarak Euclid antar tombol dalam satuan lebar tombol
+func keyDistance(from, to KeyPosition) float64 {
+	dx := to.X() - from.X()
+	dy := float64(to.Row - from.Row)
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// rareBigramRatio adalah proporsi pasangan huruf berurutan (dalam satu
+// kata) yang tidak termasuk commonBigrams
+func rareBigramRatio(runes []rune) float64 {
+	var total, rare int
+	for i := 1; i < len(runes); i++ {
+		prev, cur := unicode.ToLower(runes[i-1]), unicode.ToLower(runes[i])
+		if !unicode.IsLetter(prev) || !unicode.IsLetter(cur) {
+			continue
+		}
+		total++
+		if !commonBigrams[string([]rune{prev, cur})] {
+			rare++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(rare) / float64(total)
+}
+
+func round4(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// commonBigrams adalah bigram huruf yang paling sering muncul di teks
+// Inggris, Indonesia dan keyword pemrograman
+var commonBigrams = func() map[string]bool {
+	list := strings.Fields(`
+		th he in er an re on at en nd ti es or te of ed is it al ar st to nt
+		ng se ha as ou io le ve co me de hi ri ro ic ne ea ra ce li ch ll be
+		ma si om ur ka ah la ya da ak ga ba em pe ta ke di sa am ny ja il un
+		ik ir ul us ap na ku mu ni pa tu ru lu ut ig et el ue fo fu nc
+		ct pr rn ls if im po rt ef va ns cl tr ge ss ol ot rs ac ow wh
+	`)
+	set := make(map[string]bool, len(list))
+	for _, bigram := range list {
+		set[bigram] = true
+	}
+	return set
+}()
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+func TestPhraseAnalyzerAnalyze(t *testing.T) {
+	analyzer := NewPhraseAnalyzer(QWERTY, DefaultPhraseAnalyzerConfig)
+	snippet := "def f():\n    return 1"
+
+	tests := []struct {
+		name   string
+		text   string
+		policy string
+		want   models.PhraseAnalysis
+	}{
+		{"empty", "", WhitespaceStrict, models.PhraseAnalysis{
+			SuggestedMultiplier: 0.8,
+		}},
+		{"common word", "the", WhitespaceStrict, models.PhraseAnalysis{
+			Length: 3, FingerTravel: 1.0936, Score: 0.143, SuggestedMultiplier: 1.05,
+		}},
+		{"home row has no travel", "asdf jkl", WhitespaceStrict, models.PhraseAnalysis{
+			Length: 8, RareBigramRatio: 0.8, Score: 0.1833, SuggestedMultiplier: 1.1,
+		}},
+		{"rare bigrams", "qzxj", WhitespaceStrict, models.PhraseAnalysis{
+			Length: 4, FingerTravel: 0.8167, RareBigramRatio: 1, Score: 0.2771, SuggestedMultiplier: 1.25,
+		}},
+		{"punctuation and shift", "Hello, World!", WhitespaceStrict, models.PhraseAnalysis{
+			Length: 13, SymbolDensity: 0.1538, ShiftRatio: 0.2308, FingerTravel: 0.7237,
+			RareBigramRatio: 0.5, Score: 0.5342, SuggestedMultiplier: 1.7,
+		}},
+		{"symbols only", "{[<@#$%^&*>]}", WhitespaceStrict, models.PhraseAnalysis{
+			Length: 13, SymbolDensity: 1, ShiftRatio: 0.8462, FingerTravel: 1.8554,
+			Score: 0.6757, SuggestedMultiplier: 1.95,
+		}},
+		{"snippet strict", snippet, WhitespaceStrict, models.PhraseAnalysis{
+			Length: 21, SymbolDensity: 0.1905, ShiftRatio: 0.1429, FingerTravel: 0.7586,
+			Score: 0.3529, SuggestedMultiplier: 1.4,
+		}},
+		{"snippet collapse", snippet, WhitespaceCollapse, models.PhraseAnalysis{
+			Length: 18, SymbolDensity: 0.2222, ShiftRatio: 0.1667, FingerTravel: 0.885,
+			Score: 0.3981, SuggestedMultiplier: 1.5,
+		}},
+		{"snippet auto indent", snippet, WhitespaceAutoIndent, models.PhraseAnalysis{
+			Length: 17, SymbolDensity: 0.2353, ShiftRatio: 0.1765, FingerTravel: 0.9371,
+			Score: 0.4173, SuggestedMultiplier: 1.5,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			want.Text = tt.text
+			if got := analyzer.Analyze(tt.text, tt.policy); *got != want {
+				t.Errorf("Analyze(%q, %q) = %+v, want %+v", tt.text, tt.policy, *got, want)
+			}
+		})
+	}
+}
+
+func TestPhraseAnalyzerScoreOrdering(t *testing.T) {
+	analyzer := NewPhraseAnalyzer(QWERTY, DefaultPhraseAnalyzerConfig)
+
+	tests := []struct {
+		name         string
+		easy, harder string
+	}{
+		{"longer", "asdf", "asdf asdf asdf asdf"},
+		{"symbols", "print value", "print(value);"},
+		{"shift", "hello world", "HELLO WORLD"},
+		{"finger travel", "asdf", "qpzm"},
+		{"rare bigrams", "then", "xqjz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			easy := analyzer.Analyze(tt.easy, WhitespaceStrict)
+			harder := analyzer.Analyze(tt.harder, WhitespaceStrict)
+			if easy.Score >= harder.Score {
+				t.Errorf("score %q = %v, want lower than %q = %v", tt.easy, easy.Score, tt.harder, harder.Score)
+			}
+			if easy.SuggestedMultiplier > harder.SuggestedMultiplier {
+				t.Errorf("multiplier %q = %v, want at most %q = %v", tt.easy, easy.SuggestedMultiplier, tt.harder, harder.SuggestedMultiplier)
+			}
+		})
+	}
+}
+
+func TestPhraseAnalyzerMultiplierRange(t *testing.T) {
+	config := DefaultPhraseAnalyzerConfig
+	analyzer := NewPhraseAnalyzer(QWERTY, config)
+
+	for _, text := range []string{"", "a", "the quick brown fox", "~!@#$%^&*()_+{}|:\"<>?", "ñandú", "日本語"} {
+		multiplier := analyzer.Analyze(text, WhitespaceStrict).SuggestedMultiplier
+		if multiplier < config.MinMultiplier || multiplier > config.MaxMultiplier {
+			t.Errorf("Analyze(%q) multiplier = %v, want between %v and %v", text, multiplier, config.MinMultiplier, config.MaxMultiplier)
+		}
+		if steps := multiplier * 20; math.Abs(steps-math.Round(steps)) > 1e-9 {
+			t.Errorf("Analyze(%q) multiplier = %v, want a multiple of 0.05", text, multiplier)
+		}
+	}
+}
+
+func TestPhraseAnalyzerDifficulty(t *testing.T) {
+	analyzer := NewPhraseAnalyzer(QWERTY, DefaultPhraseAnalyzerConfig)
+
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{0, models.DifficultyEasy},
+		{0.3499, models.DifficultyEasy},
+		{0.35, models.DifficultyMedium},
+		{0.5999, models.DifficultyMedium},
+		{0.6, models.DifficultyHard},
+		{1, models.DifficultyHard},
+	}
+	for _, tt := range tests {
+		if got := analyzer.Difficulty(tt.score); got != tt.want {
+			t.Errorf("Difficulty(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestPhraseAnalyzerAnalyzeStage(t *testing.T) {
+	analyzer := NewPhraseAnalyzer(QWERTY, DefaultPhraseAnalyzerConfig)
+
+	tests := []struct {
+		name       string
+		texts      []string
+		score      float64
+		difficulty string
+	}{
+		{"no phrases", nil, 0, ""},
+		{"empty phrase only", []string{""}, 0, ""},
+		{"single phrase", []string{"the"}, 0.143, models.DifficultyEasy},
+		// (0.143×3 + 0.6757×13) / 16
+		{"weighted by length", []string{"the", "{[<@#$%^&*>]}"}, 0.5758, models.DifficultyMedium},
+		{"hard phrases", []string{"{[<@#$%^&*>]}", "{[<@#$%^&*>]}"}, 0.6757, models.DifficultyHard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phrases := make([]*models.Phrase, 0, len(tt.texts))
+			for _, text := range tt.texts {
+				phrases = append(phrases, &models.Phrase{Text: text, BaseMultiplier: 1})
+			}
+			got := analyzer.AnalyzeStage(phrases, WhitespaceStrict)
+			if got.Score != tt.score || got.SuggestedDifficulty != tt.difficulty {
+				t.Errorf("AnalyzeStage(%q) = score %v difficulty %q, want %v %q", tt.texts, got.Score, got.SuggestedDifficulty, tt.score, tt.difficulty)
+			}
+			if len(got.Phrases) != len(tt.texts) {
+				t.Errorf("AnalyzeStage(%q) analyzed %d phrases, want %d", tt.texts, len(got.Phrases), len(tt.texts))
+			}
+		})
+	}
+}
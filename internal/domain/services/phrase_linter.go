@@ -0,0 +1,203 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Charset phrase per stage
+const (
+	// PhraseCharsetQWERTY hanya mengizinkan karakter yang bisa diketik
+	// langsung di layout US QWERTY (default)
+	PhraseCharsetQWERTY = "qwerty"
+	// PhraseCharsetLatin menambahkan huruf Latin beraksen (é, ñ, ü, ...)
+	PhraseCharsetLatin = "latin"
+	// PhraseCharsetUnicode mengizinkan semua karakter printable
+	PhraseCharsetUnicode = "unicode"
+)
+
+// ValidPhraseCharset memeriksa nama charset phrase
+func ValidPhraseCharset(charset string) bool {
+	switch charset {
+	case PhraseCharsetQWERTY, PhraseCharsetLatin, PhraseCharsetUnicode:
+		return true
+	}
+	return false
+}
+
+// PhraseLintConfig adalah batas yang diperiksa PhraseLinter. Panjang
+// dihitung dalam karakter (rune), bukan byte. MinLength berlaku untuk
+// seluruh teks, MaxLength untuk setiap baris.
+type PhraseLintConfig struct {
+	MinLength     int
+	MaxLength     int
+	MaxLines      int
+	MinMultiplier float64
+	MaxMultiplier float64
+}
+
+// DefaultPhraseLintConfig - 120 karakter per baris dan 12 baris masih muat
+// di layar mobile
+var DefaultPhraseLintConfig = PhraseLintConfig{
+	MinLength:     1,
+	MaxLength:     120,
+	MaxLines:      12,
+	MinMultiplier: 0.5,
+	MaxMultiplier: 3.0,
+}
+
+// phraseTabWidth adalah jumlah spasi pengganti tab karena keyboard mobile
+// tidak punya tombol tab
+const phraseTabWidth = 4
+
+// Field yang dilaporkan PhraseLintIssue
+const (
+	PhraseFieldText       = "text"
+	PhraseFieldMultiplier = "multiplier"
+)
+
+// PhraseLintIssue adalah satu aturan yang dilanggar phrase
+type PhraseLintIssue struct {
+	Field   string
+	Message string
+}
+
+// PhraseLinter memeriksa isi phrase sebelum disimpan (create, update dan
+// import) agar semua phrase bisa diketik dengan nyaman oleh pemain
+type PhraseLinter struct {
+	config PhraseLintConfig
+}
+
+func NewPhraseLinter(config PhraseLintConfig) *PhraseLinter {
+	return &PhraseLinter{config: config}
+}
+
+// Config mengembalikan batas yang dipakai linter
+func (l *PhraseLinter) Config() PhraseLintConfig {
+	return l.config
+}
+
+// NormalizeText merapikan whitespace. Baris baru (CRLF/CR menjadi LF)
+// dipertahankan untuk snippet multi-baris: tab menjadi 4 spasi, whitespace
+// lain (NBSP, dll.) menjadi spasi, spasi di akhir baris dan baris kosong di
+// awal/akhir dibuang, baris kosong berturut-turut menjadi satu dan
+// indentasi yang sama di semua baris dihapus. Teks satu baris tetap
+// diringkas seperti sebelumnya: setiap deretan whitespace menjadi satu spasi.
+func (l *PhraseLinter) NormalizeText(text string) string {
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", strings.Repeat(" ", phraseTabWidth)).Replace(text)
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(strings.Map(toSpace, line), unicode.IsSpace)
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		return strings.Join(strings.Fields(strings.Join(lines, "")), " ")
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " ")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toSpace mengganti whitespace selain spasi dengan spasi
+func toSpace(r rune) rune {
+	if unicode.IsSpace(r) {
+		return ' '
+	}
+	return r
+}
+
+// Lint memeriksa text (yang sudah dinormalisasi) dan multiplier terhadap
+// config dan charset stage. Hasil kosong berarti phrase valid.
+func (l *PhraseLinter) Lint(text string, multiplier float64, charset string) []PhraseLintIssue {
+	var issues []PhraseLintIssue
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, PhraseLintIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(text)
+	lines := strings.Split(text, "\n")
+	switch {
+	case text == "":
+		add(PhraseFieldText, "is required")
+	case length < l.config.MinLength:
+		add(PhraseFieldText, "must be at least %d characters (got %d)", l.config.MinLength, length)
+	case len(lines) == 1 && length > l.config.MaxLength:
+		add(PhraseFieldText, "must be at most %d characters (got %d)", l.config.MaxLength, length)
+	case len(lines) > l.config.MaxLines:
+		add(PhraseFieldText, "must be at most %d lines (got %d)", l.config.MaxLines, len(lines))
+	}
+	if len(lines) > 1 {
+		for i, line := range lines {
+			if n := utf8.RuneCountInString(line); n > l.config.MaxLength {
+				add(PhraseFieldText, "line %d must be at most %d characters (got %d)", i+1, l.config.MaxLength, n)
+			}
+		}
+	}
+
+	// Laporkan setiap karakter terlarang sekali saja, urut kemunculan
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if seen[r] || allowedInCharset(r, charset) {
+			continue
+		}
+		seen[r] = true
+		if replacement, ok := asciiReplacements[r]; ok && charset != PhraseCharsetUnicode {
+			add(PhraseFieldText, "contains %q (%U) which is not allowed in %s phrases; use %q instead", r, r, charset, replacement)
+		} else {
+			add(PhraseFieldText, "contains %q (%U) which is not allowed in %s phrases", r, r, charset)
+		}
+	}
+
+	if multiplier < l.config.MinMultiplier || multiplier > l.config.MaxMultiplier {
+		add(PhraseFieldMultiplier, "must be between %g and %g", l.config.MinMultiplier, l.config.MaxMultiplier)
+	}
+	return issues
+}
+
+// allowedInCharset - spasi dan baris baru (Enter) selalu diizinkan
+func allowedInCharset(r rune, charset string) bool {
+	if r == ' ' {
+		return true
+	}
+	if _, ok := QWERTY.Lookup(r); ok {
+		return true
+	}
+	switch charset {
+	case PhraseCharsetLatin:
+		return unicode.Is(unicode.Latin, r)
+	case PhraseCharsetUnicode:
+		return unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}
+	return false
+}
+
+// asciiReplacements adalah tanda baca "pintar" dari word processor yang
+// sering ikut ter-paste beserta padanan ASCII-nya
+var asciiReplacements = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '′': "'",
+	'“': "\"", '”': "\"", '„': "\"", '″': "\"",
+	'–': "-", '—': "-", '−': "-",
+	'…': "...",
+}
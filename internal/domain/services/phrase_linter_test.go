@@ -0,0 +1,135 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPhraseLinterNormalizeText(t *testing.T) {
+	linter := NewPhraseLinter(DefaultPhraseLintConfig)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"single line trimmed", "  hello world  ", "hello world"},
+		{"single line collapses whitespace", "a \t b\u00a0\u00a0c", "a b c"},
+		{"blank lines around single line", "\n\n  hello  \n\n", "hello"},
+		{"crlf becomes lf", "if x:\r\n    y", "if x:\n    y"},
+		{"cr becomes lf", "a\rb", "a\nb"},
+		{"tab becomes four spaces", "if x:\n\ty", "if x:\n    y"},
+		{"trailing spaces per line", "a  \nb \t", "a\nb"},
+		{"consecutive blank lines", "a\n\n\n\nb", "a\n\nb"},
+		{"common indentation removed", "    a\n      b\n    c", "a\n  b\nc"},
+		{"blank lines ignored for indentation", "  a\n\n    b", "a\n\n  b"},
+		{"empty", " \n\t\n ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linter.NormalizeText(tt.text); got != tt.want {
+				t.Errorf("NormalizeText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhraseLinterLint(t *testing.T) {
+	linter := NewPhraseLinter(PhraseLintConfig{
+		MinLength:     3,
+		MaxLength:     10,
+		MaxLines:      3,
+		MinMultiplier: 0.5,
+		MaxMultiplier: 3.0,
+	})
+
+	tests := []struct {
+		name       string
+		text       string
+		multiplier float64
+		charset    string
+		want       []PhraseLintIssue
+	}{
+		{"valid", "let x = 1;", 1, PhraseCharsetQWERTY, nil},
+		{"valid multi-line", "if x:\n  y()", 1.5, PhraseCharsetQWERTY, nil},
+		{"empty", "", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "is required"},
+		}},
+		{"too short", "ab", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "must be at least 3 characters (got 2)"},
+		}},
+		{"too long", "abcdefghijk", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "must be at most 10 characters (got 11)"},
+		}},
+		{"length counts runes", "ééééééééé", 1, PhraseCharsetLatin, nil},
+		{"too many lines", "a\nb\nc\nd", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "must be at most 3 lines (got 4)"},
+		}},
+		{"line too long", "abc\nabcdefghijk", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "line 2 must be at most 10 characters (got 11)"},
+		}},
+		{"total length of snippet is not limited", "abcdefgh\nabcdefgh", 1, PhraseCharsetQWERTY, nil},
+		{"accent not allowed in qwerty", "café", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "contains 'é' (U+00E9) which is not allowed in qwerty phrases"},
+		}},
+		{"accent allowed in latin", "café", 1, PhraseCharsetLatin, nil},
+		{"cjk not allowed in latin", "日本語です", 1, PhraseCharsetLatin, []PhraseLintIssue{
+			{PhraseFieldText, "contains '日' (U+65E5) which is not allowed in latin phrases"},
+			{PhraseFieldText, "contains '本' (U+672C) which is not allowed in latin phrases"},
+			{PhraseFieldText, "contains '語' (U+8A9E) which is not allowed in latin phrases"},
+			{PhraseFieldText, "contains 'で' (U+3067) which is not allowed in latin phrases"},
+			{PhraseFieldText, "contains 'す' (U+3059) which is not allowed in latin phrases"},
+		}},
+		{"cjk allowed in unicode", "日本語です", 1, PhraseCharsetUnicode, nil},
+		{"smart quote suggests ascii", "“hi”", 1, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, `contains '“' (U+201C) which is not allowed in qwerty phrases; use "\"" instead`},
+			{PhraseFieldText, `contains '”' (U+201D) which is not allowed in qwerty phrases; use "\"" instead`},
+		}},
+		{"forbidden rune reported once", "a—b—c", 1, PhraseCharsetLatin, []PhraseLintIssue{
+			{PhraseFieldText, `contains '—' (U+2014) which is not allowed in latin phrases; use "-" instead`},
+		}},
+		{"smart punctuation allowed in unicode", "a—b…", 1, PhraseCharsetUnicode, nil},
+		{"multiplier too low", "abc", 0, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldMultiplier, "must be between 0.5 and 3"},
+		}},
+		{"multiplier too high", "abc", 3.01, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldMultiplier, "must be between 0.5 and 3"},
+		}},
+		{"multiplier bounds inclusive", "abc", 0.5, PhraseCharsetQWERTY, nil},
+		{"text and multiplier issues together", "", 5, PhraseCharsetQWERTY, []PhraseLintIssue{
+			{PhraseFieldText, "is required"},
+			{PhraseFieldMultiplier, "must be between 0.5 and 3"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := linter.Lint(tt.text, tt.multiplier, tt.charset)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint(%q, %g, %q) = %v, want %v", tt.text, tt.multiplier, tt.charset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhraseLinterDefaultConfig(t *testing.T) {
+	linter := NewPhraseLinter(DefaultPhraseLintConfig)
+
+	tests := []struct {
+		name   string
+		text   string
+		issues int
+	}{
+		{"120 characters", strings.Repeat("a", 120), 0},
+		{"121 characters", strings.Repeat("a", 121), 1},
+		{"12 lines", strings.Repeat("a\n", 11) + "a", 0},
+		{"13 lines", strings.Repeat("a\n", 12) + "a", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linter.Lint(tt.text, 1, PhraseCharsetQWERTY); len(got) != tt.issues {
+				t.Errorf("Lint() = %v, want %d issue(s)", got, tt.issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+// XP per attempt: (AttemptBaseXP + final score / 10) × multiplier difficulty
+const AttemptBaseXP = 10
+
+var difficultyXPMultiplier = map[string]float64{
+	models.DifficultyEasy:   1.0,
+	models.DifficultyMedium: 1.5,
+	models.DifficultyHard:   2.0,
+}
+
+// AttemptXP menghitung XP yang didapat dari satu attempt
+func AttemptXP(finalScore float64, difficulty string) int {
+	multiplier, ok := difficultyXPMultiplier[difficulty]
+	if !ok {
+		multiplier = 1.0
+	}
+	return int(math.Round((AttemptBaseXP + math.Max(finalScore, 0)/10) * multiplier))
+}
+
+// LevelXPStep menentukan kurva level: untuk naik dari level n ke n+1
+// dibutuhkan n × LevelXPStep XP, sehingga total XP untuk mencapai level n
+// adalah LevelXPStep × n(n-1)/2.
+const LevelXPStep = 100
+
+// LevelInfo adalah posisi XP pada kurva level
+type LevelInfo struct {
+	Level        int
+	LevelStartXP int64 // total XP minimum untuk level ini
+	NextLevelXP  int64 // total XP untuk mencapai level berikutnya
+}
+
+// LevelThreshold mengembalikan total XP minimum untuk mencapai level
+func LevelThreshold(level int) int64 {
+	n := int64(level)
+	return LevelXPStep * n * (n - 1) / 2
+}
+
+// LevelForXP mengembalikan level untuk total XP (level minimum 1)
+func LevelForXP(xp int64) LevelInfo {
+	if xp < 0 {
+		xp = 0
+	}
+	// Invers LevelThreshold, lalu koreksi pembulatan floating point
+	level := int((1 + math.Sqrt(1+8*float64(xp)/LevelXPStep)) / 2)
+	if level < 1 {
+		level = 1
+	}
+	for LevelThreshold(level+1) <= xp {
+		level++
+	}
+	for level > 1 && LevelThreshold(level) > xp {
+		level--
+	}
+	return LevelInfo{
+		Level:        level,
+		LevelStartXP: LevelThreshold(level),
+		NextLevelXP:  LevelThreshold(level + 1),
+	}
+}
+
+// AdvanceStreak mencatat attempt pada tanggal today (00:00 UTC dari tanggal
+// di timezone user). Attempt kedua di hari yang sama tidak mengubah streak;
+// hari yang terlewat mereset streak ke 1.
+func AdvanceStreak(progress *models.UserProgress, today time.Time) {
+	switch {
+	case !progress.LastPlayedOn.IsZero() && sameDay(progress.LastPlayedOn, today):
+		return
+	case !progress.LastPlayedOn.IsZero() && sameDay(progress.LastPlayedOn.AddDate(0, 0, 1), today):
+		progress.CurrentStreak++
+	case !progress.LastPlayedOn.IsZero() && progress.LastPlayedOn.After(today):
+		// Timezone user mundur sehingga "hari ini" lebih awal dari attempt
+		// terakhir; anggap hari yang sama
+		return
+	default:
+		progress.CurrentStreak = 1
+	}
+	progress.LastPlayedOn = today
+	if progress.CurrentStreak > progress.LongestStreak {
+		progress.LongestStreak = progress.CurrentStreak
+	}
+}
+
+// ActiveStreak mengembalikan streak yang masih berjalan pada today: streak
+// tetap dihitung jika attempt terakhir hari ini atau kemarin, selain itu 0
+func ActiveStreak(progress *models.UserProgress, today time.Time) int {
+	if progress == nil || progress.LastPlayedOn.IsZero() {
+		return 0
+	}
+	if progress.LastPlayedOn.Before(today.AddDate(0, 0, -1)) {
+		return 0
+	}
+	return progress.CurrentStreak
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
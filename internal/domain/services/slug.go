@@ -0,0 +1,35 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxSlugLength mengikuti panjang kolom stages.slug
+const MaxSlugLength = 100
+
+var (
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	slugSeparatorRun = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ValidSlug memeriksa slug berupa huruf kecil, angka dan '-' tunggal di
+// antara keduanya, misalnya "java-basics-2"
+func ValidSlug(slug string) bool {
+	return len(slug) <= MaxSlugLength && slugPattern.MatchString(slug)
+}
+
+// Slugify membuat slug dari nama stage dengan aturan yang sama seperti
+// backfill di migrasi. Nama tanpa huruf/angka menghasilkan "stage".
+// Panjangnya dibatasi 90 karakter agar masih ada ruang untuk suffix unik.
+func Slugify(name string) string {
+	slug := slugSeparatorRun.ReplaceAllString(strings.ToLower(name), "-")
+	if len(slug) > 90 {
+		slug = slug[:90]
+	}
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "stage"
+	}
+	return slug
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"uwika_quick_typer_game/internal/domain/models"
+)
+
+var (
+	ErrPrerequisiteCycle   = &DomainError{Code: "PREREQUISITE_CYCLE", Message: "stage prerequisites would form a cycle"}
+	ErrInvalidPrerequisite = &DomainError{Code: "INVALID_PREREQUISITE", Message: "min_stars must be 0-3, min_accuracy 0-100, and a stage cannot require itself"}
+)
+
+// StageProgression menentukan apakah stage sudah terbuka untuk user
+// berdasarkan accuracy terbaik di stage prasyarat
+type StageProgression struct {
+	scoreCalculator *ScoreCalculator
+}
+
+func NewStageProgression(scoreCalculator *ScoreCalculator) *StageProgression {
+	return &StageProgression{scoreCalculator: scoreCalculator}
+}
+
+// Evaluate menghitung status setiap prerequisite. bestAccuracy berisi
+// accuracy terbaik user per stage (stage yang belum dimainkan tidak ada).
+// Prerequisite ke stage yang tidak aktif diabaikan karena tidak bisa dimainkan.
+func (p *StageProgression) Evaluate(prerequisites []*models.StagePrerequisite, bestAccuracy map[string]float64) (bool, []*models.RequirementStatus) {
+	locked := false
+	var statuses []*models.RequirementStatus
+	for _, prerequisite := range prerequisites {
+		if !prerequisite.RequiredStageActive {
+			continue
+		}
+
+		status := &models.RequirementStatus{Prerequisite: prerequisite}
+		if accuracy, played := bestAccuracy[prerequisite.RequiredStageID]; played {
+			status.BestAccuracy = accuracy
+			status.Stars = p.scoreCalculator.CalculateStars(accuracy)
+			status.Met = status.Stars >= prerequisite.MinStars && accuracy >= prerequisite.MinAccuracy
+		}
+		if !status.Met {
+			locked = true
+		}
+		statuses = append(statuses, status)
+	}
+	return locked, statuses
+}
+
+// ValidatePrerequisites memeriksa nilai syarat untuk stageID
+func ValidatePrerequisites(stageID string, prerequisites []*models.StagePrerequisite) error {
+	seen := make(map[string]bool)
+	for _, prerequisite := range prerequisites {
+		if prerequisite.RequiredStageID == stageID || seen[prerequisite.RequiredStageID] ||
+			prerequisite.MinStars < 0 || prerequisite.MinStars > 3 ||
+			prerequisite.MinAccuracy < 0 || prerequisite.MinAccuracy > 100 {
+			return ErrInvalidPrerequisite
+		}
+		seen[prerequisite.RequiredStageID] = true
+	}
+	return nil
+}
+
+// HasPrerequisiteCycle mendeteksi siklus di graph stage -> stage prasyarat
+func HasPrerequisiteCycle(graph map[string][]string) bool {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+
+	var visit func(stageID string) bool
+	visit = func(stageID string) bool {
+		switch state[stageID] {
+		case visiting:
+			return true
+		case done:
+			return false
+		}
+		state[stageID] = visiting
+		for _, required := range graph[stageID] {
+			if visit(required) {
+				return true
+			}
+		}
+		state[stageID] = done
+		return false
+	}
+
+	for stageID := range graph {
+		if visit(stageID) {
+			return true
+		}
+	}
+	return false
+}
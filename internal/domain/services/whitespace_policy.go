@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Whitespace policy per stage, menentukan karakter mana dari teks phrase
+// (terutama snippet multi-baris) yang benar-benar diketik pemain
+const (
+	// WhitespaceStrict: setiap spasi dan baris baru diketik (default)
+	WhitespaceStrict = "strict"
+	// WhitespaceCollapse: deretan spasi, termasuk indentasi, cukup diketik
+	// sebagai satu spasi
+	WhitespaceCollapse = "collapse"
+	// WhitespaceAutoIndent: spasi di awal baris diisi otomatis oleh client
+	// setelah Enter sehingga tidak diketik
+	WhitespaceAutoIndent = "auto_indent"
+)
+
+// ValidWhitespacePolicy memeriksa nama whitespace policy
+func ValidWhitespacePolicy(policy string) bool {
+	switch policy {
+	case WhitespaceStrict, WhitespaceCollapse, WhitespaceAutoIndent:
+		return true
+	}
+	return false
+}
+
+// TypedText mengembalikan karakter yang harus diketik pemain untuk text
+// (yang sudah dinormalisasi PhraseLinter) sesuai policy. Posisi mistake,
+// jumlah karakter dan akurasi dihitung atas hasil ini, bukan teks asli.
+// Policy yang tidak dikenal diperlakukan sebagai strict.
+func TypedText(text, policy string) string {
+	switch policy {
+	case WhitespaceCollapse:
+		var b strings.Builder
+		b.Grow(len(text))
+		for i, r := range text {
+			if r == ' ' && i > 0 && text[i-1] == ' ' {
+				continue
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	case WhitespaceAutoIndent:
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimLeft(line, " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+	return text
+}
+
+// TypedLength adalah jumlah karakter (rune) yang diketik untuk text,
+// termasuk Enter untuk setiap baris baru
+func TypedLength(text, policy string) int {
+	return utf8.RuneCountInString(TypedText(text, policy))
+}
@@ -0,0 +1,58 @@
+package services
+
+import "testing"
+
+func TestTypedText(t *testing.T) {
+	snippet := "def f():\n    if x:\n        return  1"
+
+	tests := []struct {
+		name   string
+		text   string
+		policy string
+		want   string
+	}{
+		{"strict single line", "a  b", WhitespaceStrict, "a  b"},
+		{"strict snippet", snippet, WhitespaceStrict, snippet},
+		{"collapse single line", "a  b", WhitespaceCollapse, "a b"},
+		{"collapse snippet", snippet, WhitespaceCollapse, "def f():\n if x:\n return 1"},
+		{"auto indent single line", "a  b", WhitespaceAutoIndent, "a  b"},
+		{"auto indent snippet", snippet, WhitespaceAutoIndent, "def f():\nif x:\nreturn  1"},
+		{"unknown policy is strict", snippet, "tabs", snippet},
+		{"empty policy is strict", "a  b", "", "a  b"},
+		{"empty text", "", WhitespaceCollapse, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TypedText(tt.text, tt.policy); got != tt.want {
+				t.Errorf("TypedText(%q, %q) = %q, want %q", tt.text, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedLength(t *testing.T) {
+	snippet := "def f():\n    return 1"
+
+	tests := []struct {
+		name   string
+		text   string
+		policy string
+		want   int
+	}{
+		{"strict counts every space and enter", snippet, WhitespaceStrict, 21},
+		{"collapse counts indentation once", snippet, WhitespaceCollapse, 18},
+		{"auto indent skips indentation", snippet, WhitespaceAutoIndent, 17},
+		{"strict single line", "a  b", WhitespaceStrict, 4},
+		{"collapse single line", "a  b", WhitespaceCollapse, 3},
+		{"auto indent single line", "a  b", WhitespaceAutoIndent, 4},
+		{"runes not bytes", "café", WhitespaceStrict, 4},
+		{"empty text", "", WhitespaceStrict, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TypedLength(tt.text, tt.policy); got != tt.want {
+				t.Errorf("TypedLength(%q, %q) = %d, want %d", tt.text, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
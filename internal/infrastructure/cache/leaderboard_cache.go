@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+// LeaderboardCache membungkus ScoreRepository dan menyimpan top-N
+// leaderboard per stage di memory, untuk board all-time dan board season
+// (board default selama ada season aktif). Cache stage di-invalidate setiap
+// ada score baru yang ditulis lewat repository ini atau ada NOTIFY dari
+// instance lain; ttl hanya pengaman jika notifikasi terlewat.
+type LeaderboardCache struct {
+	repositories.ScoreRepository
+
+	size int
+	ttl  time.Duration
+
+	mu     sync.Mutex
+	stages map[topNKey]*stageTopN
+}
+
+// topNKey membedakan board all-time (seasonID kosong) dan board season
+type topNKey struct {
+	stageID  string
+	seasonID string
+}
+
+type stageTopN struct {
+	entries  []*models.LeaderboardEntry
+	loadedAt time.Time
+	// generation naik setiap invalidate, supaya hasil query yang dimulai
+	// sebelum invalidate tidak disimpan
+	generation uint64
+	loaded     bool
+}
+
+func NewLeaderboardCache(scoreRepo repositories.ScoreRepository, size int, ttl time.Duration) *LeaderboardCache {
+	return &LeaderboardCache{
+		ScoreRepository: scoreRepo,
+		size:            size,
+		ttl:             ttl,
+		stages:          make(map[topNKey]*stageTopN),
+	}
+}
+
+func (c *LeaderboardCache) Create(ctx context.Context, score *models.Score) error {
+	if err := c.ScoreRepository.Create(ctx, score); err != nil {
+		return err
+	}
+	c.Invalidate(score.StageID)
+	return nil
+}
+
+// FindLeaderboardByStage melayani halaman pertama leaderboard all-time dan
+// season dari cache; query lain diteruskan ke repository.
+func (c *LeaderboardCache) FindLeaderboardByStage(ctx context.Context, q repositories.LeaderboardQuery) ([]*models.LeaderboardEntry, error) {
+	if !q.Window.Since.IsZero() || q.After != nil || q.Limit > c.size {
+		return c.ScoreRepository.FindLeaderboardByStage(ctx, q)
+	}
+
+	key := topNKey{stageID: q.StageID, seasonID: q.Window.SeasonID}
+	entries, ok, generation := c.get(key)
+	if !ok {
+		var err error
+		entries, err = c.ScoreRepository.FindLeaderboardByStage(ctx, repositories.LeaderboardQuery{
+			StageID: q.StageID,
+			Window:  models.ScoreWindow{SeasonID: q.Window.SeasonID},
+			Limit:   c.size,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.put(key, entries, generation)
+	}
+
+	if len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+	return copyEntries(entries), nil
+}
+
+// Invalidate menghapus top-N cache untuk stage (semua board-nya)
+func (c *LeaderboardCache) Invalidate(stageID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, stage := range c.stages {
+		if key.stageID != stageID {
+			continue
+		}
+		stage.entries = nil
+		stage.loaded = false
+		stage.generation++
+	}
+}
+
+// InvalidateAll menghapus cache semua stage, misalnya setelah notifikasi
+// perubahan dari instance lain mungkin terlewat
+func (c *LeaderboardCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, stage := range c.stages {
+		stage.entries = nil
+		stage.loaded = false
+		stage.generation++
+	}
+}
+
+func (c *LeaderboardCache) get(key topNKey) ([]*models.LeaderboardEntry, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stage := c.stage(key)
+	if !stage.loaded || time.Since(stage.loadedAt) > c.ttl {
+		return nil, false, stage.generation
+	}
+	return stage.entries, true, stage.generation
+}
+
+func (c *LeaderboardCache) put(key topNKey, entries []*models.LeaderboardEntry, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stage := c.stage(key)
+	if stage.generation != generation {
+		return
+	}
+	stage.entries = entries
+	stage.loadedAt = time.Now()
+	stage.loaded = true
+}
+
+func (c *LeaderboardCache) stage(key topNKey) *stageTopN {
+	stage, ok := c.stages[key]
+	if !ok {
+		stage = &stageTopN{}
+		c.stages[key] = stage
+	}
+	return stage
+}
+
+// copyEntries supaya caller tidak bisa mengubah isi cache
+func copyEntries(entries []*models.LeaderboardEntry) []*models.LeaderboardEntry {
+	result := make([]*models.LeaderboardEntry, len(entries))
+	for i, entry := range entries {
+		copied := *entry
+		result[i] = &copied
+	}
+	return result
+}
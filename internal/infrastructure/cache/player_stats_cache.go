@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+// PlayerStatsCache membungkus ScoreRepository dan menyimpan hasil agregasi
+// statistik pemain di memory. Entry tetap valid selama attempt terakhir user
+// belum berubah, sehingga score dari instance lain juga langsung terlihat;
+// pengecekannya cukup satu lookup index. Since ikut menjadi key, jadi entry
+// hari sebelumnya tidak terpakai lagi setelah hari berganti.
+type PlayerStatsCache struct {
+	repositories.ScoreRepository
+
+	size int
+
+	mu      sync.Mutex
+	entries map[playerStatsKey]*playerStatsEntry
+}
+
+type playerStatsKey struct {
+	userID   string
+	since    int64
+	location string
+}
+
+type playerStatsEntry struct {
+	latestScoreID int64
+	stats         *models.PlayerStats
+}
+
+func NewPlayerStatsCache(scoreRepo repositories.ScoreRepository, size int) *PlayerStatsCache {
+	return &PlayerStatsCache{
+		ScoreRepository: scoreRepo,
+		size:            size,
+		entries:         make(map[playerStatsKey]*playerStatsEntry),
+	}
+}
+
+// FindPlayerStats melayani statistik dari cache jika user belum punya attempt
+// baru sejak agregasi terakhir. Hasilnya dipakai bersama, jangan diubah.
+func (c *PlayerStatsCache) FindPlayerStats(ctx context.Context, q repositories.PlayerStatsQuery) (*models.PlayerStats, error) {
+	latestScoreID, err := c.ScoreRepository.FindLatestScoreID(ctx, q.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := playerStatsKey{userID: q.UserID, since: q.Since.Unix(), location: q.Location.String()}
+	if stats, ok := c.get(key, latestScoreID); ok {
+		return stats, nil
+	}
+
+	stats, err := c.ScoreRepository.FindPlayerStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, latestScoreID, stats)
+	return stats, nil
+}
+
+func (c *PlayerStatsCache) get(key playerStatsKey, latestScoreID int64) (*models.PlayerStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.latestScoreID != latestScoreID {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (c *PlayerStatsCache) put(key playerStatsKey, latestScoreID int64, stats *models.PlayerStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		// Buang satu entry sembarang; entry user lain yang masih aktif akan
+		// diisi ulang pada request berikutnya
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = &playerStatsEntry{latestScoreID: latestScoreID, stats: stats}
+}
@@ -16,13 +16,15 @@ type Config struct {
 	SSLMode  string
 }
 
-func NewConnection(cfg Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
+func (cfg Config) DSN() string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
+}
 
-	db, err := sql.Open("postgres", dsn)
+func NewConnection(cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Listen menjalankan LISTEN pada channel Postgres sampai ctx selesai.
+// handle dipanggil untuk setiap payload NOTIFY; onReconnect dipanggil setelah
+// koneksi listener tersambung ulang, karena notifikasi selama putus bisa hilang.
+func Listen(ctx context.Context, cfg Config, channel string, handle func(payload string), onReconnect func()) error {
+	listener := pq.NewListener(cfg.DSN(), 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Listener %s: %v", channel, err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		defer listener.Close()
+
+		// Ping berkala supaya koneksi mati cepat terdeteksi
+		ticker := time.NewTicker(90 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-listener.Notify:
+				if notification == nil {
+					onReconnect()
+					continue
+				}
+				handle(notification.Extra)
+			case <-ticker.C:
+				go listener.Ping()
+			}
+		}
+	}()
+
+	return nil
+}
@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Auth DTOs
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -20,43 +25,171 @@ type AuthResponse struct {
 }
 
 // Theme DTOs
+type CreateThemeRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	IconKey     string `json:"icon_key"`
+	SortOrder   int    `json:"sort_order" binding:"min=0"`
+}
+
+type UpdateThemeRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	IconKey     string `json:"icon_key"`
+	SortOrder   int    `json:"sort_order" binding:"min=0"`
+}
+
 type ThemeResponse struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	Color       string `json:"color,omitempty"`
+	IconKey     string `json:"icon_key,omitempty"`
+	SortOrder   int    `json:"sort_order"`
 }
 
 // Stage DTOs
 type CreateStageRequest struct {
 	Name       string `json:"name" binding:"required"`
+	Slug       string `json:"slug"` // opsional, dibuat dari name jika kosong
 	ThemeID    string `json:"theme_id" binding:"required"`
-	Difficulty string `json:"difficulty" binding:"required"`
+	Difficulty string `json:"difficulty"` // opsional, kosong = saran analyzer dari phrases
 	IsActive   bool   `json:"is_active"`
+	// Opsional: jadwal buka/tutup stage (RFC3339)
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	// Opsional: qwerty (default), latin atau unicode
+	PhraseCharset string `json:"phrase_charset"`
+	// Opsional: jumlah phrase acak per sesi (0 = semua phrase berurutan)
+	PhrasePoolSize int `json:"phrase_pool_size" binding:"min=0"`
+	// Opsional: strict (default), collapse atau auto_indent
+	WhitespacePolicy string `json:"whitespace_policy"`
+	// Opsional: bahasa teks phrase, mis. en atau id
+	PhraseLanguage string `json:"phrase_language"`
+	// Opsional: teks phrase awal (sequence 1..n, multiplier saran analyzer)
+	Phrases []string `json:"phrases" binding:"max=500"`
 }
 
 type UpdateStageRequest struct {
 	Name       string `json:"name" binding:"required"`
+	Slug       string `json:"slug"` // opsional, kosong = tidak diubah
 	ThemeID    string `json:"theme_id" binding:"required"`
 	Difficulty string `json:"difficulty" binding:"required"`
 	IsActive   bool   `json:"is_active"`
+	// Jadwal buka/tutup stage; kosong = tanpa batas
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+	// Opsional, kosong = tidak diubah
+	PhraseCharset string `json:"phrase_charset"`
+	// Jumlah phrase acak per sesi; 0 = semua phrase berurutan
+	PhrasePoolSize int `json:"phrase_pool_size" binding:"min=0"`
+	// Opsional, kosong = tidak diubah
+	WhitespacePolicy string `json:"whitespace_policy"`
+	// Bahasa teks phrase; kosong = belum ditentukan
+	PhraseLanguage string `json:"phrase_language"`
 }
 
 type StageResponse struct {
 	ID         string           `json:"id"`
+	Slug       string           `json:"slug,omitempty"`
 	Name       string           `json:"name"`
 	ThemeID    string           `json:"theme_id,omitempty"`
 	ThemeName  string           `json:"theme_name,omitempty"`
 	Difficulty string           `json:"difficulty"`
 	IsActive   bool             `json:"is_active"`
+	VersionID  string           `json:"version_id,omitempty"` // versi published, kosong jika belum pernah dipublish
+	Version    int              `json:"version,omitempty"`
 	Phrases    []PhraseResponse `json:"phrases,omitempty"`
+	// Jadwal stage dan countdown (detik) relatif terhadap waktu server
+	AvailableFrom   *time.Time `json:"available_from,omitempty"`
+	AvailableUntil  *time.Time `json:"available_until,omitempty"`
+	OpensInSeconds  *int64     `json:"opens_in_seconds,omitempty"`
+	ClosesInSeconds *int64     `json:"closes_in_seconds,omitempty"`
+	PhraseCharset   string     `json:"phrase_charset,omitempty"`
+	// Spasi mana dari phrase multi-baris yang diketik pemain
+	WhitespacePolicy string `json:"whitespace_policy,omitempty"`
+	PhraseLanguage   string `json:"phrase_language,omitempty"`
+	// Stage dengan phrase pool: jumlah phrase per sesi
+	PhrasePoolSize int `json:"phrase_pool_size,omitempty"`
+	// Sesi dari POST /api/stage/:id/session yang dikirim saat submit
+	SessionID        string     `json:"session_id,omitempty"`
+	SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
+}
+
+// CloneStageRequest - semua field opsional: name (default "<nama> (copy)"),
+// theme_id (default theme asal) dan multiplier_scale (default 1)
+type CloneStageRequest struct {
+	Name            string  `json:"name" binding:"max=255"`
+	ThemeID         string  `json:"theme_id"`
+	MultiplierScale float64 `json:"multiplier_scale" binding:"min=0"`
+}
+
+// PublishStageRequest - reset_leaderboard memulai leaderboard stage dari
+// versi baru ini
+type PublishStageRequest struct {
+	ResetLeaderboard bool `json:"reset_leaderboard"`
+}
+
+type StageVersionResponse struct {
+	ID               string    `json:"id"`
+	Version          int       `json:"version"`
+	Name             string    `json:"name"`
+	WhitespacePolicy string    `json:"whitespace_policy"`
+	PhrasePoolSize   int       `json:"phrase_pool_size"`
+	PhraseLanguage   string    `json:"phrase_language,omitempty"`
+	LeaderboardReset bool      `json:"leaderboard_reset"`
+	PublishedBy      string    `json:"published_by,omitempty"`
+	PublishedAt      time.Time `json:"published_at"`
+	PhraseCount      int       `json:"phrase_count"`
+}
+
+type StageVersionHistoryResponse struct {
+	HasUnpublishedChanges bool                   `json:"has_unpublished_changes"`
+	Versions              []StageVersionResponse `json:"versions"`
+}
+
+// StageListEntry adalah stage di daftar pemain beserta status terkunci
+type StageListEntry struct {
+	StageResponse
+	Locked       bool                       `json:"locked"`
+	Requirements []StageRequirementResponse `json:"requirements,omitempty"`
+}
+
+type StageRequirementResponse struct {
+	StageID      string  `json:"stage_id"`
+	StageName    string  `json:"stage_name"`
+	MinStars     int     `json:"min_stars"`
+	MinAccuracy  float64 `json:"min_accuracy"`
+	BestAccuracy float64 `json:"best_accuracy"`
+	Stars        int     `json:"stars"`
+	Met          bool    `json:"met"`
+}
+
+type StagePrerequisiteRequest struct {
+	RequiredStageID string  `json:"required_stage_id" binding:"required"`
+	MinStars        int     `json:"min_stars" binding:"min=0,max=3"`
+	MinAccuracy     float64 `json:"min_accuracy" binding:"min=0,max=100"`
+}
+
+type SetStagePrerequisitesRequest struct {
+	Prerequisites []StagePrerequisiteRequest `json:"prerequisites" binding:"dive"`
+}
+
+type StagePrerequisiteResponse struct {
+	RequiredStageID   string  `json:"required_stage_id"`
+	RequiredStageName string  `json:"required_stage_name"`
+	MinStars          int     `json:"min_stars"`
+	MinAccuracy       float64 `json:"min_accuracy"`
 }
 
 // Phrase DTOs
 type CreatePhraseRequest struct {
-	StageID        string  `json:"stage_id" binding:"required"`
-	Text           string  `json:"text" binding:"required"`
-	SequenceNumber int     `json:"sequence_number" binding:"required"`
-	BaseMultiplier float64 `json:"base_multiplier" binding:"required"`
+	StageID        string   `json:"stage_id" binding:"required"`
+	Text           string   `json:"text" binding:"required"`
+	SequenceNumber int      `json:"sequence_number" binding:"required"`
+	BaseMultiplier *float64 `json:"base_multiplier"` // opsional, default saran analyzer
 }
 
 type UpdatePhraseRequest struct {
@@ -66,9 +199,43 @@ type UpdatePhraseRequest struct {
 	BaseMultiplier float64 `json:"base_multiplier" binding:"required"`
 }
 
+// ReorderPhrasesRequest berisi semua phrase stage dalam urutan baru
+type ReorderPhrasesRequest struct {
+	PhraseIDs []string `json:"phrase_ids" binding:"required,min=1"`
+}
+
+// AnalyzePhrasesRequest - teks phrase yang belum disimpan; whitespace_policy
+// opsional (default strict)
+type AnalyzePhrasesRequest struct {
+	Texts            []string `json:"texts" binding:"required,min=1,max=500"`
+	WhitespacePolicy string   `json:"whitespace_policy"`
+}
+
+type PhraseAnalysisResponse struct {
+	PhraseID            string  `json:"phrase_id,omitempty"`
+	Text                string  `json:"text"`
+	Length              int     `json:"length"`
+	SymbolDensity       float64 `json:"symbol_density"`
+	ShiftRatio          float64 `json:"shift_ratio"`
+	FingerTravel        float64 `json:"finger_travel"`
+	RareBigramRatio     float64 `json:"rare_bigram_ratio"`
+	Score               float64 `json:"score"`
+	Multiplier          float64 `json:"multiplier,omitempty"` // multiplier saat ini
+	SuggestedMultiplier float64 `json:"suggested_multiplier"`
+}
+
+type StageAnalysisResponse struct {
+	StageID             string                   `json:"stage_id,omitempty"`
+	Difficulty          string                   `json:"difficulty,omitempty"` // difficulty saat ini
+	Score               float64                  `json:"score"`
+	SuggestedDifficulty string                   `json:"suggested_difficulty,omitempty"`
+	Phrases             []PhraseAnalysisResponse `json:"phrases"`
+}
+
 type PhraseResponse struct {
 	ID             string  `json:"id"`
 	StageID        string  `json:"stage_id,omitempty"`
+	StageName      string  `json:"stage_name,omitempty"`
 	Text           string  `json:"text"`
 	SequenceNumber int     `json:"sequence_number"`
 	Multiplier     float64 `json:"multiplier"`
@@ -79,24 +246,462 @@ type SubmitScoreRequest struct {
 	StageID     string `json:"stage_id" binding:"required"`
 	TotalTimeMs int    `json:"total_time_ms" binding:"required,min=1"`
 	TotalErrors int    `json:"total_errors" binding:"min=0"`
+	// Opsional: versi yang dimainkan (version_id dari GET /api/stage/:id)
+	StageVersionID string `json:"stage_version_id"`
+	// Wajib untuk stage dengan phrase pool (session_id dari POST /api/stage/:id/session)
+	SessionID string `json:"session_id"`
+	// Opsional: latency setiap keystroke dan karakter yang salah diketik
+	// per phrase, untuk heatmap. Tidak divalidasi binding: entry yang tidak
+	// cocok dengan phrase diabaikan supaya score tetap tersimpan.
+	Phrases []PhraseMistakesRequest `json:"phrases"`
+}
+
+type PhraseMistakesRequest struct {
+	PhraseID string `json:"phrase_id"`
+	// Jeda sebelum setiap karakter yang diketik, satu nilai per karakter
+	LatenciesMs []int                 `json:"latencies_ms"`
+	Mistakes    []MistakeEventRequest `json:"mistakes"`
+}
+
+type MistakeEventRequest struct {
+	Position  int    `json:"position"`
+	Expected  string `json:"expected"`
+	Typed     string `json:"typed"` // karakter yang diketik pemain
+	LatencyMs int    `json:"latency_ms"`
 }
 
 type SubmitScoreResponse struct {
+	Status          string                   `json:"status"`
+	FinalScore      float64                  `json:"final_score"`
+	Progress        *AttemptProgressResponse `json:"progress,omitempty"`
+	NewAchievements []BadgeResponse          `json:"new_achievements"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int     `json:"rank"`
+	Username    string  `json:"username"`
+	FinalScore  float64 `json:"final_score"`
+	TotalTimeMs int     `json:"total_time_ms"`
+	IsMe        bool    `json:"is_me,omitempty"`
+}
+
+type LeaderboardResponse struct {
+	StageID    string             `json:"stage_id"`
+	Period     string             `json:"period"`
+	Entries    []LeaderboardEntry `json:"entries"`
+	Limit      int                `json:"limit,omitempty"`
+	MaxLimit   int                `json:"max_limit,omitempty"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Around     string             `json:"around,omitempty"`
+	K          int                `json:"k,omitempty"`
+}
+
+type LeaderboardStreamEntry struct {
+	Rank         int     `json:"rank"`
+	PreviousRank int     `json:"previous_rank,omitempty"`
+	Username     string  `json:"username"`
+	FinalScore   float64 `json:"final_score"`
+	TotalTimeMs  int     `json:"total_time_ms"`
+}
+
+// LeaderboardStreamEvent adalah data event SSE "leaderboard"
+type LeaderboardStreamEvent struct {
+	StageID string                   `json:"stage_id"`
+	Entries []LeaderboardStreamEntry `json:"entries"`
+}
+
+type AggregateLeaderboardEntry struct {
+	Rank            int     `json:"rank"`
+	Username        string  `json:"username"`
+	AggregateScore  float64 `json:"aggregate_score"`
+	StagesCompleted int     `json:"stages_completed"`
+	StagesTotal     int     `json:"stages_total"`
+	TotalTimeMs     int     `json:"total_time_ms"`
+}
+
+type AggregateLeaderboardResponse struct {
+	Scope      string                      `json:"scope"`
+	ThemeID    string                      `json:"theme_id,omitempty"`
+	Difficulty string                      `json:"difficulty,omitempty"`
+	Method     string                      `json:"method"`
+	Period     string                      `json:"period"`
+	Entries    []AggregateLeaderboardEntry `json:"entries"`
+}
+
+// Daily Challenge DTOs
+type DailyChallengeResponse struct {
+	Date              string           `json:"date"`
+	IsToday           bool             `json:"is_today"`
+	RankedAttemptUsed bool             `json:"ranked_attempt_used"`
+	Phrases           []PhraseResponse `json:"phrases"`
+	// Hanya diisi oleh POST /api/daily/session
+	SessionID        string     `json:"session_id,omitempty"`
+	SessionRanked    *bool      `json:"session_ranked,omitempty"`
+	SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
+}
+
+type SubmitDailyChallengeRequest struct {
+	// session_id dari POST /api/daily/session; menentukan challenge yang dinilai
+	SessionID   string `json:"session_id" binding:"required"`
+	TotalTimeMs int    `json:"total_time_ms" binding:"required,min=1"`
+	TotalErrors int    `json:"total_errors" binding:"min=0"`
+}
+
+type SubmitDailyChallengeResponse struct {
 	Status     string  `json:"status"`
 	FinalScore float64 `json:"final_score"`
+	Wpm        float64 `json:"wpm"`
+	Accuracy   float64 `json:"accuracy"`
+	// Posisi attempt ranked pemain di leaderboard challenge ini
+	Rank int `json:"rank,omitempty"`
 }
 
-type LeaderboardEntry struct {
+type DailyLeaderboardResponse struct {
+	Date    string             `json:"date"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// Season DTOs
+type CreateSeasonRequest struct {
+	Name   string     `json:"name" binding:"required"`
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+type SeasonResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at,omitempty"`
+	ClosedAt string `json:"closed_at,omitempty"`
+}
+
+type SeasonStandingEntry struct {
+	Rank        int     `json:"rank"`
 	Username    string  `json:"username"`
 	FinalScore  float64 `json:"final_score"`
 	TotalTimeMs int     `json:"total_time_ms"`
 }
 
+type SeasonStageStandings struct {
+	StageID   string                `json:"stage_id"`
+	StageName string                `json:"stage_name"`
+	Standings []SeasonStandingEntry `json:"standings"`
+}
+
+type SeasonStandingsResponse struct {
+	Season SeasonResponse         `json:"season"`
+	Stages []SeasonStageStandings `json:"stages"`
+}
+
+// Player DTOs
+type ScoreHistoryEntry struct {
+	ScoreID     int64   `json:"score_id"`
+	StageID     string  `json:"stage_id"`
+	StageName   string  `json:"stage_name"`
+	FinalScore  float64 `json:"final_score"`
+	TotalTimeMs int     `json:"total_time_ms"`
+	TotalErrors int     `json:"total_errors"`
+	CompletedAt string  `json:"completed_at"`
+}
+
+type ScoreHistoryResponse struct {
+	Scores     []ScoreHistoryEntry `json:"scores"`
+	Limit      int                 `json:"limit"`
+	MaxLimit   int                 `json:"max_limit"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type BestAttempt struct {
+	ScoreID     int64   `json:"score_id"`
+	FinalScore  float64 `json:"final_score"`
+	TotalTimeMs int     `json:"total_time_ms"`
+	TotalErrors int     `json:"total_errors"`
+	CompletedAt string  `json:"completed_at"`
+}
+
+type StageBestResponse struct {
+	StageID      string      `json:"stage_id"`
+	StageName    string      `json:"stage_name"`
+	Best         BestAttempt `json:"best"`
+	AttemptCount int         `json:"attempt_count"`
+	LastPlayedAt string      `json:"last_played_at"`
+}
+
+type StatsBucketResponse struct {
+	Start              string  `json:"start"`
+	Attempts           int     `json:"attempts"`
+	AvgWpm             float64 `json:"avg_wpm"`
+	BestWpm            float64 `json:"best_wpm"`
+	AvgAccuracy        float64 `json:"avg_accuracy"`
+	BestAccuracy       float64 `json:"best_accuracy"`
+	ErrorRate          float64 `json:"error_rate"`
+	TimePlayedMs       int64   `json:"time_played_ms"`
+	RollingAvgWpm      float64 `json:"rolling_avg_wpm"`
+	RollingAvgAccuracy float64 `json:"rolling_avg_accuracy"`
+}
+
+type PlayerStatsResponse struct {
+	Since         string                `json:"since"`
+	Days          int                   `json:"days"`
+	Attempts      int                   `json:"attempts"`
+	WpmTrend      float64               `json:"wpm_trend"`
+	AccuracyTrend float64               `json:"accuracy_trend"`
+	Daily         []StatsBucketResponse `json:"daily"`
+	Weekly        []StatsBucketResponse `json:"weekly"`
+}
+
+type KeyHeatmapEntry struct {
+	Key                 string  `json:"key"`
+	Row                 int     `json:"row"`
+	Column              int     `json:"column"`
+	Presses             int     `json:"presses"`
+	Errors              int     `json:"errors"`
+	ErrorRate           float64 `json:"error_rate"`
+	AvgLatencyMs        float64 `json:"avg_latency_ms"`
+	AvgMistakeLatencyMs float64 `json:"avg_mistake_latency_ms"`
+}
+
+type BigramHeatmapEntry struct {
+	Bigram              string  `json:"bigram"`
+	Presses             int     `json:"presses"`
+	Errors              int     `json:"errors"`
+	ErrorRate           float64 `json:"error_rate"`
+	AvgLatencyMs        float64 `json:"avg_latency_ms"`
+	AvgMistakeLatencyMs float64 `json:"avg_mistake_latency_ms"`
+}
+
+type SubstitutionHeatmapEntry struct {
+	Expected string `json:"expected"`
+	Typed    string `json:"typed"`
+	Count    int    `json:"count"`
+}
+
+type HeatmapResponse struct {
+	Layout        string                     `json:"layout"`
+	Keys          []KeyHeatmapEntry          `json:"keys"`
+	Bigrams       []BigramHeatmapEntry       `json:"bigrams"`
+	Substitutions []SubstitutionHeatmapEntry `json:"substitutions"`
+}
+
+type PlayerActivityEntry struct {
+	StageID     string  `json:"stage_id"`
+	StageName   string  `json:"stage_name"`
+	FinalScore  float64 `json:"final_score"`
+	CompletedAt string  `json:"completed_at"`
+}
+
+type PlayerPlacementEntry struct {
+	StageID    string  `json:"stage_id"`
+	StageName  string  `json:"stage_name"`
+	Rank       int     `json:"rank"`
+	FinalScore float64 `json:"final_score"`
+}
+
+type PlayerProfileResponse struct {
+	Username        string                 `json:"username"`
+	DisplayName     string                 `json:"display_name"`
+	JoinedAt        string                 `json:"joined_at"`
+	StagesCompleted int                    `json:"stages_completed"`
+	TotalAttempts   int                    `json:"total_attempts"`
+	BestWpm         float64                `json:"best_wpm"`
+	LastPlayedAt    string                 `json:"last_played_at,omitempty"`
+	Progress        PlayerProgressResponse `json:"progress"`
+	RecentActivity  []PlayerActivityEntry  `json:"recent_activity"`
+	TopPlacements   []PlayerPlacementEntry `json:"top_placements"`
+}
+
+type PlayerSettingsRequest struct {
+	DisplayName          *string `json:"display_name"`
+	ProfileVisibility    *string `json:"profile_visibility"`
+	LeaderboardAnonymous *bool   `json:"leaderboard_anonymous"`
+	Timezone             *string `json:"timezone"`
+}
+
+type PlayerSettingsResponse struct {
+	DisplayName          string `json:"display_name"`
+	ProfileVisibility    string `json:"profile_visibility"`
+	LeaderboardAnonymous bool   `json:"leaderboard_anonymous"`
+	Timezone             string `json:"timezone"`
+}
+
+// PlayerProgressResponse adalah XP, level, dan streak harian pemain
+type PlayerProgressResponse struct {
+	XP            int64 `json:"xp"`
+	Level         int   `json:"level"`
+	LevelStartXP  int64 `json:"level_start_xp"`
+	NextLevelXP   int64 `json:"next_level_xp"`
+	CurrentStreak int   `json:"current_streak"`
+	LongestStreak int   `json:"longest_streak"`
+}
+
+type AttemptProgressResponse struct {
+	XPGained  int  `json:"xp_gained"`
+	LeveledUp bool `json:"leveled_up"`
+	PlayerProgressResponse
+}
+
+// Achievement DTOs
+type AchievementRequest struct {
+	Code        string          `json:"code" binding:"required,max=100"`
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description"`
+	RuleType    string          `json:"rule_type" binding:"required"`
+	Params      json.RawMessage `json:"params"`
+	IsActive    bool            `json:"is_active"`
+}
+
+type AchievementResponse struct {
+	ID          string          `json:"id"`
+	Code        string          `json:"code"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	RuleType    string          `json:"rule_type"`
+	Params      json.RawMessage `json:"params"`
+	IsActive    bool            `json:"is_active"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+}
+
+// BadgeResponse adalah achievement seperti yang dilihat pemain (tanpa rule)
+type BadgeResponse struct {
+	ID          string `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type PlayerAchievementEntry struct {
+	BadgeResponse
+	Earned    bool   `json:"earned"`
+	AwardedAt string `json:"awarded_at,omitempty"`
+}
+
+// Stage Bundle DTOs
+
+// StageBundleDocument adalah format file import/export stage. Struktur yang
+// sama dipakai untuk JSON dan YAML; CSV memakai satu baris per phrase.
+type StageBundleDocument struct {
+	Version int                   `json:"version" yaml:"version"`
+	Themes  []BundleThemeDocument `json:"themes,omitempty" yaml:"themes,omitempty"`
+	Stages  []BundleStageDocument `json:"stages" yaml:"stages"`
+}
+
+type BundleThemeDocument struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Color       string `json:"color,omitempty" yaml:"color,omitempty"`
+	IconKey     string `json:"icon_key,omitempty" yaml:"icon_key,omitempty"`
+	SortOrder   int    `json:"sort_order" yaml:"sort_order"`
+}
+
+type BundleStageDocument struct {
+	Slug       string                 `json:"slug" yaml:"slug"`
+	Name       string                 `json:"name" yaml:"name"`
+	Theme      string                 `json:"theme" yaml:"theme"`
+	Difficulty string                 `json:"difficulty,omitempty" yaml:"difficulty,omitempty"` // default: saran analyzer
+	IsActive   *bool                  `json:"is_active,omitempty" yaml:"is_active,omitempty"`   // kosong = tidak diubah (true untuk stage baru)
+	Phrases    []BundlePhraseDocument `json:"phrases" yaml:"phrases"`
+	// Kosong = charset stage yang ada (qwerty untuk stage baru)
+	PhraseCharset string `json:"phrase_charset,omitempty" yaml:"phrase_charset,omitempty"`
+	// Kosong = pool stage yang ada (0 untuk stage baru)
+	PhrasePoolSize *int `json:"phrase_pool_size,omitempty" yaml:"phrase_pool_size,omitempty"`
+	// Kosong = whitespace policy stage yang ada (strict untuk stage baru)
+	WhitespacePolicy string `json:"whitespace_policy,omitempty" yaml:"whitespace_policy,omitempty"`
+	// Kosong = bahasa phrase stage yang ada
+	PhraseLanguage string `json:"phrase_language,omitempty" yaml:"phrase_language,omitempty"`
+}
+
+type BundlePhraseDocument struct {
+	Text       string   `json:"text" yaml:"text"`
+	Multiplier *float64 `json:"multiplier,omitempty" yaml:"multiplier,omitempty"` // default: saran analyzer
+}
+
+type ImportReportResponse struct {
+	DryRun  bool                  `json:"dry_run"`
+	Summary ImportSummary         `json:"summary"`
+	Themes  []ThemeChangeResponse `json:"themes"`
+	Stages  []StageChangeResponse `json:"stages"`
+}
+
+type ImportSummary struct {
+	ThemesCreated   int `json:"themes_created"`
+	ThemesUpdated   int `json:"themes_updated"`
+	StagesCreated   int `json:"stages_created"`
+	StagesUpdated   int `json:"stages_updated"`
+	StagesUnchanged int `json:"stages_unchanged"`
+	PhrasesAdded    int `json:"phrases_added"`
+	PhrasesUpdated  int `json:"phrases_updated"`
+	PhrasesRemoved  int `json:"phrases_removed"`
+}
+
+type ThemeChangeResponse struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+type StageChangeResponse struct {
+	Slug    string                 `json:"slug"`
+	StageID string                 `json:"stage_id,omitempty"`
+	Action  string                 `json:"action"`
+	Fields  []string               `json:"fields,omitempty"`
+	Phrases []PhraseChangeResponse `json:"phrases,omitempty"`
+}
+
+type PhraseChangeResponse struct {
+	Position      int     `json:"position"`
+	Action        string  `json:"action"`
+	Text          string  `json:"text"`
+	OldText       string  `json:"old_text,omitempty"`
+	Multiplier    float64 `json:"multiplier"`
+	OldMultiplier float64 `json:"old_multiplier,omitempty"`
+	Moved         bool    `json:"moved,omitempty"`
+}
+
 // Generic Response
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ValidationErrorResponse dipakai jika beberapa field input tidak valid
+type ValidationErrorResponse struct {
+	Error  string               `json:"error"`
+	Fields []FieldErrorResponse `json:"fields"`
+}
+
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
+
+// Translation DTOs
+
+// StageTranslationRequest - nama stage dalam locale di path
+type StageTranslationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// ThemeTranslationRequest - nama dan deskripsi theme dalam locale di path
+type ThemeTranslationRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+type TranslationResponse struct {
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TranslationListResponse - default_locale adalah locale teks asli
+// stage/theme (tidak punya terjemahan)
+type TranslationListResponse struct {
+	DefaultLocale string                `json:"default_locale"`
+	Translations  []TranslationResponse `json:"translations"`
+}
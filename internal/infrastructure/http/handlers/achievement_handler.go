@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AchievementHandler struct {
+	achievementService *services.AchievementService
+}
+
+func NewAchievementHandler(achievementService *services.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+// GetMyAchievements - semua badge beserta status earned milik user yang login
+func (h *AchievementHandler) GetMyAchievements(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	statuses, err := h.achievementService.GetUserAchievements(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := []dto.PlayerAchievementEntry{}
+	for _, status := range statuses {
+		entry := dto.PlayerAchievementEntry{
+			BadgeResponse: toBadgeResponse(status.Achievement),
+			Earned:        status.Earned,
+		}
+		if status.AwardedAt != nil {
+			entry.AwardedAt = status.AwardedAt.Format(time.RFC3339)
+		}
+		response = append(response, entry)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Admin: achievement management
+func (h *AchievementHandler) GetAllAchievements(c *gin.Context) {
+	achievements, err := h.achievementService.GetAllAchievements(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := []dto.AchievementResponse{}
+	for _, achievement := range achievements {
+		response = append(response, toAchievementResponse(achievement))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AchievementHandler) CreateAchievement(c *gin.Context) {
+	var req dto.AchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	achievement, err := h.achievementService.CreateAchievement(c.Request.Context(), toAchievementModel(req))
+	if err != nil {
+		writeAchievementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAchievementResponse(achievement))
+}
+
+func (h *AchievementHandler) UpdateAchievement(c *gin.Context) {
+	var req dto.AchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	achievement, err := h.achievementService.UpdateAchievement(c.Request.Context(), c.Param("id"), toAchievementModel(req))
+	if err != nil {
+		writeAchievementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAchievementResponse(achievement))
+}
+
+func (h *AchievementHandler) DeleteAchievement(c *gin.Context) {
+	if err := h.achievementService.DeleteAchievement(c.Request.Context(), c.Param("id")); err != nil {
+		writeAchievementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "achievement deleted successfully"})
+}
+
+func toAchievementModel(req dto.AchievementRequest) *models.Achievement {
+	return &models.Achievement{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		RuleType:    req.RuleType,
+		Params:      req.Params,
+		IsActive:    req.IsActive,
+	}
+}
+
+func toAchievementResponse(achievement *models.Achievement) dto.AchievementResponse {
+	return dto.AchievementResponse{
+		ID:          achievement.ID,
+		Code:        achievement.Code,
+		Name:        achievement.Name,
+		Description: achievement.Description,
+		RuleType:    achievement.RuleType,
+		Params:      achievement.Params,
+		IsActive:    achievement.IsActive,
+		CreatedAt:   achievement.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   achievement.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toBadgeResponse(achievement *models.Achievement) dto.BadgeResponse {
+	return dto.BadgeResponse{
+		ID:          achievement.ID,
+		Code:        achievement.Code,
+		Name:        achievement.Name,
+		Description: achievement.Description,
+	}
+}
+
+func writeAchievementError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrAchievementNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrAchievementCodeExists:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case domainservices.ErrUnknownRuleType, domainservices.ErrInvalidRuleParams:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
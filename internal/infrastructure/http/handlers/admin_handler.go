@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
 	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,16 +33,92 @@ func (h *AdminHandler) GetAllThemes(c *gin.Context) {
 
 	var response []dto.ThemeResponse
 	for _, theme := range themes {
-		response = append(response, dto.ThemeResponse{
-			ID:          theme.ID,
-			Name:        theme.Name,
-			Description: theme.Description,
-		})
+		response = append(response, toThemeResponse(theme))
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *AdminHandler) CreateTheme(c *gin.Context) {
+	var req dto.CreateThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	theme, err := h.adminService.CreateTheme(
+		c.Request.Context(),
+		req.Name,
+		req.Description,
+		req.Color,
+		req.IconKey,
+		req.SortOrder,
+	)
+	if err != nil {
+		writeThemeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toThemeResponse(theme))
+}
+
+func (h *AdminHandler) UpdateTheme(c *gin.Context) {
+	var req dto.UpdateThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	theme, err := h.adminService.UpdateTheme(
+		c.Request.Context(),
+		c.Param("id"),
+		req.Name,
+		req.Description,
+		req.Color,
+		req.IconKey,
+		req.SortOrder,
+	)
+	if err != nil {
+		writeThemeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toThemeResponse(theme))
+}
+
+func (h *AdminHandler) DeleteTheme(c *gin.Context) {
+	if err := h.adminService.DeleteTheme(c.Request.Context(), c.Param("id")); err != nil {
+		writeThemeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "theme deleted successfully"})
+}
+
+func toThemeResponse(theme *models.Theme) dto.ThemeResponse {
+	return dto.ThemeResponse{
+		ID:          theme.ID,
+		Name:        theme.Name,
+		Description: theme.Description,
+		Color:       theme.Color,
+		IconKey:     theme.IconKey,
+		SortOrder:   theme.SortOrder,
+	}
+}
+
+func writeThemeError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrThemeNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "theme not found"})
+	case services.ErrInvalidThemeName, services.ErrInvalidThemeColor, services.ErrInvalidIconKey:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrThemeNameExists, services.ErrThemeInUse:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
 // Stage Management
 func (h *AdminHandler) CreateStage(c *gin.Context) {
 	var req dto.CreateStageRequest
@@ -48,22 +130,24 @@ func (h *AdminHandler) CreateStage(c *gin.Context) {
 	stage, err := h.adminService.CreateStage(
 		c.Request.Context(),
 		req.Name,
+		req.Slug,
 		req.ThemeID,
 		req.Difficulty,
 		req.IsActive,
+		req.AvailableFrom,
+		req.AvailableUntil,
+		req.PhraseCharset,
+		req.PhrasePoolSize,
+		req.WhitespacePolicy,
+		req.PhraseLanguage,
+		req.Phrases,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writeStageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.StageResponse{
-		ID:         stage.ID,
-		Name:       stage.Name,
-		ThemeID:    stage.ThemeID,
-		Difficulty: stage.Difficulty,
-		IsActive:   stage.IsActive,
-	})
+	c.JSON(http.StatusCreated, toAdminStageResponse(stage))
 }
 
 func (h *AdminHandler) UpdateStage(c *gin.Context) {
@@ -79,26 +163,23 @@ func (h *AdminHandler) UpdateStage(c *gin.Context) {
 		c.Request.Context(),
 		stageID,
 		req.Name,
+		req.Slug,
 		req.ThemeID,
 		req.Difficulty,
 		req.IsActive,
+		req.AvailableFrom,
+		req.AvailableUntil,
+		req.PhraseCharset,
+		req.PhrasePoolSize,
+		req.WhitespacePolicy,
+		req.PhraseLanguage,
 	)
 	if err != nil {
-		if err == services.ErrStageNotFound {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writeStageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.StageResponse{
-		ID:         stage.ID,
-		Name:       stage.Name,
-		ThemeID:    stage.ThemeID,
-		Difficulty: stage.Difficulty,
-		IsActive:   stage.IsActive,
-	})
+	c.JSON(http.StatusOK, toAdminStageResponse(stage))
 }
 
 func (h *AdminHandler) DeleteStage(c *gin.Context) {
@@ -114,26 +195,206 @@ func (h *AdminHandler) DeleteStage(c *gin.Context) {
 }
 
 func (h *AdminHandler) GetAllStages(c *gin.Context) {
-	stages, err := h.adminService.GetAllStages(c.Request.Context())
+	stages, err := h.adminService.GetAllStages(c.Request.Context(), c.Query("phrase_lang"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writeStageError(c, err)
 		return
 	}
 
 	var response []dto.StageResponse
 	for _, stage := range stages {
-		response = append(response, dto.StageResponse{
-			ID:         stage.ID,
-			Name:       stage.Name,
-			ThemeID:    stage.ThemeID,
-			Difficulty: stage.Difficulty,
-			IsActive:   stage.IsActive,
-		})
+		response = append(response, toAdminStageResponse(stage))
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+func toAdminStageResponse(stage *models.Stage) dto.StageResponse {
+	response := dto.StageResponse{
+		ID:         stage.ID,
+		Slug:       stage.Slug,
+		Name:       stage.Name,
+		ThemeID:    stage.ThemeID,
+		Difficulty: stage.Difficulty,
+		IsActive:   stage.IsActive,
+		VersionID:  stage.PublishedVersionID,
+
+		PhraseCharset:    stage.PhraseCharset,
+		PhrasePoolSize:   stage.PhrasePoolSize,
+		WhitespacePolicy: stage.WhitespacePolicy,
+		PhraseLanguage:   stage.PhraseLanguage,
+	}
+	setStageSchedule(&response, stage, time.Now())
+	return response
+}
+
+func writeStageError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(c, validationErr)
+		return
+	}
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrThemeNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "theme not found"})
+	case services.ErrInvalidStageSlug:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrStageSlugExists, services.ErrNothingToPublish:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrStageHasNoPhrases, services.ErrInvalidAvailabilityWindow, services.ErrInvalidPhraseCharset,
+		services.ErrInvalidPhrasePoolSize, services.ErrInvalidWhitespacePolicy, services.ErrInvalidPhraseLanguage,
+		services.ErrInvalidMultiplierScale:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+// CloneStage - salin stage beserta phrase-nya menjadi draft baru yang belum
+// aktif. Body opsional: {"name": "...", "theme_id": "...", "multiplier_scale": 1.2}
+func (h *AdminHandler) CloneStage(c *gin.Context) {
+	var req dto.CloneStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	stage, err := h.adminService.CloneStage(c.Request.Context(), c.Param("id"), req.Name, req.ThemeID, req.MultiplierScale)
+	if err != nil {
+		writeStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAdminStageResponse(stage))
+}
+
+// Stage Versions
+
+// PublishStage - simpan draft stage sebagai versi baru yang dimainkan pemain.
+// Body opsional: {"reset_leaderboard": true}
+func (h *AdminHandler) PublishStage(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.PublishStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	version, err := h.adminService.PublishStage(c.Request.Context(), c.Param("id"), user.ID, req.ResetLeaderboard)
+	if err != nil {
+		writeStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toStageVersionResponse(version))
+}
+
+func (h *AdminHandler) GetStageVersions(c *gin.Context) {
+	history, err := h.adminService.GetStageVersions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeStageError(c, err)
+		return
+	}
+
+	response := dto.StageVersionHistoryResponse{
+		HasUnpublishedChanges: history.HasUnpublishedChanges,
+		Versions:              []dto.StageVersionResponse{},
+	}
+	for _, version := range history.Versions {
+		response.Versions = append(response.Versions, toStageVersionResponse(version))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func toStageVersionResponse(version *models.StageVersion) dto.StageVersionResponse {
+	return dto.StageVersionResponse{
+		ID:               version.ID,
+		Version:          version.VersionNumber,
+		Name:             version.Name,
+		WhitespacePolicy: version.WhitespacePolicy,
+		PhrasePoolSize:   version.PhrasePoolSize,
+		PhraseLanguage:   version.PhraseLanguage,
+		LeaderboardReset: version.LeaderboardReset,
+		PublishedBy:      version.PublishedBy,
+		PublishedAt:      version.PublishedAt,
+		PhraseCount:      version.PhraseCount,
+	}
+}
+
+// Stage Prerequisites
+func (h *AdminHandler) GetStagePrerequisites(c *gin.Context) {
+	prerequisites, err := h.adminService.GetStagePrerequisites(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writePrerequisiteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPrerequisiteResponses(prerequisites))
+}
+
+// SetStagePrerequisites - ganti semua prerequisite stage (list kosong = tanpa syarat)
+func (h *AdminHandler) SetStagePrerequisites(c *gin.Context) {
+	stageID := c.Param("id")
+
+	var req dto.SetStagePrerequisitesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var prerequisites []*models.StagePrerequisite
+	for _, item := range req.Prerequisites {
+		prerequisites = append(prerequisites, &models.StagePrerequisite{
+			StageID:         stageID,
+			RequiredStageID: item.RequiredStageID,
+			MinStars:        item.MinStars,
+			MinAccuracy:     item.MinAccuracy,
+		})
+	}
+
+	saved, err := h.adminService.SetStagePrerequisites(c.Request.Context(), stageID, prerequisites)
+	if err != nil {
+		writePrerequisiteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPrerequisiteResponses(saved))
+}
+
+func toPrerequisiteResponses(prerequisites []*models.StagePrerequisite) []dto.StagePrerequisiteResponse {
+	response := []dto.StagePrerequisiteResponse{}
+	for _, prerequisite := range prerequisites {
+		response = append(response, dto.StagePrerequisiteResponse{
+			RequiredStageID:   prerequisite.RequiredStageID,
+			RequiredStageName: prerequisite.RequiredStageName,
+			MinStars:          prerequisite.MinStars,
+			MinAccuracy:       prerequisite.MinAccuracy,
+		})
+	}
+	return response
+}
+
+func writePrerequisiteError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrRequiredStageNotFound, domainservices.ErrInvalidPrerequisite:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case domainservices.ErrPrerequisiteCycle:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
 // Phrase Management
 func (h *AdminHandler) CreatePhrase(c *gin.Context) {
 	var req dto.CreatePhraseRequest
@@ -150,7 +411,7 @@ func (h *AdminHandler) CreatePhrase(c *gin.Context) {
 		req.BaseMultiplier,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writePhraseError(c, err)
 		return
 	}
 
@@ -181,7 +442,7 @@ func (h *AdminHandler) UpdatePhrase(c *gin.Context) {
 		req.BaseMultiplier,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writePhraseError(c, err)
 		return
 	}
 
@@ -194,6 +455,22 @@ func (h *AdminHandler) UpdatePhrase(c *gin.Context) {
 	})
 }
 
+func writePhraseError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(c, validationErr)
+		return
+	}
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrPhraseNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
 func (h *AdminHandler) DeletePhrase(c *gin.Context) {
 	phraseID := c.Param("id")
 
@@ -232,3 +509,92 @@ func (h *AdminHandler) GetPhrasesByStage(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// AnalyzeStage - saran multiplier tiap phrase draft dan difficulty stage
+func (h *AdminHandler) AnalyzeStage(c *gin.Context) {
+	analysis, err := h.adminService.AnalyzeStage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toStageAnalysisResponse(analysis))
+}
+
+// AnalyzePhrases - saran multiplier dan difficulty untuk teks yang belum
+// disimpan
+func (h *AdminHandler) AnalyzePhrases(c *gin.Context) {
+	var req dto.AnalyzePhrasesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	analysis, err := h.adminService.AnalyzePhrases(req.Texts, req.WhitespacePolicy)
+	if err != nil {
+		writeStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toStageAnalysisResponse(analysis))
+}
+
+func toStageAnalysisResponse(analysis *models.StageAnalysis) dto.StageAnalysisResponse {
+	response := dto.StageAnalysisResponse{
+		StageID:             analysis.StageID,
+		Difficulty:          analysis.Difficulty,
+		Score:               analysis.Score,
+		SuggestedDifficulty: analysis.SuggestedDifficulty,
+		Phrases:             []dto.PhraseAnalysisResponse{},
+	}
+	for _, phrase := range analysis.Phrases {
+		response.Phrases = append(response.Phrases, dto.PhraseAnalysisResponse{
+			PhraseID:            phrase.PhraseID,
+			Text:                phrase.Text,
+			Length:              phrase.Length,
+			SymbolDensity:       phrase.SymbolDensity,
+			ShiftRatio:          phrase.ShiftRatio,
+			FingerTravel:        phrase.FingerTravel,
+			RareBigramRatio:     phrase.RareBigramRatio,
+			Score:               phrase.Score,
+			Multiplier:          phrase.BaseMultiplier,
+			SuggestedMultiplier: phrase.SuggestedMultiplier,
+		})
+	}
+	return response
+}
+
+// ReorderPhrases - ganti urutan semua phrase stage sekaligus
+func (h *AdminHandler) ReorderPhrases(c *gin.Context) {
+	var req dto.ReorderPhrasesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	phrases, err := h.adminService.ReorderPhrases(c.Request.Context(), c.Param("id"), req.PhraseIDs)
+	if err != nil {
+		switch err {
+		case services.ErrStageNotFound:
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+		case services.ErrPhraseOrderMismatch:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	response := []dto.PhraseResponse{}
+	for _, phrase := range phrases {
+		response = append(response, dto.PhraseResponse{
+			ID:             phrase.ID,
+			StageID:        phrase.StageID,
+			Text:           phrase.Text,
+			SequenceNumber: phrase.SequenceNumber,
+			Multiplier:     phrase.BaseMultiplier,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DailyChallengeHandler struct {
+	dailyChallengeService *services.DailyChallengeService
+	translationService    *services.TranslationService
+}
+
+func NewDailyChallengeHandler(dailyChallengeService *services.DailyChallengeService, translationService *services.TranslationService) *DailyChallengeHandler {
+	return &DailyChallengeHandler{dailyChallengeService: dailyChallengeService, translationService: translationService}
+}
+
+// GetChallenge - challenge hari ini, atau challenge lama dengan ?date=YYYY-MM-DD
+func (h *DailyChallengeHandler) GetChallenge(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	view, err := h.dailyChallengeService.GetChallenge(c.Request.Context(), user.ID, c.Query("date"))
+	if err != nil {
+		writeDailyChallengeError(c, err)
+		return
+	}
+	h.writeChallenge(c, view, nil)
+}
+
+// StartSession - memulai run challenge hari ini (atau ?date= untuk replay);
+// session_id di response wajib dikirim saat submit
+func (h *DailyChallengeHandler) StartSession(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	view, session, err := h.dailyChallengeService.StartSession(c.Request.Context(), user.ID, c.Query("date"))
+	if err != nil {
+		writeDailyChallengeError(c, err)
+		return
+	}
+	h.writeChallenge(c, view, session)
+}
+
+func (h *DailyChallengeHandler) writeChallenge(c *gin.Context, view *services.DailyChallengeView, session *models.DailyChallengeSession) {
+	// Nama stage asal phrase mengikuti locale yang diminta
+	var stageIDs []string
+	for _, phrase := range view.Challenge.Phrases {
+		stageIDs = append(stageIDs, phrase.StageID)
+	}
+	stageNames, err := h.translationService.PlayerStageNames(c.Request.Context(), stageIDs, requestLocales(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	phrases := []dto.PhraseResponse{}
+	for _, phrase := range view.Challenge.Phrases {
+		phrases = append(phrases, dto.PhraseResponse{
+			ID:             phrase.ID,
+			StageID:        phrase.StageID,
+			StageName:      stageNames[phrase.StageID],
+			Text:           phrase.Text,
+			SequenceNumber: phrase.SequenceNumber,
+			Multiplier:     phrase.BaseMultiplier,
+		})
+	}
+
+	response := dto.DailyChallengeResponse{
+		Date:              view.Challenge.Date.Format("2006-01-02"),
+		IsToday:           view.IsToday,
+		RankedAttemptUsed: view.RankedAttemptUsed,
+		Phrases:           phrases,
+	}
+	if session != nil {
+		response.SessionID = session.ID
+		response.SessionRanked = &session.Ranked
+		response.SessionExpiresAt = &session.ExpiresAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *DailyChallengeHandler) SubmitAttempt(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.SubmitDailyChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := h.dailyChallengeService.SubmitAttempt(c.Request.Context(), user.ID, req.SessionID, req.TotalTimeMs, req.TotalErrors)
+	if err != nil {
+		writeDailyChallengeError(c, err)
+		return
+	}
+
+	response := dto.SubmitDailyChallengeResponse{
+		Status:     "UNRANKED",
+		FinalScore: result.Attempt.FinalScore,
+		Wpm:        result.Attempt.Wpm,
+		Accuracy:   result.Attempt.Accuracy,
+	}
+	if result.Attempt.Ranked {
+		response.Status = "RANKED"
+	}
+	if result.Rank != nil {
+		response.Rank = result.Rank.Rank
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *DailyChallengeHandler) GetLeaderboard(c *gin.Context) {
+	limit, err := parseLimitQuery(c, "limit", services.DefaultLeaderboardLimit, services.MaxLeaderboardLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	challenge, entries, err := h.dailyChallengeService.GetLeaderboard(c.Request.Context(), c.Query("date"), limit)
+	if err != nil {
+		writeDailyChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DailyLeaderboardResponse{
+		Date:    challenge.Date.Format("2006-01-02"),
+		Entries: toLeaderboardEntries(entries, middleware.GetUserFromContext(c)),
+	})
+}
+
+func writeDailyChallengeError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidChallengeDate:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "date must be in YYYY-MM-DD format"})
+	case services.ErrInvalidLimit:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+	case services.ErrDailyTimeExceedsSession:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrDailyChallengeNotFound, services.ErrDailyChallengeUnavailable, services.ErrGameSessionNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrGameSessionSubmitted:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrGameSessionExpired:
+		c.JSON(http.StatusGone, dto.ErrorResponse{Error: err.Error()})
+	default:
+		if domainErr, ok := err.(*domainservices.DomainError); ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: domainErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
-	"strconv"
+	"time"
 
 	"uwika_quick_typer_game/internal/application/services"
-	"uwika_quick_typer_game/internal/domain/repositories"
+	"uwika_quick_typer_game/internal/domain/models"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
 	"uwika_quick_typer_game/internal/infrastructure/http/dto"
 	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
 
@@ -13,52 +15,162 @@ import (
 )
 
 type GameHandler struct {
-	gameService *services.GameService
-	userRepo    repositories.UserRepository
+	gameService        *services.GameService
+	translationService *services.TranslationService
 }
 
-func NewGameHandler(gameService *services.GameService, userRepo repositories.UserRepository) *GameHandler {
-	return &GameHandler{
-		gameService: gameService,
-		userRepo:    userRepo,
+func NewGameHandler(gameService *services.GameService, translationService *services.TranslationService) *GameHandler {
+	return &GameHandler{gameService: gameService, translationService: translationService}
+}
+
+// GetThemes - semua theme dalam locale yang diminta (lihat requestLocales)
+func (h *GameHandler) GetThemes(c *gin.Context) {
+	themes, err := h.translationService.GetThemes(c.Request.Context(), requestLocales(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := []dto.ThemeResponse{}
+	for _, theme := range themes {
+		response = append(response, toThemeResponse(theme))
 	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *GameHandler) GetStages(c *gin.Context) {
-	stages, err := h.gameService.GetActiveStages(c.Request.Context())
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	stages, err := h.gameService.GetStagesForUser(ctx, user.ID, c.Query("phrase_lang"))
+	if err == services.ErrInvalidPhraseLanguage {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	var response []dto.StageResponse
-	for _, stage := range stages {
-		response = append(response, dto.StageResponse{
-			ID:         stage.ID,
-			Name:       stage.Name,
-			Difficulty: stage.Difficulty,
-			IsActive:   stage.IsActive,
-		})
+	// Nama stage dan theme mengikuti locale yang diminta
+	locales := requestLocales(c)
+	list := make([]*models.Stage, 0, len(stages))
+	for _, progress := range stages {
+		list = append(list, progress.Stage)
+	}
+	if err := h.translationService.LocalizeStages(ctx, list, locales); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	themes, err := h.translationService.GetThemes(ctx, locales)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	themeNames := make(map[string]string, len(themes))
+	for _, theme := range themes {
+		themeNames[theme.ID] = theme.Name
+	}
+	stageNames := make(map[string]string, len(list))
+	for _, stage := range list {
+		stageNames[stage.ID] = stage.Name
+	}
+	// Prerequisite yang tidak ada di daftar (mis. nonaktif) memakai nama
+	// aslinya, tetap dilokalisasi
+	otherNames := make(map[string]string)
+	for _, progress := range stages {
+		for _, requirement := range progress.Requirements {
+			if _, ok := stageNames[requirement.Prerequisite.RequiredStageID]; !ok {
+				otherNames[requirement.Prerequisite.RequiredStageID] = requirement.Prerequisite.RequiredStageName
+			}
+		}
+	}
+	if !localizeStageNames(c, h.translationService, otherNames) {
+		return
+	}
+	for stageID, name := range otherNames {
+		stageNames[stageID] = name
+	}
+
+	now := time.Now()
+	var response []dto.StageListEntry
+	for _, progress := range stages {
+		entry := dto.StageListEntry{
+			StageResponse: dto.StageResponse{
+				ID:         progress.Stage.ID,
+				Name:       progress.Stage.Name,
+				ThemeID:    progress.Stage.ThemeID,
+				ThemeName:  themeNames[progress.Stage.ThemeID],
+				Difficulty: progress.Stage.Difficulty,
+				IsActive:   progress.Stage.IsActive,
+
+				PhraseLanguage: progress.Stage.PhraseLanguage,
+			},
+			Locked: progress.Locked,
+		}
+		setStageSchedule(&entry.StageResponse, progress.Stage, now)
+		for _, requirement := range progress.Requirements {
+			entry.Requirements = append(entry.Requirements, dto.StageRequirementResponse{
+				StageID:      requirement.Prerequisite.RequiredStageID,
+				StageName:    stageNames[requirement.Prerequisite.RequiredStageID],
+				MinStars:     requirement.Prerequisite.MinStars,
+				MinAccuracy:  requirement.Prerequisite.MinAccuracy,
+				BestAccuracy: requirement.BestAccuracy,
+				Stars:        requirement.Stars,
+				Met:          requirement.Met,
+			})
+		}
+		response = append(response, entry)
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *GameHandler) GetStageDetail(c *gin.Context) {
-	stageID := c.Param("id")
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
 
-	stage, phrases, err := h.gameService.GetStageWithPhrases(c.Request.Context(), stageID)
+	stage, version, err := h.gameService.GetStageForPlayer(c.Request.Context(), user.ID, c.Param("id"))
 	if err != nil {
-		if err == services.ErrStageNotFound {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
-			return
-		}
+		writePlayStageError(c, err)
+		return
+	}
+	h.writePlayerStage(c, stage, version, nil)
+}
+
+// StartSession memulai (atau melanjutkan) sesi permainan stage dan
+// mengembalikan phrase yang harus diketik
+func (h *GameHandler) StartSession(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	stage, version, session, err := h.gameService.StartSession(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		writePlayStageError(c, err)
+		return
+	}
+	h.writePlayerStage(c, stage, version, session)
+}
+
+func (h *GameHandler) writePlayerStage(c *gin.Context, stage *models.Stage, version *models.StageVersion, session *models.GameSession) {
+	if err := h.translationService.LocalizeStages(c.Request.Context(), []*models.Stage{stage}, requestLocales(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	var phrasesResponse []dto.PhraseResponse
-	for _, phrase := range phrases {
+	for _, phrase := range version.Phrases {
 		phrasesResponse = append(phrasesResponse, dto.PhraseResponse{
 			ID:             phrase.ID,
 			Text:           phrase.Text,
@@ -73,12 +185,48 @@ func (h *GameHandler) GetStageDetail(c *gin.Context) {
 		ThemeID:    stage.ThemeID,
 		Difficulty: stage.Difficulty,
 		IsActive:   stage.IsActive,
+		VersionID:  version.ID,
+		Version:    version.VersionNumber,
 		Phrases:    phrasesResponse,
+
+		WhitespacePolicy: stage.WhitespacePolicy,
+		PhraseLanguage:   stage.PhraseLanguage,
+		PhrasePoolSize:   stage.PhrasePoolSize,
+	}
+	if session != nil {
+		response.SessionID = session.ID
+		response.SessionExpiresAt = &session.ExpiresAt
 	}
+	setStageSchedule(&response, stage, time.Now())
 
 	c.JSON(http.StatusOK, response)
 }
 
+func writePlayStageError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrStageLocked:
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "stage is locked"})
+	case services.ErrStageNotAvailable:
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: "stage is not available"})
+	case services.ErrGameSessionNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrStageVersionOutdated, services.ErrGameSessionSubmitted:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrGameSessionExpired:
+		c.JSON(http.StatusGone, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrGameSessionRequired:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		if domainErr, ok := err.(*domainservices.DomainError); ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: domainErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
 func (h *GameHandler) SubmitScore(c *gin.Context) {
 	user := middleware.GetUserFromContext(c)
 	if user == nil {
@@ -92,60 +240,75 @@ func (h *GameHandler) SubmitScore(c *gin.Context) {
 		return
 	}
 
-	score, status, err := h.gameService.SubmitScore(
+	var latencies []models.TypingLatency
+	var mistakes []models.TypingMistake
+	for _, phrase := range req.Phrases {
+		if len(phrase.LatenciesMs) > 0 {
+			latencies = append(latencies, models.TypingLatency{PhraseID: phrase.PhraseID, LatenciesMs: phrase.LatenciesMs})
+		}
+		for _, mistake := range phrase.Mistakes {
+			mistakes = append(mistakes, models.TypingMistake{
+				PhraseID:  phrase.PhraseID,
+				Position:  mistake.Position,
+				Expected:  mistake.Expected,
+				Typed:     mistake.Typed,
+				LatencyMs: mistake.LatencyMs,
+			})
+		}
+	}
+
+	result, err := h.gameService.SubmitScore(
 		c.Request.Context(),
 		user.ID,
 		req.StageID,
+		req.StageVersionID,
+		req.SessionID,
 		req.TotalTimeMs,
 		req.TotalErrors,
+		latencies,
+		mistakes,
 	)
 	if err != nil {
-		if err == services.ErrStageNotFound {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		writePlayStageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SubmitScoreResponse{
-		Status:     status,
-		FinalScore: score.FinalScore,
-	})
-}
-
-func (h *GameHandler) GetLeaderboard(c *gin.Context) {
-	stageID := c.Query("stage_id")
-	if stageID == "" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "stage_id is required"})
-		return
+	newAchievements := []dto.BadgeResponse{}
+	for _, achievement := range result.NewAchievements {
+		newAchievements = append(newAchievements, toBadgeResponse(achievement))
 	}
 
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		limit = 20
+	response := dto.SubmitScoreResponse{
+		Status:          result.Status,
+		FinalScore:      result.Score.FinalScore,
+		NewAchievements: newAchievements,
 	}
-
-	scores, err := h.gameService.GetLeaderboard(c.Request.Context(), stageID, limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
-		return
-	}
-
-	var response []dto.LeaderboardEntry
-	for _, score := range scores {
-		// Get username
-		user, err := h.userRepo.FindByID(c.Request.Context(), score.UserID)
-		if err != nil {
-			continue
+	if result.Progress != nil {
+		response.Progress = &dto.AttemptProgressResponse{
+			XPGained:               result.Progress.XPGained,
+			LeveledUp:              result.Progress.LeveledUp,
+			PlayerProgressResponse: toPlayerProgressResponse(&result.Progress.PlayerProgress),
 		}
-		response = append(response, dto.LeaderboardEntry{
-			Username:    user.Username,
-			FinalScore:  score.FinalScore,
-			TotalTimeMs: score.TotalTimeMs,
-		})
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// setStageSchedule mengisi jadwal stage beserta countdown dalam detik
+// (dibulatkan ke atas) sampai stage buka atau tutup
+func setStageSchedule(response *dto.StageResponse, stage *models.Stage, now time.Time) {
+	response.AvailableFrom = stage.AvailableFrom
+	response.AvailableUntil = stage.AvailableUntil
+	if stage.AvailableFrom != nil && now.Before(*stage.AvailableFrom) {
+		seconds := secondsUntil(*stage.AvailableFrom, now)
+		response.OpensInSeconds = &seconds
+	}
+	if stage.AvailableUntil != nil && now.Before(*stage.AvailableUntil) {
+		seconds := secondsUntil(*stage.AvailableUntil, now)
+		response.ClosesInSeconds = &seconds
+	}
+}
+
+func secondsUntil(t, now time.Time) int64 {
+	return int64(math.Ceil(t.Sub(now).Seconds()))
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *services.LeaderboardService
+	leaderboardStream  *services.LeaderboardStream
+	gameService        *services.GameService
+}
+
+func NewLeaderboardHandler(leaderboardService *services.LeaderboardService, leaderboardStream *services.LeaderboardStream, gameService *services.GameService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+		leaderboardStream:  leaderboardStream,
+		gameService:        gameService,
+	}
+}
+
+// GetLeaderboard - leaderboard per stage dengan keyset pagination
+// (cursor) atau mode around=me. Tanpa period, board season aktif yang
+// ditampilkan (all_time jika tidak ada season).
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	stageID := c.Query("stage_id")
+	if stageID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "stage_id is required"})
+		return
+	}
+	period, err := h.leaderboardService.ResolvePeriod(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	if around := c.Query("around"); around != "" {
+		h.getLeaderboardAroundMe(c, stageID, period, around)
+		return
+	}
+
+	limit, err := parseLimitQuery(c, "limit", services.DefaultLeaderboardLimit, services.MaxLeaderboardLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	page, err := h.leaderboardService.GetLeaderboard(c.Request.Context(), stageID, period, c.Query("cursor"), limit)
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	user := middleware.GetUserFromContext(c)
+	response := dto.LeaderboardResponse{
+		StageID:    stageID,
+		Period:     period,
+		Entries:    toLeaderboardEntries(page.Entries, user),
+		Limit:      page.Limit,
+		MaxLimit:   page.MaxLimit,
+		NextCursor: page.NextCursor,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LeaderboardHandler) getLeaderboardAroundMe(c *gin.Context, stageID, period, around string) {
+	if around != "me" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "around only supports 'me'"})
+		return
+	}
+
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	k, err := parseLimitQuery(c, "k", services.DefaultAroundRadius, services.MaxAroundRadius)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, err := h.leaderboardService.GetLeaderboardAroundUser(c.Request.Context(), stageID, period, user.ID, k)
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LeaderboardResponse{
+		StageID: stageID,
+		Period:  period,
+		Entries: toLeaderboardEntries(entries, user),
+		Around:  "me",
+		K:       k,
+	})
+}
+
+// StreamLeaderboard - Server-Sent Events berisi snapshot top-N leaderboard
+// default stage (season aktif, atau all-time jika tidak ada season) setiap
+// kali ranking berubah. Route ini publik (read-only) karena EventSource di
+// browser tidak bisa mengirim header Authorization, sehingga hanya stage
+// aktif yang sudah dipublish yang bisa di-stream. Client yang reconnect
+// dengan Last-Event-ID hanya menerima snapshot awal jika ada perubahan
+// sejak itu.
+func (h *LeaderboardHandler) StreamLeaderboard(c *gin.Context) {
+	stageID := c.Query("stage_id")
+	if stageID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "stage_id is required"})
+		return
+	}
+	if _, err := uuid.Parse(stageID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+		return
+	}
+	stage, _, err := h.gameService.GetPublishedStage(c.Request.Context(), stageID)
+	if err == nil && !stage.IsActive {
+		err = services.ErrStageNotFound
+	}
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+
+	// Subscribe sebelum snapshot supaya tidak ada perubahan yang terlewat
+	events, cancel := h.leaderboardStream.Subscribe(stageID)
+	defer cancel()
+
+	snapshot, err := h.leaderboardStream.Snapshot(c.Request.Context(), stageID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds())
+	if snapshot.EventID() != lastEventID {
+		writeLeaderboardEvent(c, snapshot)
+		lastEventID = snapshot.EventID()
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event := <-events:
+			if !event.Reset && event.EventID() == lastEventID {
+				continue
+			}
+			writeLeaderboardEvent(c, event)
+			lastEventID = event.EventID()
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+const (
+	sseHeartbeat = 15 * time.Second
+	sseRetry     = 3 * time.Second
+)
+
+func writeLeaderboardEvent(c *gin.Context, event *services.LeaderboardEvent) {
+	viewer := middleware.GetUserFromContext(c)
+	data := dto.LeaderboardStreamEvent{
+		StageID: event.StageID,
+		Entries: []dto.LeaderboardStreamEntry{},
+	}
+	for _, entry := range event.Entries {
+		data.Entries = append(data.Entries, dto.LeaderboardStreamEntry{
+			Rank:         entry.Rank,
+			PreviousRank: event.PreviousRanks[entry.UserID],
+			Username:     leaderboardName(entry.Username, entry.Anonymous, isViewer(viewer, entry.UserID)),
+			FinalScore:   entry.FinalScore,
+			TotalTimeMs:  entry.TotalTimeMs,
+		})
+	}
+	// Event reset berisi board lengkap yang menggantikan board lama;
+	// previous_rank tidak ada karena ranking lama sudah tidak berlaku
+	name := "leaderboard"
+	if event.Reset {
+		name = "reset"
+	}
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.EventID(), name, payload)
+}
+
+// GetAggregateLeaderboard - leaderboard gabungan global, per theme (theme_id)
+// atau per difficulty
+func (h *LeaderboardHandler) GetAggregateLeaderboard(c *gin.Context) {
+	themeID := c.Query("theme_id")
+	difficulty := c.Query("difficulty")
+	method := c.DefaultQuery("method", string(models.AggregationNormalizedSum))
+	period, err := h.leaderboardService.ResolvePeriod(c.Request.Context(), c.Query("period"))
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	limit, err := parseLimitQuery(c, "limit", services.DefaultLeaderboardLimit, services.MaxLeaderboardLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, err := h.leaderboardService.GetAggregateLeaderboard(c.Request.Context(), themeID, difficulty, method, period, limit)
+	if err != nil {
+		writeLeaderboardError(c, err)
+		return
+	}
+
+	scope := "global"
+	if themeID != "" {
+		scope = "theme"
+	} else if difficulty != "" {
+		scope = "difficulty"
+	}
+
+	response := dto.AggregateLeaderboardResponse{
+		Scope:      scope,
+		ThemeID:    themeID,
+		Difficulty: difficulty,
+		Method:     method,
+		Period:     period,
+		Entries:    []dto.AggregateLeaderboardEntry{},
+	}
+	viewer := middleware.GetUserFromContext(c)
+	for i, entry := range entries {
+		response.Entries = append(response.Entries, dto.AggregateLeaderboardEntry{
+			Rank:            i + 1,
+			Username:        leaderboardName(entry.Username, entry.Anonymous, isViewer(viewer, entry.UserID)),
+			AggregateScore:  entry.AggregateScore,
+			StagesCompleted: entry.StagesCompleted,
+			StagesTotal:     entry.StagesTotal,
+			TotalTimeMs:     entry.TotalTimeMs,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func toLeaderboardEntries(entries []*models.LeaderboardEntry, viewer *models.User) []dto.LeaderboardEntry {
+	response := []dto.LeaderboardEntry{}
+	for _, entry := range entries {
+		isMe := isViewer(viewer, entry.UserID)
+		response = append(response, dto.LeaderboardEntry{
+			Rank:        entry.Rank,
+			Username:    leaderboardName(entry.Username, entry.Anonymous, isMe),
+			FinalScore:  entry.FinalScore,
+			TotalTimeMs: entry.TotalTimeMs,
+			IsMe:        isMe,
+		})
+	}
+	return response
+}
+
+// anonymousPlayerName menggantikan username pemain yang memilih anonim di
+// leaderboard. Pemain itu sendiri tetap melihat username-nya.
+const anonymousPlayerName = "Anonymous"
+
+func leaderboardName(username string, anonymous, isMe bool) string {
+	if anonymous && !isMe {
+		return anonymousPlayerName
+	}
+	return username
+}
+
+func isViewer(viewer *models.User, userID string) bool {
+	return viewer != nil && viewer.ID == userID
+}
+
+func writeLeaderboardError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrInvalidPeriod:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "period must be one of daily, weekly, monthly, all_time, season"})
+	case services.ErrInvalidAggregation:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "method must be one of normalized_sum, average_percentile"})
+	case services.ErrInvalidDifficulty:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "difficulty must be one of easy, medium, hard"})
+	case services.ErrInvalidCursor:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid cursor"})
+	case services.ErrInvalidLimit:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+	case services.ErrThemeNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "theme not found"})
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrNoActiveSeason:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "no active season"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+// parseLimitQuery membaca query parameter integer 1..max. Nilai yang tidak
+// valid ditolak (tidak diganti default diam-diam).
+func parseLimitQuery(c *gin.Context, name string, defaultValue, max int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 || value > max {
+		return 0, fmt.Errorf("%s must be an integer between 1 and %d", name, max)
+	}
+	return value, nil
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlayerHandler struct {
+	playerService      *services.PlayerService
+	translationService *services.TranslationService
+}
+
+func NewPlayerHandler(playerService *services.PlayerService, translationService *services.TranslationService) *PlayerHandler {
+	return &PlayerHandler{playerService: playerService, translationService: translationService}
+}
+
+// GetScoreHistory - riwayat attempt user yang login, terbaru lebih dulu.
+// Filter opsional: stage_id, from, to (YYYY-MM-DD atau RFC3339)
+func (h *PlayerHandler) GetScoreHistory(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	limit, err := parseLimitQuery(c, "limit", services.DefaultLeaderboardLimit, services.MaxLeaderboardLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	page, err := h.playerService.GetScoreHistory(c.Request.Context(), user.ID, services.ScoreHistoryFilter{
+		StageID: c.Query("stage_id"),
+		From:    c.Query("from"),
+		To:      c.Query("to"),
+		Cursor:  c.Query("cursor"),
+		Limit:   limit,
+	})
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	stageNames := make(map[string]string)
+	for _, attempt := range page.Attempts {
+		stageNames[attempt.StageID] = attempt.StageName
+	}
+	if !localizeStageNames(c, h.translationService, stageNames) {
+		return
+	}
+
+	response := dto.ScoreHistoryResponse{
+		Scores:     []dto.ScoreHistoryEntry{},
+		Limit:      page.Limit,
+		MaxLimit:   page.MaxLimit,
+		NextCursor: page.NextCursor,
+	}
+	for _, attempt := range page.Attempts {
+		response.Scores = append(response.Scores, dto.ScoreHistoryEntry{
+			ScoreID:     attempt.ID,
+			StageID:     attempt.StageID,
+			StageName:   stageNames[attempt.StageID],
+			FinalScore:  attempt.FinalScore,
+			TotalTimeMs: attempt.TotalTimeMs,
+			TotalErrors: attempt.TotalErrors,
+			CompletedAt: attempt.CompletedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStageBests - best attempt user di setiap stage yang pernah dimainkan
+func (h *PlayerHandler) GetStageBests(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	bests, err := h.playerService.GetStageBests(c.Request.Context(), user.ID)
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	stageNames := make(map[string]string)
+	for _, best := range bests {
+		stageNames[best.StageID] = best.StageName
+	}
+	if !localizeStageNames(c, h.translationService, stageNames) {
+		return
+	}
+
+	response := []dto.StageBestResponse{}
+	for _, best := range bests {
+		response = append(response, dto.StageBestResponse{
+			StageID:   best.StageID,
+			StageName: stageNames[best.StageID],
+			Best: dto.BestAttempt{
+				ScoreID:     best.Best.ID,
+				FinalScore:  best.Best.FinalScore,
+				TotalTimeMs: best.Best.TotalTimeMs,
+				TotalErrors: best.Best.TotalErrors,
+				CompletedAt: best.Best.CompletedAt.Format(time.RFC3339),
+			},
+			AttemptCount: best.AttemptCount,
+			LastPlayedAt: best.LastPlayedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStats - statistik perkembangan user: bucket harian & mingguan, rolling
+// average dan trend (slope per hari) selama `days` hari terakhir
+func (h *PlayerHandler) GetStats(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	days, err := parseLimitQuery(c, "days", services.DefaultStatsDays, services.MaxStatsDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	stats, err := h.playerService.GetStats(c.Request.Context(), user.ID, days)
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PlayerStatsResponse{
+		Since:         stats.Since.Format("2006-01-02"),
+		Days:          days,
+		Attempts:      stats.Attempts,
+		WpmTrend:      round2(stats.WpmTrend),
+		AccuracyTrend: round2(stats.AccuracyTrend),
+		Daily:         toStatsBuckets(stats.Daily),
+		Weekly:        toStatsBuckets(stats.Weekly),
+	})
+}
+
+// GetHeatmap - error rate dan latency per tombol (layout QWERTY), bigram
+// yang paling sering salah diketik dan karakter yang paling sering tertukar
+func (h *PlayerHandler) GetHeatmap(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	heatmap, err := h.playerService.GetHeatmap(c.Request.Context(), user.ID)
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	response := dto.HeatmapResponse{
+		Layout:        heatmap.Layout,
+		Keys:          []dto.KeyHeatmapEntry{},
+		Bigrams:       []dto.BigramHeatmapEntry{},
+		Substitutions: []dto.SubstitutionHeatmapEntry{},
+	}
+	for _, key := range heatmap.Keys {
+		response.Keys = append(response.Keys, dto.KeyHeatmapEntry{
+			Key:                 key.Stat.Key,
+			Row:                 key.Position.Row,
+			Column:              key.Position.Column,
+			Presses:             key.Stat.Presses,
+			Errors:              key.Stat.Errors,
+			ErrorRate:           round2(key.Stat.ErrorRate()),
+			AvgLatencyMs:        round2(key.Stat.AvgLatencyMs()),
+			AvgMistakeLatencyMs: round2(key.Stat.AvgMistakeLatencyMs()),
+		})
+	}
+	for _, bigram := range heatmap.Bigrams {
+		response.Bigrams = append(response.Bigrams, dto.BigramHeatmapEntry{
+			Bigram:              bigram.Key,
+			Presses:             bigram.Presses,
+			Errors:              bigram.Errors,
+			ErrorRate:           round2(bigram.ErrorRate()),
+			AvgLatencyMs:        round2(bigram.AvgLatencyMs()),
+			AvgMistakeLatencyMs: round2(bigram.AvgMistakeLatencyMs()),
+		})
+	}
+	for _, substitution := range heatmap.Substitutions {
+		response.Substitutions = append(response.Substitutions, dto.SubstitutionHeatmapEntry{
+			Expected: substitution.Expected,
+			Typed:    substitution.Typed,
+			Count:    substitution.Count,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProfile - profil publik pemain berdasarkan username
+func (h *PlayerHandler) GetProfile(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	profile, err := h.playerService.GetPublicProfile(c.Request.Context(), user.ID, c.Param("username"))
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	stageNames := make(map[string]string)
+	for _, attempt := range profile.RecentAttempts {
+		stageNames[attempt.StageID] = attempt.StageName
+	}
+	for _, placement := range profile.TopPlacements {
+		stageNames[placement.StageID] = placement.StageName
+	}
+	if !localizeStageNames(c, h.translationService, stageNames) {
+		return
+	}
+
+	response := dto.PlayerProfileResponse{
+		Username:        profile.User.Username,
+		DisplayName:     profile.User.Name(),
+		JoinedAt:        profile.User.CreatedAt.Format(time.RFC3339),
+		StagesCompleted: profile.Summary.StagesCompleted,
+		TotalAttempts:   profile.Summary.TotalAttempts,
+		BestWpm:         profile.Summary.BestWpm,
+		Progress:        toPlayerProgressResponse(profile.Progress),
+		RecentActivity:  []dto.PlayerActivityEntry{},
+		TopPlacements:   []dto.PlayerPlacementEntry{},
+	}
+	if profile.Summary.LastPlayedAt != nil {
+		response.LastPlayedAt = profile.Summary.LastPlayedAt.Format(time.RFC3339)
+	}
+	for _, attempt := range profile.RecentAttempts {
+		response.RecentActivity = append(response.RecentActivity, dto.PlayerActivityEntry{
+			StageID:     attempt.StageID,
+			StageName:   stageNames[attempt.StageID],
+			FinalScore:  attempt.FinalScore,
+			CompletedAt: attempt.CompletedAt.Format(time.RFC3339),
+		})
+	}
+	for _, placement := range profile.TopPlacements {
+		response.TopPlacements = append(response.TopPlacements, dto.PlayerPlacementEntry{
+			StageID:    placement.StageID,
+			StageName:  stageNames[placement.StageID],
+			Rank:       placement.Rank,
+			FinalScore: placement.FinalScore,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PlayerHandler) GetSettings(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	settings, err := h.playerService.GetSettings(c.Request.Context(), user.ID)
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlayerSettingsResponse(settings))
+}
+
+// UpdateSettings - ubah display name dan pengaturan privasi (field yang tidak
+// dikirim tidak diubah)
+func (h *PlayerHandler) UpdateSettings(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.PlayerSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings, err := h.playerService.UpdateSettings(c.Request.Context(), user.ID, services.PlayerSettingsUpdate{
+		DisplayName:          req.DisplayName,
+		ProfileVisibility:    req.ProfileVisibility,
+		LeaderboardAnonymous: req.LeaderboardAnonymous,
+		Timezone:             req.Timezone,
+	})
+	if err != nil {
+		writePlayerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlayerSettingsResponse(settings))
+}
+
+func toPlayerSettingsResponse(user *models.User) dto.PlayerSettingsResponse {
+	return dto.PlayerSettingsResponse{
+		DisplayName:          user.DisplayName,
+		ProfileVisibility:    user.ProfileVisibility,
+		LeaderboardAnonymous: user.LeaderboardAnonymous,
+		Timezone:             user.Timezone,
+	}
+}
+
+func toPlayerProgressResponse(progress *services.PlayerProgress) dto.PlayerProgressResponse {
+	return dto.PlayerProgressResponse{
+		XP:            progress.XP,
+		Level:         progress.Level.Level,
+		LevelStartXP:  progress.Level.LevelStartXP,
+		NextLevelXP:   progress.Level.NextLevelXP,
+		CurrentStreak: progress.CurrentStreak,
+		LongestStreak: progress.LongestStreak,
+	}
+}
+
+func toStatsBuckets(buckets []*models.StatsBucket) []dto.StatsBucketResponse {
+	response := []dto.StatsBucketResponse{}
+	for _, bucket := range buckets {
+		response = append(response, dto.StatsBucketResponse{
+			Start:              bucket.Start.Format("2006-01-02"),
+			Attempts:           bucket.Attempts,
+			AvgWpm:             round2(bucket.AvgWpm),
+			BestWpm:            round2(bucket.BestWpm),
+			AvgAccuracy:        round2(bucket.AvgAccuracy),
+			BestAccuracy:       round2(bucket.BestAccuracy),
+			ErrorRate:          round2(bucket.ErrorRate),
+			TimePlayedMs:       bucket.TimePlayedMs,
+			RollingAvgWpm:      round2(bucket.RollingAvgWpm),
+			RollingAvgAccuracy: round2(bucket.RollingAvgAccuracy),
+		})
+	}
+	return response
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func writePlayerError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrPlayerNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "player not found"})
+	case services.ErrInvalidVisibility, services.ErrInvalidDisplayName, services.ErrInvalidTimezone:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidDateRange:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid date range, use YYYY-MM-DD or RFC3339 with from before to"})
+	case services.ErrInvalidCursor, services.ErrInvalidLimit:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeasonHandler struct {
+	seasonService      *services.SeasonService
+	translationService *services.TranslationService
+}
+
+func NewSeasonHandler(seasonService *services.SeasonService, translationService *services.TranslationService) *SeasonHandler {
+	return &SeasonHandler{seasonService: seasonService, translationService: translationService}
+}
+
+func (h *SeasonHandler) GetSeasons(c *gin.Context) {
+	seasons, err := h.seasonService.GetAllSeasons(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := []dto.SeasonResponse{}
+	for _, season := range seasons {
+		response = append(response, toSeasonResponse(season))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStandings - final standings season yang sudah ditutup (default podium
+// top 3 per stage, bisa difilter dengan stage_id)
+func (h *SeasonHandler) GetStandings(c *gin.Context) {
+	limit, err := parseLimitQuery(c, "limit", services.DefaultPodiumSize, services.MaxLeaderboardLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	season, standings, err := h.seasonService.GetStandings(c.Request.Context(), c.Param("id"), c.Query("stage_id"), limit)
+	if err != nil {
+		writeSeasonError(c, err)
+		return
+	}
+
+	stageNames := make(map[string]string)
+	for _, standing := range standings {
+		stageNames[standing.StageID] = standing.StageName
+	}
+	if !localizeStageNames(c, h.translationService, stageNames) {
+		return
+	}
+
+	response := dto.SeasonStandingsResponse{
+		Season: toSeasonResponse(season),
+		Stages: []dto.SeasonStageStandings{},
+	}
+	viewer := middleware.GetUserFromContext(c)
+	for _, standing := range standings {
+		last := len(response.Stages) - 1
+		if last < 0 || response.Stages[last].StageID != standing.StageID {
+			response.Stages = append(response.Stages, dto.SeasonStageStandings{
+				StageID:   standing.StageID,
+				StageName: stageNames[standing.StageID],
+			})
+			last++
+		}
+		response.Stages[last].Standings = append(response.Stages[last].Standings, dto.SeasonStandingEntry{
+			Rank:        standing.Rank,
+			Username:    leaderboardName(standing.Username, standing.Anonymous, isViewer(viewer, standing.UserID)),
+			FinalScore:  standing.FinalScore,
+			TotalTimeMs: standing.TotalTimeMs,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Admin: buat season baru
+func (h *SeasonHandler) CreateSeason(c *gin.Context) {
+	var req dto.CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	season, err := h.seasonService.CreateSeason(c.Request.Context(), req.Name, req.EndsAt)
+	if err != nil {
+		writeSeasonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSeasonResponse(season))
+}
+
+// Admin: tutup season dan bekukan final standings
+func (h *SeasonHandler) CloseSeason(c *gin.Context) {
+	season, err := h.seasonService.CloseSeason(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSeasonError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toSeasonResponse(season))
+}
+
+func toSeasonResponse(season *models.Season) dto.SeasonResponse {
+	response := dto.SeasonResponse{
+		ID:       season.ID,
+		Name:     season.Name,
+		Status:   season.Status,
+		StartsAt: season.StartsAt.Format(time.RFC3339),
+	}
+	if season.EndsAt != nil {
+		response.EndsAt = season.EndsAt.Format(time.RFC3339)
+	}
+	if season.ClosedAt != nil {
+		response.ClosedAt = season.ClosedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+func writeSeasonError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrSeasonNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "season not found"})
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrActiveSeasonExists, services.ErrSeasonAlreadyClosed:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidSeasonEnd:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidLimit:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid limit"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
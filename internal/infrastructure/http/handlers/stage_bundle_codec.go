@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format file bundle yang didukung import/export
+const (
+	bundleFormatJSON = "json"
+	bundleFormatYAML = "yaml"
+	bundleFormatCSV  = "csv"
+)
+
+// bundleCSVHeader adalah kolom CSV: satu baris per phrase, urut sesuai
+// sequence. Kolom stage diambil dari baris pertama slug tersebut; baris
+// dengan phrase_text kosong berarti stage tanpa phrase.
+var bundleCSVHeader = []string{"stage_slug", "stage_name", "theme", "difficulty", "is_active", "phrase_text", "multiplier"}
+
+var errUnsupportedBundleFormat = errors.New("format must be json, yaml or csv")
+
+// bundleFormat menentukan format dari query ?format= lalu Content-Type;
+// default JSON
+func bundleFormat(format, contentType string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		switch mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			format = bundleFormatYAML
+		case "text/csv":
+			format = bundleFormatCSV
+		default:
+			format = bundleFormatJSON
+		}
+	}
+	switch format {
+	case bundleFormatJSON, bundleFormatYAML, bundleFormatCSV:
+		return format, nil
+	case "yml":
+		return bundleFormatYAML, nil
+	}
+	return "", errUnsupportedBundleFormat
+}
+
+func bundleContentType(format string) string {
+	switch format {
+	case bundleFormatYAML:
+		return "application/yaml; charset=utf-8"
+	case bundleFormatCSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+func decodeBundle(format string, body []byte) (*dto.StageBundleDocument, error) {
+	doc := &dto.StageBundleDocument{}
+	switch format {
+	case bundleFormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(doc); err != nil {
+			return nil, fmt.Errorf("invalid yaml bundle: %w", err)
+		}
+	case bundleFormatCSV:
+		return decodeBundleCSV(body)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(doc); err != nil {
+			return nil, fmt.Errorf("invalid json bundle: %w", err)
+		}
+	}
+	return doc, nil
+}
+
+func encodeBundle(format string, doc *dto.StageBundleDocument) ([]byte, error) {
+	switch format {
+	case bundleFormatYAML:
+		return yaml.Marshal(doc)
+	case bundleFormatCSV:
+		return encodeBundleCSV(doc)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// decodeBundleCSV membaca CSV dengan header bundleCSVHeader (urutan kolom
+// bebas). CSV tidak membawa metadata theme dan selalu versi terbaru. Phrase
+// multi-baris ditulis sebagai field ber-quote yang berisi baris baru.
+func decodeBundleCSV(body []byte) (*dto.StageBundleDocument, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("invalid csv bundle: missing header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv bundle: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range bundleCSVHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("invalid csv bundle: missing column %q", name)
+		}
+	}
+
+	doc := &dto.StageBundleDocument{Version: models.StageBundleVersion}
+	stageIndex := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv bundle: %w", err)
+		}
+		// Nomor baris awal record; satu record bisa lebih dari satu baris
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		slug := strings.TrimSpace(field("stage_slug"))
+		index, seen := stageIndex[slug]
+		if !seen {
+			stage := dto.BundleStageDocument{
+				Slug:       slug,
+				Name:       field("stage_name"),
+				Theme:      field("theme"),
+				Difficulty: strings.TrimSpace(field("difficulty")),
+				Phrases:    []dto.BundlePhraseDocument{},
+			}
+			if value := strings.TrimSpace(field("is_active")); value != "" {
+				isActive, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid csv bundle: line %d: is_active must be true or false", line)
+				}
+				stage.IsActive = &isActive
+			}
+			index = len(doc.Stages)
+			stageIndex[slug] = index
+			doc.Stages = append(doc.Stages, stage)
+		}
+
+		text := field("phrase_text")
+		if text == "" {
+			continue
+		}
+		phrase := dto.BundlePhraseDocument{Text: text}
+		if value := strings.TrimSpace(field("multiplier")); value != "" {
+			multiplier, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid csv bundle: line %d: multiplier must be a number", line)
+			}
+			phrase.Multiplier = &multiplier
+		}
+		doc.Stages[index].Phrases = append(doc.Stages[index].Phrases, phrase)
+	}
+	return doc, nil
+}
+
+func encodeBundleCSV(doc *dto.StageBundleDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(bundleCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for _, stage := range doc.Stages {
+		isActive := ""
+		if stage.IsActive != nil {
+			isActive = strconv.FormatBool(*stage.IsActive)
+		}
+		prefix := []string{stage.Slug, stage.Name, stage.Theme, stage.Difficulty, isActive}
+
+		if len(stage.Phrases) == 0 {
+			if err := writer.Write(append(prefix, "", "")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, phrase := range stage.Phrases {
+			multiplier := ""
+			if phrase.Multiplier != nil {
+				multiplier = strconv.FormatFloat(*phrase.Multiplier, 'f', -1, 64)
+			}
+			record := append(append([]string{}, prefix...), phrase.Text, multiplier)
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func toStageBundle(doc *dto.StageBundleDocument) *models.StageBundle {
+	bundle := &models.StageBundle{Version: doc.Version}
+	for _, theme := range doc.Themes {
+		bundle.Themes = append(bundle.Themes, &models.BundleTheme{
+			Name:        theme.Name,
+			Description: theme.Description,
+			Color:       theme.Color,
+			IconKey:     theme.IconKey,
+			SortOrder:   theme.SortOrder,
+		})
+	}
+	for _, stage := range doc.Stages {
+		bundleStage := &models.BundleStage{
+			Slug:       stage.Slug,
+			Name:       stage.Name,
+			Theme:      stage.Theme,
+			Difficulty: stage.Difficulty,
+			IsActive:   stage.IsActive,
+
+			PhraseCharset:    stage.PhraseCharset,
+			PhrasePoolSize:   stage.PhrasePoolSize,
+			WhitespacePolicy: stage.WhitespacePolicy,
+			PhraseLanguage:   stage.PhraseLanguage,
+		}
+		for _, phrase := range stage.Phrases {
+			bundleStage.Phrases = append(bundleStage.Phrases, &models.BundlePhrase{
+				Text:       phrase.Text,
+				Multiplier: phrase.Multiplier,
+			})
+		}
+		bundle.Stages = append(bundle.Stages, bundleStage)
+	}
+	return bundle
+}
+
+func toStageBundleDocument(bundle *models.StageBundle) *dto.StageBundleDocument {
+	doc := &dto.StageBundleDocument{
+		Version: bundle.Version,
+		Stages:  []dto.BundleStageDocument{},
+	}
+	for _, theme := range bundle.Themes {
+		doc.Themes = append(doc.Themes, dto.BundleThemeDocument{
+			Name:        theme.Name,
+			Description: theme.Description,
+			Color:       theme.Color,
+			IconKey:     theme.IconKey,
+			SortOrder:   theme.SortOrder,
+		})
+	}
+	for _, stage := range bundle.Stages {
+		stageDoc := dto.BundleStageDocument{
+			Slug:       stage.Slug,
+			Name:       stage.Name,
+			Theme:      stage.Theme,
+			Difficulty: stage.Difficulty,
+			IsActive:   stage.IsActive,
+			Phrases:    []dto.BundlePhraseDocument{},
+
+			PhraseCharset:    stage.PhraseCharset,
+			PhrasePoolSize:   stage.PhrasePoolSize,
+			WhitespacePolicy: stage.WhitespacePolicy,
+			PhraseLanguage:   stage.PhraseLanguage,
+		}
+		for _, phrase := range stage.Phrases {
+			stageDoc.Phrases = append(stageDoc.Phrases, dto.BundlePhraseDocument{
+				Text:       phrase.Text,
+				Multiplier: phrase.Multiplier,
+			})
+		}
+		doc.Stages = append(doc.Stages, stageDoc)
+	}
+	return doc
+}
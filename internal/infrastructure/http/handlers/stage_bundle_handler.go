@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBundleSize membatasi ukuran file import
+const maxBundleSize = 5 << 20
+
+type StageBundleHandler struct {
+	bundleService *services.StageBundleService
+}
+
+func NewStageBundleHandler(bundleService *services.StageBundleService) *StageBundleHandler {
+	return &StageBundleHandler{bundleService: bundleService}
+}
+
+// ExportStages - download bundle stage (?format=json|yaml|csv, ?slug= bisa diulang)
+func (h *StageBundleHandler) ExportStages(c *gin.Context) {
+	format, err := bundleFormat(c.Query("format"), "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	bundle, err := h.bundleService.ExportStages(c.Request.Context(), c.QueryArray("slug"))
+	if err != nil {
+		writeBundleError(c, err)
+		return
+	}
+
+	body, err := encodeBundle(format, toStageBundleDocument(bundle))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="stages.`+format+`"`)
+	c.Data(http.StatusOK, bundleContentType(format), body)
+}
+
+// ImportStages - upsert stage dari bundle dalam satu transaksi. Dengan
+// ?dry_run=true hanya diff yang dikembalikan tanpa menyimpan apa pun.
+func (h *StageBundleHandler) ImportStages(c *gin.Context) {
+	format, err := bundleFormat(c.Query("format"), c.ContentType())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "dry_run must be true or false"})
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBundleSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "bundle exceeds 5 MB"})
+		return
+	}
+
+	doc, err := decodeBundle(format, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report, err := h.bundleService.ImportStages(c.Request.Context(), toStageBundle(doc), dryRun)
+	if err != nil {
+		writeBundleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toImportReportResponse(report))
+}
+
+func toImportReportResponse(report *models.ImportReport) dto.ImportReportResponse {
+	response := dto.ImportReportResponse{
+		DryRun: report.DryRun,
+		Themes: []dto.ThemeChangeResponse{},
+		Stages: []dto.StageChangeResponse{},
+	}
+
+	for _, change := range report.Themes {
+		switch change.Action {
+		case models.ImportActionCreate:
+			response.Summary.ThemesCreated++
+		case models.ImportActionUpdate:
+			response.Summary.ThemesUpdated++
+		}
+		response.Themes = append(response.Themes, dto.ThemeChangeResponse{
+			Name:   change.Name,
+			Action: change.Action,
+			Fields: change.Fields,
+		})
+	}
+
+	for _, change := range report.Stages {
+		switch change.Action {
+		case models.ImportActionCreate:
+			response.Summary.StagesCreated++
+		case models.ImportActionUpdate:
+			response.Summary.StagesUpdated++
+		default:
+			response.Summary.StagesUnchanged++
+		}
+
+		stage := dto.StageChangeResponse{
+			Slug:    change.Slug,
+			StageID: change.StageID,
+			Action:  change.Action,
+			Fields:  change.Fields,
+		}
+		for _, phrase := range change.Phrases {
+			switch phrase.Action {
+			case models.ImportActionAdd:
+				response.Summary.PhrasesAdded++
+			case models.ImportActionUpdate:
+				response.Summary.PhrasesUpdated++
+			case models.ImportActionRemove:
+				response.Summary.PhrasesRemoved++
+			}
+			stage.Phrases = append(stage.Phrases, dto.PhraseChangeResponse{
+				Position:      phrase.Position,
+				Action:        phrase.Action,
+				Text:          phrase.Text,
+				OldText:       phrase.OldText,
+				Multiplier:    phrase.Multiplier,
+				OldMultiplier: phrase.OldMultiplier,
+				Moved:         phrase.Moved,
+			})
+		}
+		response.Stages = append(response.Stages, stage)
+	}
+	return response
+}
+
+func writeBundleError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(c, validationErr)
+		return
+	}
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
+
+func writeValidationError(c *gin.Context, err *services.ValidationError) {
+	response := dto.ValidationErrorResponse{Error: "validation failed"}
+	for _, field := range err.Fields {
+		response.Fields = append(response.Fields, dto.FieldErrorResponse{
+			Field:   field.Field,
+			Message: field.Message,
+		})
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"uwika_quick_typer_game/internal/application/services"
+	"uwika_quick_typer_game/internal/domain/models"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+	"uwika_quick_typer_game/internal/infrastructure/http/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService *services.TranslationService
+}
+
+func NewTranslationHandler(translationService *services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{translationService: translationService}
+}
+
+// requestLocales adalah locale yang diminta client urut prioritas: query
+// ?lang= (satu locale) mengalahkan header Accept-Language. Locale yang
+// tidak valid diabaikan sehingga teks asli (locale default) dipakai.
+func requestLocales(c *gin.Context) []string {
+	if lang := c.Query("lang"); lang != "" {
+		if locale := domainservices.NormalizeLocale(lang); locale != "" {
+			return []string{locale}
+		}
+		return nil
+	}
+	return domainservices.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// localizeStageNames mengganti names (stage id → nama asli) dengan nama
+// dalam locale request. Mengembalikan false setelah menulis response error.
+func localizeStageNames(c *gin.Context, translationService *services.TranslationService, names map[string]string) bool {
+	if err := translationService.LocalizeStageNames(c.Request.Context(), names, requestLocales(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	return true
+}
+
+// Stage Translations
+
+func (h *TranslationHandler) GetStageTranslations(c *gin.Context) {
+	translations, err := h.translationService.GetStageTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+
+	response := dto.TranslationListResponse{
+		DefaultLocale: h.translationService.DefaultLocale(),
+		Translations:  []dto.TranslationResponse{},
+	}
+	for _, translation := range translations {
+		response.Translations = append(response.Translations, toStageTranslationResponse(translation))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TranslationHandler) SetStageTranslation(c *gin.Context) {
+	var req dto.StageTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	translation, err := h.translationService.SetStageTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), req.Name)
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toStageTranslationResponse(translation))
+}
+
+func (h *TranslationHandler) DeleteStageTranslation(c *gin.Context) {
+	if err := h.translationService.DeleteStageTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "translation deleted successfully"})
+}
+
+// Theme Translations
+
+func (h *TranslationHandler) GetThemeTranslations(c *gin.Context) {
+	translations, err := h.translationService.GetThemeTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+
+	response := dto.TranslationListResponse{
+		DefaultLocale: h.translationService.DefaultLocale(),
+		Translations:  []dto.TranslationResponse{},
+	}
+	for _, translation := range translations {
+		response.Translations = append(response.Translations, toThemeTranslationResponse(translation))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TranslationHandler) SetThemeTranslation(c *gin.Context) {
+	var req dto.ThemeTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	translation, err := h.translationService.SetThemeTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), req.Name, req.Description)
+	if err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toThemeTranslationResponse(translation))
+}
+
+func (h *TranslationHandler) DeleteThemeTranslation(c *gin.Context) {
+	if err := h.translationService.DeleteThemeTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		writeTranslationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "translation deleted successfully"})
+}
+
+func toStageTranslationResponse(translation *models.StageTranslation) dto.TranslationResponse {
+	return dto.TranslationResponse{
+		Locale:    translation.Locale,
+		Name:      translation.Name,
+		UpdatedAt: translation.UpdatedAt,
+	}
+}
+
+func toThemeTranslationResponse(translation *models.ThemeTranslation) dto.TranslationResponse {
+	return dto.TranslationResponse{
+		Locale:      translation.Locale,
+		Name:        translation.Name,
+		Description: translation.Description,
+		UpdatedAt:   translation.UpdatedAt,
+	}
+}
+
+func writeTranslationError(c *gin.Context, err error) {
+	switch err {
+	case services.ErrStageNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "stage not found"})
+	case services.ErrThemeNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "theme not found"})
+	case services.ErrTranslationNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case services.ErrInvalidLocale, services.ErrDefaultLocaleTranslation:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -2,7 +2,6 @@ package router
 
 import (
 	"uwika_quick_typer_game/internal/application/services"
-	"uwika_quick_typer_game/internal/domain/repositories"
 	"uwika_quick_typer_game/internal/infrastructure/http/handlers"
 	"uwika_quick_typer_game/internal/infrastructure/http/middleware"
 
@@ -12,8 +11,15 @@ import (
 func SetupRouter(
 	authService *services.AuthService,
 	gameService *services.GameService,
+	leaderboardService *services.LeaderboardService,
+	leaderboardStream *services.LeaderboardStream,
+	seasonService *services.SeasonService,
+	playerService *services.PlayerService,
+	achievementService *services.AchievementService,
+	dailyChallengeService *services.DailyChallengeService,
+	stageBundleService *services.StageBundleService,
+	translationService *services.TranslationService,
 	adminService *services.AdminService,
-	userRepo repositories.UserRepository,
 ) *gin.Engine {
 	r := gin.Default()
 
@@ -21,7 +27,7 @@ func SetupRouter(
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -34,7 +40,14 @@ func SetupRouter(
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	gameHandler := handlers.NewGameHandler(gameService, userRepo)
+	gameHandler := handlers.NewGameHandler(gameService, translationService)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, leaderboardStream, gameService)
+	seasonHandler := handlers.NewSeasonHandler(seasonService, translationService)
+	playerHandler := handlers.NewPlayerHandler(playerService, translationService)
+	achievementHandler := handlers.NewAchievementHandler(achievementService)
+	dailyChallengeHandler := handlers.NewDailyChallengeHandler(dailyChallengeService, translationService)
+	stageBundleHandler := handlers.NewStageBundleHandler(stageBundleService)
+	translationHandler := handlers.NewTranslationHandler(translationService)
 	adminHandler := handlers.NewAdminHandler(adminService)
 
 	// Public routes
@@ -48,14 +61,41 @@ func SetupRouter(
 			auth.GET("/profile", middleware.AuthMiddleware(authService), authHandler.Profile)
 		}
 
+		// Live leaderboard untuk layar proyektor: publik dan read-only karena
+		// EventSource tidak bisa mengirim header Authorization
+		api.GET("/leaderboard/stream", leaderboardHandler.StreamLeaderboard)
+
 		// Game endpoints (require authentication)
 		game := api.Group("")
 		game.Use(middleware.AuthMiddleware(authService))
 		{
+			game.GET("/themes", gameHandler.GetThemes)
 			game.GET("/stages", gameHandler.GetStages)
 			game.GET("/stage/:id", gameHandler.GetStageDetail)
+			game.POST("/stage/:id/session", gameHandler.StartSession)
 			game.POST("/score/submit", gameHandler.SubmitScore)
-			game.GET("/leaderboard", gameHandler.GetLeaderboard)
+			game.GET("/leaderboard", leaderboardHandler.GetLeaderboard)
+			game.GET("/leaderboard/aggregate", leaderboardHandler.GetAggregateLeaderboard)
+			game.GET("/seasons", seasonHandler.GetSeasons)
+			game.GET("/seasons/:id/standings", seasonHandler.GetStandings)
+			game.GET("/players/:username", playerHandler.GetProfile)
+			game.GET("/daily", dailyChallengeHandler.GetChallenge)
+			game.POST("/daily/session", dailyChallengeHandler.StartSession)
+			game.POST("/daily/submit", dailyChallengeHandler.SubmitAttempt)
+			game.GET("/daily/leaderboard", dailyChallengeHandler.GetLeaderboard)
+		}
+
+		// Player endpoints (data milik user yang login)
+		me := api.Group("/me")
+		me.Use(middleware.AuthMiddleware(authService))
+		{
+			me.GET("/scores", playerHandler.GetScoreHistory)
+			me.GET("/bests", playerHandler.GetStageBests)
+			me.GET("/stats", playerHandler.GetStats)
+			me.GET("/heatmap", playerHandler.GetHeatmap)
+			me.GET("/achievements", achievementHandler.GetMyAchievements)
+			me.GET("/settings", playerHandler.GetSettings)
+			me.PUT("/settings", playerHandler.UpdateSettings)
 		}
 	}
 
@@ -64,20 +104,50 @@ func SetupRouter(
 	admin.Use(middleware.AuthMiddleware(authService))
 	admin.Use(middleware.AdminMiddleware())
 	{
-		// Theme endpoints (read-only for admin)
+		// Theme management
 		admin.GET("/themes", adminHandler.GetAllThemes)
+		admin.POST("/themes", adminHandler.CreateTheme)
+		admin.PUT("/themes/:id", adminHandler.UpdateTheme)
+		admin.DELETE("/themes/:id", adminHandler.DeleteTheme)
+		admin.GET("/themes/:id/translations", translationHandler.GetThemeTranslations)
+		admin.PUT("/themes/:id/translations/:locale", translationHandler.SetThemeTranslation)
+		admin.DELETE("/themes/:id/translations/:locale", translationHandler.DeleteThemeTranslation)
 
 		// Stage management
 		admin.POST("/stage", adminHandler.CreateStage)
 		admin.PUT("/stage/:id", adminHandler.UpdateStage)
 		admin.DELETE("/stage/:id", adminHandler.DeleteStage)
 		admin.GET("/stages", adminHandler.GetAllStages)
+		admin.GET("/stage/:id/prerequisites", adminHandler.GetStagePrerequisites)
+		admin.PUT("/stage/:id/prerequisites", adminHandler.SetStagePrerequisites)
+		admin.PUT("/stage/:id/phrases/order", adminHandler.ReorderPhrases)
+		admin.POST("/stage/:id/publish", adminHandler.PublishStage)
+		admin.GET("/stage/:id/versions", adminHandler.GetStageVersions)
+		admin.GET("/stage/:id/analysis", adminHandler.AnalyzeStage)
+		admin.POST("/stage/:id/clone", adminHandler.CloneStage)
+		admin.GET("/stage/:id/translations", translationHandler.GetStageTranslations)
+		admin.PUT("/stage/:id/translations/:locale", translationHandler.SetStageTranslation)
+		admin.DELETE("/stage/:id/translations/:locale", translationHandler.DeleteStageTranslation)
+		admin.GET("/stages/export", stageBundleHandler.ExportStages)
+		admin.POST("/stages/import", stageBundleHandler.ImportStages)
 
 		// Phrase management
 		admin.POST("/phrase", adminHandler.CreatePhrase)
 		admin.PUT("/phrase/:id", adminHandler.UpdatePhrase)
 		admin.DELETE("/phrase/:id", adminHandler.DeletePhrase)
 		admin.GET("/phrases", adminHandler.GetPhrasesByStage)
+		admin.POST("/phrases/analyze", adminHandler.AnalyzePhrases)
+
+		// Season management
+		admin.POST("/season", seasonHandler.CreateSeason)
+		admin.POST("/season/:id/close", seasonHandler.CloseSeason)
+		admin.GET("/seasons", seasonHandler.GetSeasons)
+
+		// Achievement management
+		admin.GET("/achievements", achievementHandler.GetAllAchievements)
+		admin.POST("/achievement", achievementHandler.CreateAchievement)
+		admin.PUT("/achievement/:id", achievementHandler.UpdateAchievement)
+		admin.DELETE("/achievement/:id", achievementHandler.DeleteAchievement)
 	}
 
 	// Health check
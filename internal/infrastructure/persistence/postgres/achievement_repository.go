@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type achievementRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementRepository(db *sql.DB) repositories.AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+func (r *achievementRepository) Create(ctx context.Context, achievement *models.Achievement) error {
+	if achievement.ID == "" {
+		achievement.ID = uuid.New().String()
+	}
+	achievement.CreatedAt = time.Now()
+	achievement.UpdatedAt = achievement.CreatedAt
+
+	query := `
+		INSERT INTO achievements (id, code, name, description, rule_type, params, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		achievement.ID, achievement.Code, achievement.Name, achievement.Description, achievement.RuleType,
+		string(achievement.Params), achievement.IsActive, achievement.CreatedAt, achievement.UpdatedAt,
+	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
+	return err
+}
+
+func (r *achievementRepository) FindByID(ctx context.Context, achievementID string) (*models.Achievement, error) {
+	query := `
+		SELECT ` + achievementColumns + `
+		FROM achievements WHERE id = $1
+	`
+	return scanAchievement(conn(ctx, r.db).QueryRowContext(ctx, query, achievementID))
+}
+
+func (r *achievementRepository) FindAll(ctx context.Context) ([]*models.Achievement, error) {
+	query := `
+		SELECT ` + achievementColumns + `
+		FROM achievements
+		ORDER BY created_at ASC, code ASC
+	`
+	return r.findMany(ctx, query)
+}
+
+func (r *achievementRepository) FindActive(ctx context.Context) ([]*models.Achievement, error) {
+	query := `
+		SELECT ` + achievementColumns + `
+		FROM achievements
+		WHERE is_active = true
+		ORDER BY created_at ASC, code ASC
+	`
+	return r.findMany(ctx, query)
+}
+
+func (r *achievementRepository) Update(ctx context.Context, achievement *models.Achievement) error {
+	achievement.UpdatedAt = time.Now()
+	query := `
+		UPDATE achievements
+		SET code = $2, name = $3, description = $4, rule_type = $5, params = $6, is_active = $7, updated_at = $8
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		achievement.ID, achievement.Code, achievement.Name, achievement.Description, achievement.RuleType,
+		string(achievement.Params), achievement.IsActive, achievement.UpdatedAt,
+	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
+	return err
+}
+
+func (r *achievementRepository) Delete(ctx context.Context, achievementID string) error {
+	query := `DELETE FROM achievements WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, achievementID)
+	return err
+}
+
+func (r *achievementRepository) FindByUser(ctx context.Context, userID string) ([]*models.UserAchievement, error) {
+	query := `
+		SELECT achievement_id, COALESCE(score_id, 0), awarded_at
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY awarded_at ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var awarded []*models.UserAchievement
+	for rows.Next() {
+		ua := &models.UserAchievement{}
+		if err := rows.Scan(&ua.AchievementID, &ua.ScoreID, &ua.AwardedAt); err != nil {
+			return nil, err
+		}
+		ua.AwardedAt = localWallClock(ua.AwardedAt)
+		awarded = append(awarded, ua)
+	}
+	return awarded, rows.Err()
+}
+
+func (r *achievementRepository) Award(ctx context.Context, userID, achievementID string, scoreID int64) (bool, error) {
+	query := `
+		INSERT INTO user_achievements (user_id, achievement_id, score_id, awarded_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, achievement_id) DO NOTHING
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID, achievementID, scoreID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *achievementRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*models.Achievement, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []*models.Achievement
+	for rows.Next() {
+		achievement, err := scanAchievement(rows)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, rows.Err()
+}
+
+const achievementColumns = `id, code, name, COALESCE(description, ''), rule_type, params, is_active, created_at, updated_at`
+
+func scanAchievement(row rowScanner) (*models.Achievement, error) {
+	achievement := &models.Achievement{}
+	var params []byte
+	err := row.Scan(
+		&achievement.ID, &achievement.Code, &achievement.Name, &achievement.Description, &achievement.RuleType,
+		&params, &achievement.IsActive, &achievement.CreatedAt, &achievement.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	achievement.Params = params
+	return achievement, nil
+}
@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type dailyChallengeRepository struct {
+	db *sql.DB
+}
+
+func NewDailyChallengeRepository(db *sql.DB) repositories.DailyChallengeRepository {
+	return &dailyChallengeRepository{db: db}
+}
+
+func (r *dailyChallengeRepository) FindByDate(ctx context.Context, date time.Time) (*models.DailyChallenge, error) {
+	query := `
+		SELECT id, challenge_date, seed, created_at
+		FROM daily_challenges WHERE challenge_date = $1::date
+	`
+	challenge := &models.DailyChallenge{}
+	var challengeDate time.Time
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, date.Format("2006-01-02")).Scan(
+		&challenge.ID, &challengeDate, &challenge.Seed, &challenge.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	challenge.Date = time.Date(challengeDate.Year(), challengeDate.Month(), challengeDate.Day(), 0, 0, 0, 0, time.UTC)
+	challenge.CreatedAt = localWallClock(challenge.CreatedAt)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT COALESCE(phrase_id::text, ''), COALESCE(stage_id::text, ''), text, sequence_number, base_multiplier
+		FROM daily_challenge_phrases
+		WHERE challenge_id = $1
+		ORDER BY sequence_number ASC
+	`, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		phrase := &models.Phrase{}
+		if err := rows.Scan(&phrase.ID, &phrase.StageID, &phrase.Text, &phrase.SequenceNumber, &phrase.BaseMultiplier); err != nil {
+			return nil, err
+		}
+		challenge.Phrases = append(challenge.Phrases, phrase)
+	}
+	return challenge, rows.Err()
+}
+
+func (r *dailyChallengeRepository) Create(ctx context.Context, challenge *models.DailyChallenge) error {
+	if challenge.ID == "" {
+		challenge.ID = uuid.New().String()
+	}
+	challenge.CreatedAt = time.Now()
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO daily_challenges (id, challenge_date, seed, created_at)
+			VALUES ($1, $2::date, $3, $4)
+			ON CONFLICT (challenge_date) DO NOTHING
+		`, challenge.ID, challenge.Date.Format("2006-01-02"), challenge.Seed, challenge.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		for _, phrase := range challenge.Phrases {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO daily_challenge_phrases (challenge_id, sequence_number, phrase_id, stage_id, text, base_multiplier)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, challenge.ID, phrase.SequenceNumber, nullString(phrase.ID), nullString(phrase.StageID), phrase.Text, phrase.BaseMultiplier)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *dailyChallengeRepository) CreateAttempt(ctx context.Context, attempt *models.DailyChallengeAttempt, ranked bool) error {
+	attempt.CompletedAt = time.Now()
+
+	query := `
+		INSERT INTO daily_challenge_scores
+			(challenge_id, user_id, final_score, total_time_ms, total_errors, total_chars, wpm, accuracy, ranked, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	args := []interface{}{
+		attempt.ChallengeID, attempt.UserID, attempt.FinalScore, attempt.TotalTimeMs, attempt.TotalErrors,
+		attempt.TotalChars, attempt.Wpm, attempt.Accuracy,
+	}
+
+	if ranked {
+		// Unique index parsial menjamin hanya satu attempt ranked per user,
+		// termasuk saat dua submit datang bersamaan
+		err := conn(ctx, r.db).QueryRowContext(ctx, query+`
+			ON CONFLICT (challenge_id, user_id) WHERE ranked DO NOTHING
+			RETURNING id
+		`, append(args, true, attempt.CompletedAt)...).Scan(&attempt.ID)
+		if err == nil {
+			attempt.Ranked = true
+			return nil
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	attempt.Ranked = false
+	return conn(ctx, r.db).QueryRowContext(ctx, query+` RETURNING id`, append(args, false, attempt.CompletedAt)...).Scan(&attempt.ID)
+}
+
+func (r *dailyChallengeRepository) HasRankedAttempt(ctx context.Context, challengeID, userID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM daily_challenge_scores
+			WHERE challenge_id = $1 AND user_id = $2 AND ranked
+		)
+	`
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, challengeID, userID).Scan(&exists)
+	return exists, err
+}
+
+// rankedDailyCTE mengurutkan attempt ranked satu challenge dengan urutan
+// yang sama seperti leaderboard stage
+const rankedDailyCTE = `
+	WITH ranked AS (
+		SELECT
+			id AS score_id, user_id, final_score, total_time_ms, total_errors, completed_at,
+			ROW_NUMBER() OVER (ORDER BY final_score DESC, total_time_ms ASC, user_id ASC) AS rank
+		FROM daily_challenge_scores
+		WHERE challenge_id = $1 AND ranked
+	)
+`
+
+func (r *dailyChallengeRepository) FindLeaderboard(ctx context.Context, challengeID string, limit int) ([]*models.LeaderboardEntry, error) {
+	query := rankedDailyCTE + `
+		SELECT r.rank, r.score_id, r.user_id, u.username, u.leaderboard_anonymous, r.final_score, r.total_time_ms, r.total_errors, r.completed_at
+		FROM ranked r
+		JOIN users u ON u.id = r.user_id
+		ORDER BY r.rank
+		LIMIT $2
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, challengeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLeaderboardEntries(rows)
+}
+
+func (r *dailyChallengeRepository) FindUserRank(ctx context.Context, challengeID, userID string) (*models.LeaderboardEntry, error) {
+	query := rankedDailyCTE + `
+		SELECT r.rank, r.score_id, r.user_id, u.username, u.leaderboard_anonymous, r.final_score, r.total_time_ms, r.total_errors, r.completed_at
+		FROM ranked r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.user_id = $2
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := scanLeaderboardEntries(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+const dailySessionSelect = `
+	SELECT s.id, s.challenge_id, c.challenge_date, s.user_id, s.ranked, s.created_at, s.expires_at, s.submitted_at
+	FROM daily_challenge_sessions s
+	JOIN daily_challenges c ON c.id = s.challenge_id
+`
+
+func (r *dailyChallengeRepository) StartSession(ctx context.Context, session *models.DailyChallengeSession, now time.Time) (*models.DailyChallengeSession, error) {
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+	session.CreatedAt = now
+
+	var started *models.DailyChallengeSession
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Sesi terbuka yang kedaluwarsa tidak bisa disubmit lagi; hapus
+		// supaya tidak menghalangi sesi baru (lihat idx_daily_challenge_sessions_open)
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM daily_challenge_sessions
+			WHERE user_id = $1 AND challenge_id = $2 AND submitted_at IS NULL AND expires_at <= $3
+		`, session.UserID, session.ChallengeID, now.Local())
+		if err != nil {
+			return err
+		}
+
+		// Request paralel: yang kalah menunggu lalu memakai sesi pemenang
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO daily_challenge_sessions (id, challenge_id, user_id, ranked, created_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, challenge_id) WHERE submitted_at IS NULL DO NOTHING
+		`, session.ID, session.ChallengeID, session.UserID, session.Ranked, session.CreatedAt.Local(), session.ExpiresAt.Local())
+		if err != nil {
+			return err
+		}
+
+		started, err = scanDailySession(tx.QueryRowContext(ctx, dailySessionSelect+`
+			WHERE s.user_id = $1 AND s.challenge_id = $2 AND s.submitted_at IS NULL
+		`, session.UserID, session.ChallengeID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+func (r *dailyChallengeRepository) FindSession(ctx context.Context, sessionID string) (*models.DailyChallengeSession, error) {
+	session, err := scanDailySession(conn(ctx, r.db).QueryRowContext(ctx, dailySessionSelect+` WHERE s.id = $1`, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+func scanDailySession(row *sql.Row) (*models.DailyChallengeSession, error) {
+	session := &models.DailyChallengeSession{}
+	var challengeDate time.Time
+	var submittedAt sql.NullTime
+	err := row.Scan(
+		&session.ID, &session.ChallengeID, &challengeDate, &session.UserID, &session.Ranked,
+		&session.CreatedAt, &session.ExpiresAt, &submittedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ChallengeDate = time.Date(challengeDate.Year(), challengeDate.Month(), challengeDate.Day(), 0, 0, 0, 0, time.UTC)
+	session.CreatedAt = localWallClock(session.CreatedAt)
+	session.ExpiresAt = localWallClock(session.ExpiresAt)
+	if submittedAt.Valid {
+		t := localWallClock(submittedAt.Time)
+		session.SubmittedAt = &t
+	}
+	return session, nil
+}
+
+func (r *dailyChallengeRepository) MarkSessionSubmitted(ctx context.Context, sessionID string, submittedAt time.Time) (bool, error) {
+	query := `
+		UPDATE daily_challenge_sessions SET submitted_at = $2
+		WHERE id = $1 AND submitted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, sessionID, submittedAt.Local())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,16 @@
+package postgres
+
+import (
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes, lihat https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+func isPQError(err error, code string) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && string(pqErr.Code) == code
+}
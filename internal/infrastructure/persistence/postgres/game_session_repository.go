@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type gameSessionRepository struct {
+	db *sql.DB
+}
+
+func NewGameSessionRepository(db *sql.DB) repositories.GameSessionRepository {
+	return &gameSessionRepository{db: db}
+}
+
+const gameSessionColumns = `id, user_id, stage_id, stage_version_id, seed, phrase_ids, created_at, expires_at, submitted_at`
+
+func (r *gameSessionRepository) Start(ctx context.Context, session *models.GameSession, now time.Time) (*models.GameSession, error) {
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+	session.CreatedAt = now
+
+	var started *models.GameSession
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Sesi terbuka yang kedaluwarsa tidak bisa disubmit lagi; hapus
+		// supaya tidak menghalangi sesi baru (lihat idx_game_sessions_open)
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM game_sessions
+			WHERE user_id = $1 AND stage_id = $2 AND submitted_at IS NULL AND expires_at <= $3
+		`, session.UserID, session.StageID, now.Local())
+		if err != nil {
+			return err
+		}
+
+		// Request paralel: yang kalah menunggu lalu memakai sesi pemenang
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO game_sessions (id, user_id, stage_id, stage_version_id, seed, phrase_ids, created_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (user_id, stage_id, stage_version_id) WHERE submitted_at IS NULL DO NOTHING
+		`,
+			session.ID, session.UserID, session.StageID, session.StageVersionID, session.Seed,
+			pq.Array(session.PhraseIDs), session.CreatedAt.Local(), session.ExpiresAt.Local(),
+		)
+		if err != nil {
+			return err
+		}
+
+		started, err = scanGameSession(tx.QueryRowContext(ctx, `
+			SELECT `+gameSessionColumns+`
+			FROM game_sessions
+			WHERE user_id = $1 AND stage_id = $2 AND stage_version_id = $3 AND submitted_at IS NULL
+		`, session.UserID, session.StageID, session.StageVersionID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+func (r *gameSessionRepository) FindByID(ctx context.Context, sessionID string) (*models.GameSession, error) {
+	query := `SELECT ` + gameSessionColumns + ` FROM game_sessions WHERE id = $1`
+	session, err := scanGameSession(conn(ctx, r.db).QueryRowContext(ctx, query, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+func scanGameSession(row *sql.Row) (*models.GameSession, error) {
+	session := &models.GameSession{}
+	var submittedAt sql.NullTime
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.StageID, &session.StageVersionID, &session.Seed,
+		pq.Array(&session.PhraseIDs), &session.CreatedAt, &session.ExpiresAt, &submittedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.CreatedAt = localWallClock(session.CreatedAt)
+	session.ExpiresAt = localWallClock(session.ExpiresAt)
+	if submittedAt.Valid {
+		t := localWallClock(submittedAt.Time)
+		session.SubmittedAt = &t
+	}
+	return session, nil
+}
+
+func (r *gameSessionRepository) MarkSubmitted(ctx context.Context, sessionID string, submittedAt time.Time) (bool, error) {
+	query := `
+		UPDATE game_sessions SET submitted_at = $2
+		WHERE id = $1 AND submitted_at IS NULL
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, sessionID, submittedAt.Local())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
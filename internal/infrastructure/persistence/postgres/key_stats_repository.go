@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/lib/pq"
+)
+
+type keyStatsRepository struct {
+	db *sql.DB
+}
+
+func NewKeyStatsRepository(db *sql.DB) repositories.KeyStatsRepository {
+	return &keyStatsRepository{db: db}
+}
+
+func (r *keyStatsRepository) Record(ctx context.Context, userID string, keys, bigrams []*models.KeyStat, substitutions []*models.KeySubstitution) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := upsertKeyStats(ctx, tx, "user_key_stats", "key_char", userID, keys); err != nil {
+			return err
+		}
+		if err := upsertKeyStats(ctx, tx, "user_bigram_stats", "bigram", userID, bigrams); err != nil {
+			return err
+		}
+		return upsertSubstitutions(ctx, tx, userID, substitutions)
+	})
+}
+
+func upsertSubstitutions(ctx context.Context, tx *sql.Tx, userID string, substitutions []*models.KeySubstitution) error {
+	if len(substitutions) == 0 {
+		return nil
+	}
+
+	expected := make([]string, len(substitutions))
+	typed := make([]string, len(substitutions))
+	counts := make([]int64, len(substitutions))
+	for i, substitution := range substitutions {
+		expected[i] = substitution.Expected
+		typed[i] = substitution.Typed
+		counts[i] = int64(substitution.Count)
+	}
+
+	query := `
+		INSERT INTO user_key_substitutions (user_id, expected, typed, count, updated_at)
+		SELECT $1, d.expected, d.typed, d.count, $5
+		FROM unnest($2::text[], $3::text[], $4::int[]) AS d(expected, typed, count)
+		ON CONFLICT (user_id, expected, typed) DO UPDATE
+		SET count = user_key_substitutions.count + EXCLUDED.count,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := tx.ExecContext(ctx, query, userID, pq.Array(expected), pq.Array(typed), pq.Array(counts), time.Now())
+	return err
+}
+
+// upsertKeyStats menambahkan delta statistik dalam satu statement lewat unnest
+func upsertKeyStats(ctx context.Context, tx *sql.Tx, table, column, userID string, stats []*models.KeyStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(stats))
+	presses := make([]int64, len(stats))
+	errorCounts := make([]int64, len(stats))
+	latencies := make([]int64, len(stats))
+	samples := make([]int64, len(stats))
+	mistakeLatencies := make([]int64, len(stats))
+	mistakeSamples := make([]int64, len(stats))
+	for i, stat := range stats {
+		keys[i] = stat.Key
+		presses[i] = int64(stat.Presses)
+		errorCounts[i] = int64(stat.Errors)
+		latencies[i] = stat.TotalLatencyMs
+		samples[i] = int64(stat.LatencySamples)
+		mistakeLatencies[i] = stat.TotalMistakeLatencyMs
+		mistakeSamples[i] = int64(stat.MistakeLatencySamples)
+	}
+
+	// table dan column berasal dari konstanta di Record
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (user_id, %[2]s, presses, errors, total_latency_ms, latency_samples,
+			total_mistake_latency_ms, mistake_latency_samples, updated_at)
+		SELECT $1, d.key, d.presses, d.errors, d.latency, d.samples, d.mistake_latency, d.mistake_samples, $9
+		FROM unnest($2::text[], $3::int[], $4::int[], $5::bigint[], $6::int[], $7::bigint[], $8::int[])
+			AS d(key, presses, errors, latency, samples, mistake_latency, mistake_samples)
+		ON CONFLICT (user_id, %[2]s) DO UPDATE
+		SET presses = %[1]s.presses + EXCLUDED.presses,
+			errors = %[1]s.errors + EXCLUDED.errors,
+			total_latency_ms = %[1]s.total_latency_ms + EXCLUDED.total_latency_ms,
+			latency_samples = %[1]s.latency_samples + EXCLUDED.latency_samples,
+			total_mistake_latency_ms = %[1]s.total_mistake_latency_ms + EXCLUDED.total_mistake_latency_ms,
+			mistake_latency_samples = %[1]s.mistake_latency_samples + EXCLUDED.mistake_latency_samples,
+			updated_at = EXCLUDED.updated_at
+	`, table, column)
+	_, err := tx.ExecContext(ctx, query, userID,
+		pq.Array(keys), pq.Array(presses), pq.Array(errorCounts), pq.Array(latencies), pq.Array(samples),
+		pq.Array(mistakeLatencies), pq.Array(mistakeSamples), time.Now(),
+	)
+	return err
+}
+
+func (r *keyStatsRepository) FindKeysByUser(ctx context.Context, userID string) ([]*models.KeyStat, error) {
+	query := `
+		SELECT key_char, presses, errors, total_latency_ms, latency_samples, total_mistake_latency_ms, mistake_latency_samples
+		FROM user_key_stats
+		WHERE user_id = $1
+		ORDER BY key_char
+	`
+	return r.findMany(ctx, query, userID)
+}
+
+func (r *keyStatsRepository) FindBigramsByUser(ctx context.Context, userID string, minPresses, limit int) ([]*models.KeyStat, error) {
+	// Bigram paling bermasalah: error rate tertinggi, yang jarang diketik diabaikan
+	query := `
+		SELECT bigram, presses, errors, total_latency_ms, latency_samples, total_mistake_latency_ms, mistake_latency_samples
+		FROM user_bigram_stats
+		WHERE user_id = $1 AND errors > 0 AND presses >= $2
+		ORDER BY errors::float / presses DESC, errors DESC, bigram ASC
+		LIMIT $3
+	`
+	return r.findMany(ctx, query, userID, minPresses, limit)
+}
+
+func (r *keyStatsRepository) FindSubstitutionsByUser(ctx context.Context, userID string, limit int) ([]*models.KeySubstitution, error) {
+	query := `
+		SELECT expected, typed, count
+		FROM user_key_substitutions
+		WHERE user_id = $1
+		ORDER BY count DESC, expected ASC, typed ASC
+		LIMIT $2
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var substitutions []*models.KeySubstitution
+	for rows.Next() {
+		substitution := &models.KeySubstitution{}
+		if err := rows.Scan(&substitution.Expected, &substitution.Typed, &substitution.Count); err != nil {
+			return nil, err
+		}
+		substitutions = append(substitutions, substitution)
+	}
+	return substitutions, rows.Err()
+}
+
+func (r *keyStatsRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*models.KeyStat, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*models.KeyStat
+	for rows.Next() {
+		stat := &models.KeyStat{}
+		err := rows.Scan(
+			&stat.Key, &stat.Presses, &stat.Errors, &stat.TotalLatencyMs, &stat.LatencySamples,
+			&stat.TotalMistakeLatencyMs, &stat.MistakeLatencySamples,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
@@ -9,6 +9,7 @@ import (
 	"uwika_quick_typer_game/internal/domain/repositories"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type phraseRepository struct {
@@ -30,7 +31,7 @@ func (r *phraseRepository) Create(ctx context.Context, phrase *models.Phrase) er
 		INSERT INTO phrases (id, stage_id, text, sequence_number, base_multiplier, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		phrase.ID, phrase.StageID, phrase.Text, phrase.SequenceNumber, phrase.BaseMultiplier, phrase.CreatedAt, phrase.UpdatedAt,
 	)
 	return err
@@ -42,7 +43,7 @@ func (r *phraseRepository) FindByID(ctx context.Context, phraseID string) (*mode
 		FROM phrases WHERE id = $1
 	`
 	phrase := &models.Phrase{}
-	err := r.db.QueryRowContext(ctx, query, phraseID).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, phraseID).Scan(
 		&phrase.ID, &phrase.StageID, &phrase.Text, &phrase.SequenceNumber, &phrase.BaseMultiplier, &phrase.CreatedAt, &phrase.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -61,7 +62,7 @@ func (r *phraseRepository) FindByStageID(ctx context.Context, stageID string) ([
 		WHERE stage_id = $1
 		ORDER BY sequence_number ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query, stageID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, stageID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +82,39 @@ func (r *phraseRepository) FindByStageID(ctx context.Context, stageID string) ([
 	return phrases, nil
 }
 
+func (r *phraseRepository) FindFromActiveStages(ctx context.Context, now time.Time) ([]*models.Phrase, error) {
+	query := `
+		SELECT vp.phrase_id, s.id, vp.text, vp.sequence_number, vp.base_multiplier, v.published_at, v.published_at
+		FROM stages s
+		JOIN stage_versions v ON v.id = s.published_version_id
+		JOIN stage_version_phrases vp ON vp.version_id = v.id
+		WHERE s.is_active = true AND vp.phrase_id IS NOT NULL
+			AND (s.available_from IS NULL OR s.available_from <= $1)
+			AND (s.available_until IS NULL OR s.available_until > $1)
+		ORDER BY s.id ASC, vp.sequence_number ASC
+	`
+	// Jadwal disimpan sebagai waktu lokal server (lihat localTime), jadi now
+	// dikirim dari Go alih-alih memakai jam/zona sesi database
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, localTime(&now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var phrases []*models.Phrase
+	for rows.Next() {
+		phrase := &models.Phrase{}
+		err := rows.Scan(
+			&phrase.ID, &phrase.StageID, &phrase.Text, &phrase.SequenceNumber, &phrase.BaseMultiplier, &phrase.CreatedAt, &phrase.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		phrases = append(phrases, phrase)
+	}
+	return phrases, rows.Err()
+}
+
 func (r *phraseRepository) Update(ctx context.Context, phrase *models.Phrase) error {
 	phrase.UpdatedAt = time.Now()
 	query := `
@@ -88,7 +122,7 @@ func (r *phraseRepository) Update(ctx context.Context, phrase *models.Phrase) er
 		SET stage_id = $2, text = $3, sequence_number = $4, base_multiplier = $5, updated_at = $6
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		phrase.ID, phrase.StageID, phrase.Text, phrase.SequenceNumber, phrase.BaseMultiplier, phrase.UpdatedAt,
 	)
 	return err
@@ -96,6 +130,32 @@ func (r *phraseRepository) Update(ctx context.Context, phrase *models.Phrase) er
 
 func (r *phraseRepository) Delete(ctx context.Context, phraseID string) error {
 	query := `DELETE FROM phrases WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, phraseID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, phraseID)
 	return err
 }
+
+// Resequence dilakukan dua tahap agar tidak melanggar UNIQUE(stage_id,
+// sequence_number) di tengah jalan: semua phrase dipindah dulu ke nomor
+// negatif sementara, baru kemudian dibalik ke 1..n.
+func (r *phraseRepository) Resequence(ctx context.Context, stageID string, phraseIDs []string) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE phrases
+			SET sequence_number = -array_position($2::uuid[], id), updated_at = $3
+			WHERE stage_id = $1 AND id = ANY($2::uuid[])
+		`, stageID, pq.Array(phraseIDs), time.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE phrases
+			SET sequence_number = -sequence_number
+			WHERE stage_id = $1 AND sequence_number < 0
+		`, stageID)
+		if isPQError(err, pqUniqueViolation) {
+			return repositories.ErrDuplicate
+		}
+		return err
+	})
+}
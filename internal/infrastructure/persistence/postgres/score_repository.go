@@ -3,12 +3,27 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"uwika_quick_typer_game/internal/domain/models"
 	"uwika_quick_typer_game/internal/domain/repositories"
 )
 
+// LeaderboardChannel adalah channel NOTIFY yang dikirim setiap kali best
+// score user di sebuah stage berubah atau leaderboard stage di-reset
+// (nama channel juga dipakai trigger di migration 000023)
+const LeaderboardChannel = "leaderboard_updates"
+
+// LeaderboardNotification adalah payload JSON di LeaderboardChannel.
+// ScoreID 0 berarti leaderboard stage di-reset saat publish versi baru atau
+// best score dihitung ulang karena score dihapus. StageID kosong berarti
+// season dimulai atau ditutup sehingga board default semua stage berganti.
+type LeaderboardNotification struct {
+	StageID string `json:"stage_id"`
+	ScoreID int64  `json:"score_id"`
+}
+
 type scoreRepository struct {
 	db *sql.DB
 }
@@ -20,29 +35,82 @@ func NewScoreRepository(db *sql.DB) repositories.ScoreRepository {
 func (r *scoreRepository) Create(ctx context.Context, score *models.Score) error {
 	score.CompletedAt = time.Now()
 
-	query := `
-		INSERT INTO scores (user_id, stage_id, final_score, total_time_ms, total_errors, completed_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-	_, err := r.db.ExecContext(ctx, query,
-		score.UserID, score.StageID, score.FinalScore, score.TotalTimeMs, score.TotalErrors, score.CompletedAt,
-	)
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Score masuk ke season yang sedang aktif. FOR SHARE menahan
+		// penutupan season sampai transaksi ini selesai, sehingga score
+		// tidak terlewat dari final standings.
+		query := `
+			INSERT INTO scores (
+				user_id, stage_id, stage_version_id, final_score, total_time_ms, total_errors, total_chars, wpm, accuracy,
+				completed_at, season_id
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (SELECT id FROM seasons WHERE status = 'active' FOR SHARE))
+			RETURNING id, season_id
+		`
+		var seasonID sql.NullString
+		err := tx.QueryRowContext(ctx, query,
+			score.UserID, score.StageID, score.StageVersionID, score.FinalScore, score.TotalTimeMs, score.TotalErrors,
+			score.TotalChars, score.Wpm, score.Accuracy, score.CompletedAt,
+		).Scan(&score.ID, &seasonID)
+		if err != nil {
+			return err
+		}
+		score.SeasonID = seasonID.String
+
+		// Perbarui best score hanya jika attempt ini lebih baik. Score dari
+		// versi sebelum reset leaderboard terakhir tidak dihitung.
+		bestQuery := `
+			INSERT INTO user_stage_bests (user_id, stage_id, score_id, final_score, total_time_ms, total_errors, completed_at)
+			SELECT $1, $2, $3, $4, $5, $6, $7
+			FROM stage_versions v
+			JOIN stages st ON st.id = v.stage_id
+			WHERE v.id = $8 AND v.version_number >= st.leaderboard_min_version
+			ON CONFLICT (user_id, stage_id) DO UPDATE
+			SET score_id = EXCLUDED.score_id,
+				final_score = EXCLUDED.final_score,
+				total_time_ms = EXCLUDED.total_time_ms,
+				total_errors = EXCLUDED.total_errors,
+				completed_at = EXCLUDED.completed_at
+			WHERE EXCLUDED.final_score > user_stage_bests.final_score
+				OR (EXCLUDED.final_score = user_stage_bests.final_score AND EXCLUDED.total_time_ms < user_stage_bests.total_time_ms)
+		`
+		result, err := tx.ExecContext(ctx, bestQuery,
+			score.UserID, score.StageID, score.ID, score.FinalScore, score.TotalTimeMs, score.TotalErrors, score.CompletedAt,
+			score.StageVersionID,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		score.IsPersonalBest = affected > 0
+		if !score.IsPersonalBest {
+			return nil
+		}
 
-	return err
+		// Leaderboard hanya bisa berubah jika best score berubah. NOTIFY
+		// baru terkirim ke listener (semua instance API) setelah commit.
+		payload, err := json.Marshal(LeaderboardNotification{StageID: score.StageID, ScoreID: score.ID})
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, LeaderboardChannel, string(payload))
+		return err
+	})
 }
 
 func (r *scoreRepository) FindByUserAndStage(ctx context.Context, userID, stageID string) (*models.Score, error) {
 	// Get best score for this user on this stage
 	query := `
-		SELECT user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
-		FROM scores 
+		SELECT score_id, user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
+		FROM user_stage_bests
 		WHERE user_id = $1 AND stage_id = $2
-		ORDER BY final_score DESC, total_time_ms ASC
-		LIMIT 1
 	`
 	score := &models.Score{}
-	err := r.db.QueryRowContext(ctx, query, userID, stageID).Scan(
-		&score.UserID, &score.StageID, &score.FinalScore, &score.TotalTimeMs, &score.TotalErrors, &score.CompletedAt,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, stageID).Scan(
+		&score.ID, &score.UserID, &score.StageID, &score.FinalScore, &score.TotalTimeMs, &score.TotalErrors, &score.CompletedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -53,64 +121,313 @@ func (r *scoreRepository) FindByUserAndStage(ctx context.Context, userID, stageI
 	return score, nil
 }
 
-func (r *scoreRepository) FindLeaderboardByStage(ctx context.Context, stageID string, limit int) ([]*models.Score, error) {
-	// Get best score per user for the leaderboard
+// rankedLeaderboardCTE ranks the best attempt per user of stage $1 within
+// the window ($2 = since, $3 = season, both NULL = all-time). All-time
+// leaderboards read the materialized user_stage_bests table; windowed ones
+// pick the best attempt inside the window from scores. Scores played on a
+// version older than the stage's last leaderboard reset never rank.
+func rankedLeaderboardCTE(window models.ScoreWindow) string {
+	bestScores := `
+		SELECT score_id, user_id, final_score, total_time_ms, total_errors, completed_at
+		FROM user_stage_bests
+		WHERE stage_id = $1 AND $2::timestamp IS NULL AND $3::uuid IS NULL
+	`
+	if !window.IsAllTime() {
+		bestScores = `
+			SELECT DISTINCT ON (sc.user_id)
+				sc.id AS score_id, sc.user_id, sc.final_score, sc.total_time_ms, sc.total_errors, sc.completed_at
+			FROM scores sc
+			JOIN stages st ON st.id = sc.stage_id
+			JOIN stage_versions v ON v.id = sc.stage_version_id
+			WHERE sc.stage_id = $1
+				AND v.version_number >= st.leaderboard_min_version
+				AND ($2::timestamp IS NULL OR sc.completed_at >= $2::timestamp)
+				AND ($3::uuid IS NULL OR sc.season_id = $3::uuid)
+			ORDER BY sc.user_id, sc.final_score DESC, sc.total_time_ms ASC
+		`
+	}
+	return `
+		WITH best_scores AS (` + bestScores + `),
+		ranked AS (
+			SELECT
+				score_id, user_id, final_score, total_time_ms, total_errors, completed_at,
+				ROW_NUMBER() OVER (ORDER BY final_score DESC, total_time_ms ASC, user_id ASC) AS rank
+			FROM best_scores
+		)
+	`
+}
+
+func (r *scoreRepository) FindLeaderboardByStage(ctx context.Context, q repositories.LeaderboardQuery) ([]*models.LeaderboardEntry, error) {
+	// Keyset pagination: ambil entry setelah cursor (jika ada)
+	query := rankedLeaderboardCTE(q.Window) + `
+		SELECT r.rank, r.score_id, r.user_id, u.username, u.leaderboard_anonymous, r.final_score, r.total_time_ms, r.total_errors, r.completed_at
+		FROM ranked r
+		JOIN users u ON u.id = r.user_id
+		WHERE $4::numeric IS NULL
+			OR r.final_score < $4::numeric
+			OR (r.final_score = $4::numeric AND r.total_time_ms > $5::int)
+			OR (r.final_score = $4::numeric AND r.total_time_ms = $5::int AND r.user_id > $6::uuid)
+		ORDER BY r.rank
+		LIMIT $7
+	`
+	var afterScore sql.NullFloat64
+	var afterTime sql.NullInt64
+	var afterUser sql.NullString
+	if q.After != nil {
+		afterScore = sql.NullFloat64{Float64: q.After.FinalScore, Valid: true}
+		afterTime = sql.NullInt64{Int64: int64(q.After.TotalTimeMs), Valid: true}
+		afterUser = sql.NullString{String: q.After.UserID, Valid: true}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query,
+		q.StageID, windowStart(q.Window.Since), nullString(q.Window.SeasonID), afterScore, afterTime, afterUser, q.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLeaderboardEntries(rows)
+}
+
+func (r *scoreRepository) FindLeaderboardAroundUser(ctx context.Context, stageID string, window models.ScoreWindow, userID string, radius int) ([]*models.LeaderboardEntry, error) {
+	query := rankedLeaderboardCTE(window) + `
+		, me AS (
+			SELECT rank FROM ranked WHERE user_id = $4::uuid
+		)
+		SELECT r.rank, r.score_id, r.user_id, u.username, u.leaderboard_anonymous, r.final_score, r.total_time_ms, r.total_errors, r.completed_at
+		FROM ranked r
+		JOIN users u ON u.id = r.user_id
+		CROSS JOIN me
+		WHERE r.rank BETWEEN me.rank - $5 AND me.rank + $5
+		ORDER BY r.rank
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, stageID, windowStart(window.Since), nullString(window.SeasonID), userID, radius)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLeaderboardEntries(rows)
+}
+
+func scanLeaderboardEntries(rows *sql.Rows) ([]*models.LeaderboardEntry, error) {
+	var entries []*models.LeaderboardEntry
+	for rows.Next() {
+		entry := &models.LeaderboardEntry{}
+		err := rows.Scan(
+			&entry.Rank, &entry.ScoreID, &entry.UserID, &entry.Username, &entry.Anonymous, &entry.FinalScore, &entry.TotalTimeMs, &entry.TotalErrors, &entry.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *scoreRepository) FindByUserID(ctx context.Context, q repositories.ScoreHistoryQuery) ([]*models.Attempt, error) {
+	query := `
+		SELECT sc.id, sc.user_id, sc.stage_id, COALESCE(sc.season_id::text, ''), sc.final_score,
+			sc.total_time_ms, sc.total_errors, sc.completed_at, st.name
+		FROM scores sc
+		JOIN stages st ON st.id = sc.stage_id
+		WHERE sc.user_id = $1
+			AND ($2::uuid IS NULL OR sc.stage_id = $2::uuid)
+			AND ($3::timestamp IS NULL OR sc.completed_at >= $3::timestamp)
+			AND ($4::timestamp IS NULL OR sc.completed_at < $4::timestamp)
+			AND ($5::timestamp IS NULL OR (sc.completed_at, sc.id) < ($5::timestamp, $6::int))
+		ORDER BY sc.completed_at DESC, sc.id DESC
+		LIMIT $7
+	`
+	// Cursor berasal dari completed_at yang sudah dalam zona lokal (lihat
+	// localWallClock), jadi wall clock-nya sama persis dengan di database
+	var afterTime sql.NullTime
+	var afterID sql.NullInt64
+	if q.After != nil {
+		afterTime = sql.NullTime{Time: q.After.CompletedAt, Valid: true}
+		afterID = sql.NullInt64{Int64: q.After.ScoreID, Valid: true}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query,
+		q.UserID, nullString(q.StageID), windowStart(q.From), windowStart(q.To), afterTime, afterID, q.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*models.Attempt
+	for rows.Next() {
+		attempt := &models.Attempt{}
+		err := rows.Scan(
+			&attempt.ID, &attempt.UserID, &attempt.StageID, &attempt.SeasonID, &attempt.FinalScore,
+			&attempt.TotalTimeMs, &attempt.TotalErrors, &attempt.CompletedAt, &attempt.StageName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		attempt.CompletedAt = localWallClock(attempt.CompletedAt)
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
+}
+
+func (r *scoreRepository) FindBestsByUser(ctx context.Context, userID string) ([]*models.StageBest, error) {
+	// Dihitung dari scores (bukan user_stage_bests) supaya best pribadi
+	// tetap ada setelah leaderboard stage di-reset
 	query := `
-		WITH best_scores AS (
-			SELECT DISTINCT ON (user_id)
-				user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
-			FROM scores 
-			WHERE stage_id = $1
-			ORDER BY user_id, final_score DESC, total_time_ms ASC
+		WITH bests AS (
+			SELECT DISTINCT ON (stage_id)
+				user_id, stage_id, id AS score_id, final_score, total_time_ms, total_errors, completed_at
+			FROM scores
+			WHERE user_id = $1
+			ORDER BY stage_id, final_score DESC, total_time_ms ASC
 		)
-		SELECT user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
-		FROM best_scores
-		ORDER BY final_score DESC, total_time_ms ASC
-		LIMIT $2
+		SELECT b.stage_id, st.name, b.score_id, b.final_score, b.total_time_ms, b.total_errors, b.completed_at,
+			stats.attempt_count, stats.last_played_at
+		FROM bests b
+		JOIN stages st ON st.id = b.stage_id
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS attempt_count, MAX(completed_at) AS last_played_at
+			FROM scores
+			WHERE user_id = b.user_id AND stage_id = b.stage_id
+		) stats
+		ORDER BY stats.last_played_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, stageID, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var scores []*models.Score
+	var bests []*models.StageBest
 	for rows.Next() {
-		score := &models.Score{}
+		best := &models.StageBest{}
 		err := rows.Scan(
-			&score.UserID, &score.StageID, &score.FinalScore, &score.TotalTimeMs, &score.TotalErrors, &score.CompletedAt,
+			&best.StageID, &best.StageName, &best.Best.ID, &best.Best.FinalScore, &best.Best.TotalTimeMs,
+			&best.Best.TotalErrors, &best.Best.CompletedAt, &best.AttemptCount, &best.LastPlayedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		scores = append(scores, score)
+		best.Best.CompletedAt = localWallClock(best.Best.CompletedAt)
+		best.LastPlayedAt = localWallClock(best.LastPlayedAt)
+		best.Best.UserID = userID
+		best.Best.StageID = best.StageID
+		bests = append(bests, best)
 	}
-	return scores, nil
+	return bests, rows.Err()
 }
 
-func (r *scoreRepository) FindByUserID(ctx context.Context, userID string) ([]*models.Score, error) {
+func (r *scoreRepository) FindAggregateLeaderboard(ctx context.Context, filter repositories.AggregateLeaderboardFilter) ([]*models.AggregateLeaderboardEntry, error) {
+	// Best score per (user, stage) di stage aktif dalam scope (all-time dari
+	// user_stage_bests, windowed dari scores), lalu
+	// dinormalisasi per stage sebelum di-aggregate per user.
+	// Stage yang belum diselesaikan tidak punya baris, jadi kontribusinya 0
+	// dan rata-rata percentile tetap dibagi jumlah seluruh stage dalam scope.
 	query := `
-		SELECT user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
-		FROM scores 
-		WHERE user_id = $1
-		ORDER BY completed_at DESC
+		WITH scoped_stages AS (
+			SELECT id, leaderboard_min_version FROM stages
+			WHERE is_active = true
+				AND ($1::uuid IS NULL OR theme_id = $1::uuid)
+				AND ($2::text IS NULL OR difficulty = $2::text)
+		),
+		stage_count AS (
+			SELECT COUNT(*) AS total FROM scoped_stages
+		),
+		best_scores AS (
+			SELECT b.user_id, b.stage_id, b.final_score, b.total_time_ms
+			FROM user_stage_bests b
+			JOIN scoped_stages ss ON ss.id = b.stage_id
+			WHERE $3::timestamp IS NULL AND $4::uuid IS NULL
+			UNION ALL
+			(
+				SELECT DISTINCT ON (sc.user_id, sc.stage_id)
+					sc.user_id, sc.stage_id, sc.final_score, sc.total_time_ms
+				FROM scores sc
+				JOIN scoped_stages ss ON ss.id = sc.stage_id
+				JOIN stage_versions v ON v.id = sc.stage_version_id
+				WHERE ($3::timestamp IS NOT NULL OR $4::uuid IS NOT NULL)
+					AND v.version_number >= ss.leaderboard_min_version
+					AND ($3::timestamp IS NULL OR sc.completed_at >= $3::timestamp)
+					AND ($4::uuid IS NULL OR sc.season_id = $4::uuid)
+				ORDER BY sc.user_id, sc.stage_id, sc.final_score DESC, sc.total_time_ms ASC
+			)
+		),
+		stage_points AS (
+			SELECT
+				user_id,
+				total_time_ms,
+				CASE WHEN MAX(final_score) OVER per_stage > 0
+					THEN (final_score / MAX(final_score) OVER per_stage * 1000)::float8
+					ELSE 0
+				END AS normalized,
+				CUME_DIST() OVER (PARTITION BY stage_id ORDER BY final_score ASC, total_time_ms DESC) * 100 AS percentile
+			FROM best_scores
+			WINDOW per_stage AS (PARTITION BY stage_id)
+		),
+		aggregated AS (
+			SELECT
+				sp.user_id,
+				CASE WHEN $5 = 'average_percentile'
+					THEN SUM(sp.percentile) / sc.total
+					ELSE SUM(sp.normalized)
+				END AS aggregate_score,
+				COUNT(*) AS stages_completed,
+				sc.total AS stages_total,
+				SUM(sp.total_time_ms) AS total_time_ms
+			FROM stage_points sp
+			CROSS JOIN stage_count sc
+			GROUP BY sp.user_id, sc.total
+		)
+		SELECT a.user_id, u.username, u.leaderboard_anonymous, a.aggregate_score, a.stages_completed, a.stages_total, a.total_time_ms
+		FROM aggregated a
+		JOIN users u ON u.id = a.user_id
+		ORDER BY a.aggregate_score DESC, a.stages_completed DESC, a.total_time_ms ASC, a.user_id ASC
+		LIMIT $6
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query,
+		nullString(filter.ThemeID), nullString(filter.Difficulty),
+		windowStart(filter.Window.Since), nullString(filter.Window.SeasonID),
+		string(filter.Method), filter.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var scores []*models.Score
+	var entries []*models.AggregateLeaderboardEntry
 	for rows.Next() {
-		score := &models.Score{}
+		entry := &models.AggregateLeaderboardEntry{}
 		err := rows.Scan(
-			&score.UserID, &score.StageID, &score.FinalScore, &score.TotalTimeMs, &score.TotalErrors, &score.CompletedAt,
+			&entry.UserID, &entry.Username, &entry.Anonymous, &entry.AggregateScore, &entry.StagesCompleted, &entry.StagesTotal, &entry.TotalTimeMs,
 		)
 		if err != nil {
 			return nil, err
 		}
-		scores = append(scores, score)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// windowStart converts a time boundary into a query parameter. completed_at
+// is a TIMESTAMP holding server-local wall clock time (see Create), so the
+// boundary is shifted to the local zone; the zero time means no bound.
+func windowStart(since time.Time) sql.NullTime {
+	if since.IsZero() {
+		return sql.NullTime{}
 	}
-	return scores, nil
+	return sql.NullTime{Time: since.Local(), Valid: true}
+}
+
+// localWallClock re-attaches the server-local zone to a TIMESTAMP value read
+// back from the database, which the driver returns as UTC.
+func localWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
 }
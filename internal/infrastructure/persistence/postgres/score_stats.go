@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+// Jumlah bucket (termasuk bucket itu sendiri) untuk rata-rata bergulir
+const (
+	dailyRollingBuckets  = 7
+	weeklyRollingBuckets = 4
+)
+
+func (r *scoreRepository) FindLatestScoreID(ctx context.Context, userID string) (int64, error) {
+	query := `
+		SELECT id FROM scores
+		WHERE user_id = $1
+		ORDER BY completed_at DESC, id DESC
+		LIMIT 1
+	`
+	var id int64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (r *scoreRepository) FindPlayerStats(ctx context.Context, q repositories.PlayerStatsQuery) (*models.PlayerStats, error) {
+	stats := &models.PlayerStats{Since: q.Since}
+
+	// completed_at menyimpan wall clock lokal server; geser ke timezone
+	// statistik sebelum dipotong per hari/minggu
+	shift := zoneShiftSeconds(q.Since, q.Location)
+
+	trendQuery := `
+		SELECT COUNT(*),
+			COALESCE(regr_slope(wpm, EXTRACT(EPOCH FROM completed_at) / 86400), 0),
+			COALESCE(regr_slope(accuracy, EXTRACT(EPOCH FROM completed_at) / 86400), 0)
+		FROM scores
+		WHERE user_id = $1 AND completed_at >= $2
+	`
+	err := conn(ctx, r.db).QueryRowContext(ctx, trendQuery, q.UserID, windowStart(q.Since)).Scan(
+		&stats.Attempts, &stats.WpmTrend, &stats.AccuracyTrend,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Attempts == 0 {
+		return stats, nil
+	}
+
+	stats.Daily, err = r.findStatsBuckets(ctx, q, "day", dailyRollingBuckets, shift)
+	if err != nil {
+		return nil, err
+	}
+	stats.Weekly, err = r.findStatsBuckets(ctx, q, "week", weeklyRollingBuckets, shift)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *scoreRepository) findStatsBuckets(ctx context.Context, q repositories.PlayerStatsQuery, unit string, rolling int, shift int) ([]*models.StatsBucket, error) {
+	// unit dan rolling berasal dari konstanta, bukan input user
+	query := fmt.Sprintf(`
+		WITH buckets AS (
+			SELECT
+				date_trunc('%s', completed_at + make_interval(secs => $3)) AS bucket,
+				COUNT(*) AS attempts,
+				COALESCE(AVG(wpm), 0) AS avg_wpm,
+				COALESCE(MAX(wpm), 0) AS best_wpm,
+				COALESCE(AVG(accuracy), 0) AS avg_accuracy,
+				COALESCE(MAX(accuracy), 0) AS best_accuracy,
+				COALESCE(SUM(total_errors) * 100.0 / NULLIF(SUM(total_chars), 0), 0) AS error_rate,
+				SUM(total_time_ms) AS time_played_ms
+			FROM scores
+			WHERE user_id = $1 AND completed_at >= $2
+			GROUP BY 1
+		)
+		SELECT bucket, attempts, avg_wpm, best_wpm, avg_accuracy, best_accuracy, error_rate, time_played_ms,
+			AVG(avg_wpm) OVER rolling, AVG(avg_accuracy) OVER rolling
+		FROM buckets
+		WINDOW rolling AS (ORDER BY bucket ROWS BETWEEN %d PRECEDING AND CURRENT ROW)
+		ORDER BY bucket
+	`, unit, rolling-1)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, q.UserID, windowStart(q.Since), shift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []*models.StatsBucket
+	for rows.Next() {
+		bucket := &models.StatsBucket{}
+		err := rows.Scan(
+			&bucket.Start, &bucket.Attempts, &bucket.AvgWpm, &bucket.BestWpm, &bucket.AvgAccuracy,
+			&bucket.BestAccuracy, &bucket.ErrorRate, &bucket.TimePlayedMs,
+			&bucket.RollingAvgWpm, &bucket.RollingAvgAccuracy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		// bucket adalah wall clock di timezone statistik
+		start := bucket.Start
+		bucket.Start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, q.Location)
+		buckets = append(buckets, bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// zoneShiftSeconds adalah selisih offset timezone loc terhadap zona lokal
+// server pada waktu at. Perubahan DST di dalam rentang statistik diabaikan.
+func zoneShiftSeconds(at time.Time, loc *time.Location) int {
+	_, locOffset := at.In(loc).Zone()
+	_, localOffset := at.Local().Zone()
+	return locOffset - localOffset
+}
+
+func (r *scoreRepository) FindPlayerSummary(ctx context.Context, userID string) (*models.PlayerSummary, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(MAX(wpm), 0), MAX(completed_at),
+			COUNT(DISTINCT stage_id)
+		FROM scores
+		WHERE user_id = $1
+	`
+	summary := &models.PlayerSummary{}
+	var lastPlayedAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
+		&summary.TotalAttempts, &summary.BestWpm, &lastPlayedAt, &summary.StagesCompleted,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastPlayedAt.Valid {
+		t := localWallClock(lastPlayedAt.Time)
+		summary.LastPlayedAt = &t
+	}
+	return summary, nil
+}
+
+func (r *scoreRepository) FindTopPlacements(ctx context.Context, userID string, maxRank, limit int) ([]*models.Placement, error) {
+	// Rank dihitung dengan urutan yang sama seperti leaderboard all-time,
+	// memakai index user_stage_bests per stage
+	query := `
+		SELECT stage_id, stage_name, rank, final_score, total_time_ms
+		FROM (
+			SELECT b.stage_id, st.name AS stage_name, b.final_score, b.total_time_ms,
+				1 + (
+					SELECT COUNT(*)
+					FROM user_stage_bests o
+					WHERE o.stage_id = b.stage_id
+						AND (o.final_score > b.final_score
+							OR (o.final_score = b.final_score AND o.total_time_ms < b.total_time_ms)
+							OR (o.final_score = b.final_score AND o.total_time_ms = b.total_time_ms AND o.user_id < b.user_id))
+				) AS rank
+			FROM user_stage_bests b
+			JOIN stages st ON st.id = b.stage_id AND st.is_active = true
+			WHERE b.user_id = $1
+		) placements
+		WHERE rank <= $2
+		ORDER BY rank ASC, final_score DESC, stage_name ASC
+		LIMIT $3
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, maxRank, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var placements []*models.Placement
+	for rows.Next() {
+		placement := &models.Placement{}
+		err := rows.Scan(&placement.StageID, &placement.StageName, &placement.Rank, &placement.FinalScore, &placement.TotalTimeMs)
+		if err != nil {
+			return nil, err
+		}
+		placements = append(placements, placement)
+	}
+	return placements, rows.Err()
+}
+
+func (r *scoreRepository) FindBestAccuracyByUser(ctx context.Context, userID string) (map[string]float64, error) {
+	query := `
+		SELECT stage_id, MAX(accuracy)
+		FROM scores
+		WHERE user_id = $1 AND accuracy IS NOT NULL
+		GROUP BY stage_id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	best := make(map[string]float64)
+	for rows.Next() {
+		var stageID string
+		var accuracy float64
+		if err := rows.Scan(&stageID, &accuracy); err != nil {
+			return nil, err
+		}
+		best[stageID] = accuracy
+	}
+	return best, rows.Err()
+}
+
+func (r *scoreRepository) FindCompletedThemes(ctx context.Context, userID string) (map[string]bool, error) {
+	// Memakai scores, bukan user_stage_bests, supaya reset leaderboard
+	// stage tidak membatalkan theme yang sudah selesai
+	query := `
+		SELECT st.theme_id
+		FROM stages st
+		WHERE st.is_active = true AND st.published_version_id IS NOT NULL
+		GROUP BY st.theme_id
+		HAVING bool_and(EXISTS (SELECT 1 FROM scores sc WHERE sc.stage_id = st.id AND sc.user_id = $1))
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	themes := make(map[string]bool)
+	for rows.Next() {
+		var themeID string
+		if err := rows.Scan(&themeID); err != nil {
+			return nil, err
+		}
+		themes[themeID] = true
+	}
+	return themes, rows.Err()
+}
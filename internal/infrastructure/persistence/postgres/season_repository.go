@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type seasonRepository struct {
+	db *sql.DB
+}
+
+func NewSeasonRepository(db *sql.DB) repositories.SeasonRepository {
+	return &seasonRepository{db: db}
+}
+
+func (r *seasonRepository) Create(ctx context.Context, season *models.Season) error {
+	if season.ID == "" {
+		season.ID = uuid.New().String()
+	}
+	season.Status = models.SeasonStatusActive
+	season.StartsAt = time.Now()
+	season.CreatedAt = season.StartsAt
+
+	// Kolom TIMESTAMP menyimpan waktu lokal server (sama seperti completed_at)
+	var endsAt sql.NullTime
+	if season.EndsAt != nil {
+		endsAt = sql.NullTime{Time: season.EndsAt.Local(), Valid: true}
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO seasons (id, name, status, starts_at, ends_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`
+		_, err := tx.ExecContext(ctx, query,
+			season.ID, season.Name, season.Status, season.StartsAt, endsAt, season.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return notifySeasonChange(ctx, tx)
+	})
+}
+
+// notifySeasonChange memberi tahu semua instance bahwa board default (board
+// season aktif) semua stage berganti
+func notifySeasonChange(ctx context.Context, tx *sql.Tx) error {
+	payload, err := json.Marshal(LeaderboardNotification{})
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, LeaderboardChannel, string(payload))
+	return err
+}
+
+func (r *seasonRepository) FindByID(ctx context.Context, seasonID string) (*models.Season, error) {
+	query := `
+		SELECT id, name, status, starts_at, ends_at, closed_at, created_at
+		FROM seasons WHERE id = $1
+	`
+	return scanSeason(conn(ctx, r.db).QueryRowContext(ctx, query, seasonID))
+}
+
+func (r *seasonRepository) FindActive(ctx context.Context) (*models.Season, error) {
+	query := `
+		SELECT id, name, status, starts_at, ends_at, closed_at, created_at
+		FROM seasons WHERE status = 'active'
+	`
+	return scanSeason(conn(ctx, r.db).QueryRowContext(ctx, query))
+}
+
+func (r *seasonRepository) FindAll(ctx context.Context) ([]*models.Season, error) {
+	query := `
+		SELECT id, name, status, starts_at, ends_at, closed_at, created_at
+		FROM seasons
+		ORDER BY starts_at DESC
+	`
+	return r.findMany(ctx, query)
+}
+
+func (r *seasonRepository) FindExpired(ctx context.Context, now time.Time) ([]*models.Season, error) {
+	query := `
+		SELECT id, name, status, starts_at, ends_at, closed_at, created_at
+		FROM seasons
+		WHERE status = 'active' AND ends_at IS NOT NULL AND ends_at <= $1
+	`
+	return r.findMany(ctx, query, now.Local())
+}
+
+func (r *seasonRepository) Close(ctx context.Context, seasonID string) (*models.Season, error) {
+	var season *models.Season
+	err := withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Update lebih dulu: row lock membuat score baru menunggu (lihat
+		// ScoreRepository.Create) lalu tidak lagi masuk ke season ini
+		query := `
+			UPDATE seasons
+			SET status = 'closed', closed_at = $2
+			WHERE id = $1 AND status = 'active'
+			RETURNING id, name, status, starts_at, ends_at, closed_at, created_at
+		`
+		var err error
+		season, err = scanSeason(tx.QueryRowContext(ctx, query, seasonID, time.Now()))
+		if err != nil || season == nil {
+			return err
+		}
+
+		standingsQuery := `
+			INSERT INTO season_standings (
+				season_id, stage_id, stage_name, rank, user_id, username,
+				score_id, final_score, total_time_ms, total_errors, completed_at
+			)
+			SELECT
+				$1, b.stage_id, st.name,
+				ROW_NUMBER() OVER (PARTITION BY b.stage_id ORDER BY b.final_score DESC, b.total_time_ms ASC, b.user_id ASC),
+				b.user_id, u.username,
+				b.id, b.final_score, b.total_time_ms, b.total_errors, b.completed_at
+			FROM (
+				SELECT DISTINCT ON (user_id, stage_id)
+					id, user_id, stage_id, final_score, total_time_ms, total_errors, completed_at
+				FROM scores
+				WHERE season_id = $1
+				ORDER BY user_id, stage_id, final_score DESC, total_time_ms ASC
+			) b
+			JOIN stages st ON st.id = b.stage_id
+			JOIN users u ON u.id = b.user_id
+		`
+		if _, err := tx.ExecContext(ctx, standingsQuery, seasonID); err != nil {
+			return err
+		}
+		return notifySeasonChange(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+func (r *seasonRepository) FindStandings(ctx context.Context, seasonID, stageID string, limit int) ([]*models.SeasonStanding, error) {
+	// limit berlaku per stage (mis. 3 untuk podium)
+	query := `
+		SELECT ss.season_id, ss.stage_id, ss.stage_name, ss.rank, ss.user_id, ss.username,
+			COALESCE(u.leaderboard_anonymous, FALSE),
+			ss.final_score, ss.total_time_ms, ss.total_errors, ss.completed_at
+		FROM season_standings ss
+		LEFT JOIN users u ON u.id = ss.user_id
+		WHERE ss.season_id = $1
+			AND ($2::uuid IS NULL OR ss.stage_id = $2::uuid)
+			AND ss.rank <= $3
+		ORDER BY ss.stage_name ASC, ss.stage_id ASC, ss.rank ASC
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, seasonID, nullString(stageID), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []*models.SeasonStanding
+	for rows.Next() {
+		standing := &models.SeasonStanding{}
+		err := rows.Scan(
+			&standing.SeasonID, &standing.StageID, &standing.StageName, &standing.Rank, &standing.UserID, &standing.Username, &standing.Anonymous,
+			&standing.FinalScore, &standing.TotalTimeMs, &standing.TotalErrors, &standing.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, standing)
+	}
+	return standings, rows.Err()
+}
+
+func (r *seasonRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*models.Season, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []*models.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSeason(row rowScanner) (*models.Season, error) {
+	season := &models.Season{}
+	var endsAt, closedAt sql.NullTime
+	err := row.Scan(
+		&season.ID, &season.Name, &season.Status, &season.StartsAt, &endsAt, &closedAt, &season.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if endsAt.Valid {
+		season.EndsAt = &endsAt.Time
+	}
+	if closedAt.Valid {
+		season.ClosedAt = &closedAt.Time
+	}
+	return season, nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+	domainservices "uwika_quick_typer_game/internal/domain/services"
+)
+
+type stagePrerequisiteRepository struct {
+	db *sql.DB
+}
+
+func NewStagePrerequisiteRepository(db *sql.DB) repositories.StagePrerequisiteRepository {
+	return &stagePrerequisiteRepository{db: db}
+}
+
+func (r *stagePrerequisiteRepository) FindAll(ctx context.Context) ([]*models.StagePrerequisite, error) {
+	query := `
+		SELECT p.stage_id, p.required_stage_id, st.name, st.is_active, p.min_stars, p.min_accuracy
+		FROM stage_prerequisites p
+		JOIN stages st ON st.id = p.required_stage_id
+		ORDER BY p.stage_id, st.name
+	`
+	return r.findMany(ctx, query)
+}
+
+func (r *stagePrerequisiteRepository) FindByStageID(ctx context.Context, stageID string) ([]*models.StagePrerequisite, error) {
+	query := `
+		SELECT p.stage_id, p.required_stage_id, st.name, st.is_active, p.min_stars, p.min_accuracy
+		FROM stage_prerequisites p
+		JOIN stages st ON st.id = p.required_stage_id
+		WHERE p.stage_id = $1
+		ORDER BY st.name
+	`
+	return r.findMany(ctx, query, stageID)
+}
+
+func (r *stagePrerequisiteRepository) ReplaceForStage(ctx context.Context, stageID string, prerequisites []*models.StagePrerequisite) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Kunci tabel dari perubahan paralel supaya pengecekan siklus di
+		// bawah tidak dilewati oleh dua update yang bersamaan
+		if _, err := tx.ExecContext(ctx, `LOCK TABLE stage_prerequisites IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM stage_prerequisites WHERE stage_id = $1`, stageID); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO stage_prerequisites (stage_id, required_stage_id, min_stars, min_accuracy, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		now := time.Now()
+		for _, prerequisite := range prerequisites {
+			_, err := tx.ExecContext(ctx, query,
+				stageID, prerequisite.RequiredStageID, prerequisite.MinStars, prerequisite.MinAccuracy, now,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Service sudah memeriksa siklus; cek ulang di dalam transaksi
+		// terhadap data yang terkunci
+		cycleQuery := `
+			WITH RECURSIVE reachable(stage_id) AS (
+				SELECT required_stage_id FROM stage_prerequisites WHERE stage_id = $1
+				UNION
+				SELECT p.required_stage_id
+				FROM stage_prerequisites p
+				JOIN reachable r ON p.stage_id = r.stage_id
+			)
+			SELECT EXISTS (SELECT 1 FROM reachable WHERE stage_id = $1)
+		`
+		var cycle bool
+		if err := tx.QueryRowContext(ctx, cycleQuery, stageID).Scan(&cycle); err != nil {
+			return err
+		}
+		if cycle {
+			return domainservices.ErrPrerequisiteCycle
+		}
+		return nil
+	})
+}
+
+func (r *stagePrerequisiteRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*models.StagePrerequisite, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prerequisites []*models.StagePrerequisite
+	for rows.Next() {
+		prerequisite := &models.StagePrerequisite{}
+		err := rows.Scan(
+			&prerequisite.StageID, &prerequisite.RequiredStageID, &prerequisite.RequiredStageName,
+			&prerequisite.RequiredStageActive, &prerequisite.MinStars, &prerequisite.MinAccuracy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		prerequisites = append(prerequisites, prerequisite)
+	}
+	return prerequisites, rows.Err()
+}
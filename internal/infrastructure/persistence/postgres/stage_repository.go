@@ -25,69 +25,63 @@ func (r *stageRepository) Create(ctx context.Context, stage *models.Stage) error
 	}
 	stage.CreatedAt = time.Now()
 	stage.UpdatedAt = time.Now()
+	stage.LeaderboardMinVersion = 1
 
 	query := `
-		INSERT INTO stages (id, name, theme_id, difficulty, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO stages (
+			id, slug, name, theme_id, difficulty, is_active, available_from, available_until, phrase_charset, phrase_pool_size,
+			whitespace_policy, phrase_language, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		stage.ID, stage.Name, stage.ThemeID, stage.Difficulty, stage.IsActive, stage.CreatedAt, stage.UpdatedAt,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		stage.ID, stage.Slug, stage.Name, stage.ThemeID, stage.Difficulty, stage.IsActive,
+		localTime(stage.AvailableFrom), localTime(stage.AvailableUntil), stage.PhraseCharset, stage.PhrasePoolSize,
+		stage.WhitespacePolicy, nullString(stage.PhraseLanguage), stage.CreatedAt, stage.UpdatedAt,
 	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
 	return err
 }
 
 func (r *stageRepository) FindByID(ctx context.Context, stageID string) (*models.Stage, error) {
 	query := `
-		SELECT id, name, theme_id, difficulty, is_active, created_at, updated_at
+		SELECT ` + stageColumns + `
 		FROM stages WHERE id = $1
 	`
-	stage := &models.Stage{}
-	err := r.db.QueryRowContext(ctx, query, stageID).Scan(
-		&stage.ID, &stage.Name, &stage.ThemeID, &stage.Difficulty, &stage.IsActive, &stage.CreatedAt, &stage.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-	return stage, nil
+	return scanStage(conn(ctx, r.db).QueryRowContext(ctx, query, stageID))
+}
+
+func (r *stageRepository) FindBySlug(ctx context.Context, slug string) (*models.Stage, error) {
+	query := `
+		SELECT ` + stageColumns + `
+		FROM stages WHERE slug = $1
+	`
+	return scanStage(conn(ctx, r.db).QueryRowContext(ctx, query, slug))
 }
 
 func (r *stageRepository) FindAll(ctx context.Context) ([]*models.Stage, error) {
 	query := `
-		SELECT id, name, theme_id, difficulty, is_active, created_at, updated_at
+		SELECT ` + stageColumns + `
 		FROM stages
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var stages []*models.Stage
-	for rows.Next() {
-		stage := &models.Stage{}
-		err := rows.Scan(
-			&stage.ID, &stage.Name, &stage.ThemeID, &stage.Difficulty, &stage.IsActive, &stage.CreatedAt, &stage.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		stages = append(stages, stage)
-	}
-	return stages, nil
+	return r.findStages(ctx, query)
 }
 
 func (r *stageRepository) FindAllActive(ctx context.Context) ([]*models.Stage, error) {
 	query := `
-		SELECT id, name, theme_id, difficulty, is_active, created_at, updated_at
+		SELECT ` + stageColumns + `
 		FROM stages
 		WHERE is_active = true
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	return r.findStages(ctx, query)
+}
+
+func (r *stageRepository) findStages(ctx context.Context, query string, args ...interface{}) ([]*models.Stage, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,33 +89,75 @@ func (r *stageRepository) FindAllActive(ctx context.Context) ([]*models.Stage, e
 
 	var stages []*models.Stage
 	for rows.Next() {
-		stage := &models.Stage{}
-		err := rows.Scan(
-			&stage.ID, &stage.Name, &stage.ThemeID, &stage.Difficulty, &stage.IsActive, &stage.CreatedAt, &stage.UpdatedAt,
-		)
+		stage, err := scanStage(rows)
 		if err != nil {
 			return nil, err
 		}
 		stages = append(stages, stage)
 	}
-	return stages, nil
+	return stages, rows.Err()
 }
 
 func (r *stageRepository) Update(ctx context.Context, stage *models.Stage) error {
 	stage.UpdatedAt = time.Now()
 	query := `
 		UPDATE stages 
-		SET name = $2, theme_id = $3, difficulty = $4, is_active = $5, updated_at = $6
+		SET slug = $2, name = $3, theme_id = $4, difficulty = $5, is_active = $6,
+			available_from = $7, available_until = $8, phrase_charset = $9, phrase_pool_size = $10,
+			whitespace_policy = $11, phrase_language = $12, updated_at = $13
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		stage.ID, stage.Name, stage.ThemeID, stage.Difficulty, stage.IsActive, stage.UpdatedAt,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		stage.ID, stage.Slug, stage.Name, stage.ThemeID, stage.Difficulty, stage.IsActive,
+		localTime(stage.AvailableFrom), localTime(stage.AvailableUntil), stage.PhraseCharset, stage.PhrasePoolSize,
+		stage.WhitespacePolicy, nullString(stage.PhraseLanguage), stage.UpdatedAt,
 	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
 	return err
 }
 
 func (r *stageRepository) Delete(ctx context.Context, stageID string) error {
 	query := `DELETE FROM stages WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, stageID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, stageID)
 	return err
 }
+
+// localTime mengubah jadwal opsional ke parameter kolom TIMESTAMP, yang
+// menyimpan waktu lokal server (lihat windowStart)
+func localTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.Local(), Valid: true}
+}
+
+const stageColumns = `id, slug, name, theme_id, difficulty, is_active, created_at, updated_at,
+		COALESCE(published_version_id::text, ''), leaderboard_min_version, available_from, available_until, phrase_charset, phrase_pool_size,
+		whitespace_policy, COALESCE(phrase_language, '')`
+
+func scanStage(row rowScanner) (*models.Stage, error) {
+	stage := &models.Stage{}
+	var availableFrom, availableUntil sql.NullTime
+	err := row.Scan(
+		&stage.ID, &stage.Slug, &stage.Name, &stage.ThemeID, &stage.Difficulty, &stage.IsActive, &stage.CreatedAt, &stage.UpdatedAt,
+		&stage.PublishedVersionID, &stage.LeaderboardMinVersion, &availableFrom, &availableUntil, &stage.PhraseCharset, &stage.PhrasePoolSize,
+		&stage.WhitespacePolicy, &stage.PhraseLanguage,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if availableFrom.Valid {
+		t := localWallClock(availableFrom.Time)
+		stage.AvailableFrom = &t
+	}
+	if availableUntil.Valid {
+		t := localWallClock(availableUntil.Time)
+		stage.AvailableUntil = &t
+	}
+	return stage, nil
+}
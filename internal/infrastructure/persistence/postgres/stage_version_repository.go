@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+type stageVersionRepository struct {
+	db *sql.DB
+}
+
+func NewStageVersionRepository(db *sql.DB) repositories.StageVersionRepository {
+	return &stageVersionRepository{db: db}
+}
+
+const stageVersionColumns = `
+	v.id, v.stage_id, v.version_number, v.name, v.whitespace_policy, v.phrase_pool_size,
+	COALESCE(v.phrase_language, ''), v.leaderboard_reset,
+	COALESCE(v.published_by::text, ''), v.published_at,
+	(SELECT COUNT(*) FROM stage_version_phrases vp WHERE vp.version_id = v.id)
+`
+
+func scanStageVersion(row rowScanner) (*models.StageVersion, error) {
+	version := &models.StageVersion{}
+	err := row.Scan(
+		&version.ID, &version.StageID, &version.VersionNumber, &version.Name,
+		&version.WhitespacePolicy, &version.PhrasePoolSize, &version.PhraseLanguage, &version.LeaderboardReset,
+		&version.PublishedBy, &version.PublishedAt, &version.PhraseCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (r *stageVersionRepository) Publish(ctx context.Context, version *models.StageVersion) error {
+	if version.ID == "" {
+		version.ID = uuid.New().String()
+	}
+	version.PublishedAt = time.Now()
+	version.PhraseCount = len(version.Phrases)
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Lock stage supaya dua publish bersamaan tidak mendapat nomor
+		// versi yang sama
+		var stageID string
+		err := tx.QueryRowContext(ctx, `SELECT id FROM stages WHERE id = $1 FOR UPDATE`, version.StageID).Scan(&stageID)
+		if err != nil {
+			return err
+		}
+		var current int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COALESCE(MAX(version_number), 0) FROM stage_versions WHERE stage_id = $1
+		`, version.StageID).Scan(&current)
+		if err != nil {
+			return err
+		}
+		version.VersionNumber = current + 1
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stage_versions (id, stage_id, version_number, name, whitespace_policy, phrase_pool_size,
+				phrase_language, leaderboard_reset, published_by, published_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, version.ID, version.StageID, version.VersionNumber, version.Name, version.WhitespacePolicy, version.PhrasePoolSize,
+			nullString(version.PhraseLanguage), version.LeaderboardReset, nullString(version.PublishedBy), version.PublishedAt)
+		if err != nil {
+			return err
+		}
+
+		for _, phrase := range version.Phrases {
+			draftID := phrase.ID
+			phrase.ID = uuid.New().String()
+			phrase.StageID = version.StageID
+			phrase.CreatedAt = version.PublishedAt
+			phrase.UpdatedAt = version.PublishedAt
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO stage_version_phrases (id, version_id, phrase_id, text, sequence_number, base_multiplier)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, phrase.ID, version.ID, nullString(draftID), phrase.Text, phrase.SequenceNumber, phrase.BaseMultiplier)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !version.LeaderboardReset {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE stages SET published_version_id = $2 WHERE id = $1
+			`, version.StageID, version.ID)
+			return err
+		}
+
+		// Reset: score versi lama tetap tersimpan, tetapi tidak lagi masuk
+		// leaderboard stage. Materialisasi best score dikosongkan dan cache
+		// leaderboard di semua instance diinvalidasi lewat NOTIFY.
+		_, err = tx.ExecContext(ctx, `
+			UPDATE stages SET published_version_id = $2, leaderboard_min_version = $3 WHERE id = $1
+		`, version.StageID, version.ID, version.VersionNumber)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_stage_bests WHERE stage_id = $1`, version.StageID); err != nil {
+			return err
+		}
+		payload, err := json.Marshal(LeaderboardNotification{StageID: version.StageID})
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, LeaderboardChannel, string(payload))
+		return err
+	})
+}
+
+func (r *stageVersionRepository) FindByID(ctx context.Context, versionID string) (*models.StageVersion, error) {
+	query := `SELECT ` + stageVersionColumns + ` FROM stage_versions v WHERE v.id = $1`
+	return r.findWithPhrases(ctx, query, versionID)
+}
+
+func (r *stageVersionRepository) FindPublished(ctx context.Context, stageID string) (*models.StageVersion, error) {
+	query := `
+		SELECT ` + stageVersionColumns + `
+		FROM stages s
+		JOIN stage_versions v ON v.id = s.published_version_id
+		WHERE s.id = $1
+	`
+	return r.findWithPhrases(ctx, query, stageID)
+}
+
+func (r *stageVersionRepository) FindByStageID(ctx context.Context, stageID string) ([]*models.StageVersion, error) {
+	query := `
+		SELECT ` + stageVersionColumns + `
+		FROM stage_versions v
+		WHERE v.stage_id = $1
+		ORDER BY v.version_number DESC
+	`
+	return r.findMany(ctx, query, stageID)
+}
+
+func (r *stageVersionRepository) FindAllPublished(ctx context.Context) ([]*models.StageVersion, error) {
+	query := `
+		SELECT ` + stageVersionColumns + `
+		FROM stages s
+		JOIN stage_versions v ON v.id = s.published_version_id
+	`
+	return r.findMany(ctx, query)
+}
+
+func (r *stageVersionRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*models.StageVersion, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*models.StageVersion
+	for rows.Next() {
+		version, err := scanStageVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (r *stageVersionRepository) findWithPhrases(ctx context.Context, query string, args ...interface{}) (*models.StageVersion, error) {
+	db := conn(ctx, r.db)
+	version, err := scanStageVersion(db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ID phrase versi adalah id stage_version_phrases, sehingga tetap unik
+	// walaupun phrase draft asalnya sudah dihapus
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, text, sequence_number, base_multiplier
+		FROM stage_version_phrases
+		WHERE version_id = $1
+		ORDER BY sequence_number ASC
+	`, version.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		phrase := &models.Phrase{StageID: version.StageID, CreatedAt: version.PublishedAt, UpdatedAt: version.PublishedAt}
+		if err := rows.Scan(&phrase.ID, &phrase.Text, &phrase.SequenceNumber, &phrase.BaseMultiplier); err != nil {
+			return nil, err
+		}
+		version.Phrases = append(version.Phrases, phrase)
+	}
+	return version, rows.Err()
+}
@@ -3,9 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"uwika_quick_typer_game/internal/domain/models"
 	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/google/uuid"
 )
 
 type themeRepository struct {
@@ -16,13 +19,34 @@ func NewThemeRepository(db *sql.DB) repositories.ThemeRepository {
 	return &themeRepository{db: db}
 }
 
+func (r *themeRepository) Create(ctx context.Context, theme *models.Theme) error {
+	if theme.ID == "" {
+		theme.ID = uuid.New().String()
+	}
+	theme.CreatedAt = time.Now()
+	theme.UpdatedAt = theme.CreatedAt
+
+	query := `
+		INSERT INTO themes (id, name, description, color, icon_key, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		theme.ID, theme.Name, nullString(theme.Description), nullString(theme.Color), nullString(theme.IconKey),
+		theme.SortOrder, theme.CreatedAt, theme.UpdatedAt,
+	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
+	return err
+}
+
 func (r *themeRepository) FindAll(ctx context.Context) ([]*models.Theme, error) {
 	query := `
-		SELECT id, name, description, created_at
+		SELECT ` + themeColumns + `
 		FROM themes
-		ORDER BY name ASC
+		ORDER BY sort_order ASC, name ASC
 	`
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -30,30 +54,68 @@ func (r *themeRepository) FindAll(ctx context.Context) ([]*models.Theme, error)
 
 	var themes []*models.Theme
 	for rows.Next() {
-		theme := &models.Theme{}
-		var description sql.NullString
-		err := rows.Scan(&theme.ID, &theme.Name, &description, &theme.CreatedAt)
+		theme, err := scanTheme(rows)
 		if err != nil {
 			return nil, err
 		}
-		if description.Valid {
-			theme.Description = description.String
-		}
 		themes = append(themes, theme)
 	}
-	return themes, nil
+	return themes, rows.Err()
 }
 
 func (r *themeRepository) FindByID(ctx context.Context, themeID string) (*models.Theme, error) {
 	query := `
-		SELECT id, name, description, created_at
+		SELECT ` + themeColumns + `
+		FROM themes
+		WHERE id = $1
+	`
+	return scanTheme(conn(ctx, r.db).QueryRowContext(ctx, query, themeID))
+}
+
+func (r *themeRepository) FindByName(ctx context.Context, name string) (*models.Theme, error) {
+	query := `
+		SELECT ` + themeColumns + `
 		FROM themes
+		WHERE name = $1
+	`
+	return scanTheme(conn(ctx, r.db).QueryRowContext(ctx, query, name))
+}
+
+func (r *themeRepository) Update(ctx context.Context, theme *models.Theme) error {
+	theme.UpdatedAt = time.Now()
+	query := `
+		UPDATE themes
+		SET name = $2, description = $3, color = $4, icon_key = $5, sort_order = $6, updated_at = $7
 		WHERE id = $1
 	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		theme.ID, theme.Name, nullString(theme.Description), nullString(theme.Color), nullString(theme.IconKey),
+		theme.SortOrder, theme.UpdatedAt,
+	)
+	if isPQError(err, pqUniqueViolation) {
+		return repositories.ErrDuplicate
+	}
+	return err
+}
+
+// Delete ditolak database (ON DELETE RESTRICT) selama masih ada stage
+// dengan theme ini
+func (r *themeRepository) Delete(ctx context.Context, themeID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM themes WHERE id = $1`, themeID)
+	if isPQError(err, pqForeignKeyViolation) {
+		return repositories.ErrReferenced
+	}
+	return err
+}
+
+const themeColumns = `id, name, COALESCE(description, ''), COALESCE(color, ''), COALESCE(icon_key, ''),
+		sort_order, created_at, updated_at`
+
+func scanTheme(row rowScanner) (*models.Theme, error) {
 	theme := &models.Theme{}
-	var description sql.NullString
-	err := r.db.QueryRowContext(ctx, query, themeID).Scan(
-		&theme.ID, &theme.Name, &description, &theme.CreatedAt,
+	err := row.Scan(
+		&theme.ID, &theme.Name, &theme.Description, &theme.Color, &theme.IconKey,
+		&theme.SortOrder, &theme.CreatedAt, &theme.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -61,9 +123,5 @@ func (r *themeRepository) FindByID(ctx context.Context, themeID string) (*models
 	if err != nil {
 		return nil, err
 	}
-	if description.Valid {
-		theme.Description = description.String
-	}
 	return theme, nil
 }
-
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+
+	"github.com/lib/pq"
+)
+
+type translationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) repositories.TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) FindStageTranslations(ctx context.Context, stageID string) ([]*models.StageTranslation, error) {
+	query := `
+		SELECT stage_id, locale, name, created_at, updated_at
+		FROM stage_translations
+		WHERE stage_id = $1
+		ORDER BY locale ASC
+	`
+	return r.findStageTranslations(ctx, query, stageID)
+}
+
+func (r *translationRepository) FindStageTranslationsByLocales(ctx context.Context, locales []string) ([]*models.StageTranslation, error) {
+	query := `
+		SELECT stage_id, locale, name, created_at, updated_at
+		FROM stage_translations
+		WHERE locale = ANY($1)
+	`
+	return r.findStageTranslations(ctx, query, pq.Array(locales))
+}
+
+func (r *translationRepository) findStageTranslations(ctx context.Context, query string, args ...interface{}) ([]*models.StageTranslation, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*models.StageTranslation
+	for rows.Next() {
+		translation := &models.StageTranslation{}
+		if err := rows.Scan(
+			&translation.StageID, &translation.Locale, &translation.Name, &translation.CreatedAt, &translation.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+// UpsertStageTranslation - CreatedAt hanya diisi untuk terjemahan baru
+func (r *translationRepository) UpsertStageTranslation(ctx context.Context, translation *models.StageTranslation) error {
+	translation.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO stage_translations (stage_id, locale, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (stage_id, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		translation.StageID, translation.Locale, translation.Name, translation.UpdatedAt,
+	)
+	return err
+}
+
+func (r *translationRepository) DeleteStageTranslation(ctx context.Context, stageID, locale string) (bool, error) {
+	query := `DELETE FROM stage_translations WHERE stage_id = $1 AND locale = $2`
+	return r.delete(ctx, query, stageID, locale)
+}
+
+func (r *translationRepository) FindThemeTranslations(ctx context.Context, themeID string) ([]*models.ThemeTranslation, error) {
+	query := `
+		SELECT theme_id, locale, name, COALESCE(description, ''), created_at, updated_at
+		FROM theme_translations
+		WHERE theme_id = $1
+		ORDER BY locale ASC
+	`
+	return r.findThemeTranslations(ctx, query, themeID)
+}
+
+func (r *translationRepository) FindThemeTranslationsByLocales(ctx context.Context, locales []string) ([]*models.ThemeTranslation, error) {
+	query := `
+		SELECT theme_id, locale, name, COALESCE(description, ''), created_at, updated_at
+		FROM theme_translations
+		WHERE locale = ANY($1)
+	`
+	return r.findThemeTranslations(ctx, query, pq.Array(locales))
+}
+
+func (r *translationRepository) findThemeTranslations(ctx context.Context, query string, args ...interface{}) ([]*models.ThemeTranslation, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []*models.ThemeTranslation
+	for rows.Next() {
+		translation := &models.ThemeTranslation{}
+		if err := rows.Scan(
+			&translation.ThemeID, &translation.Locale, &translation.Name, &translation.Description,
+			&translation.CreatedAt, &translation.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+func (r *translationRepository) UpsertThemeTranslation(ctx context.Context, translation *models.ThemeTranslation) error {
+	translation.UpdatedAt = time.Now()
+	query := `
+		INSERT INTO theme_translations (theme_id, locale, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (theme_id, locale) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		translation.ThemeID, translation.Locale, translation.Name, nullString(translation.Description), translation.UpdatedAt,
+	)
+	return err
+}
+
+func (r *translationRepository) DeleteThemeTranslation(ctx context.Context, themeID, locale string) (bool, error) {
+	query := `DELETE FROM theme_translations WHERE theme_id = $1 AND locale = $2`
+	return r.delete(ctx, query, themeID, locale)
+}
+
+func (r *translationRepository) delete(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"uwika_quick_typer_game/internal/domain/models"
+	"uwika_quick_typer_game/internal/domain/repositories"
+)
+
+type userProgressRepository struct {
+	db *sql.DB
+}
+
+func NewUserProgressRepository(db *sql.DB) repositories.UserProgressRepository {
+	return &userProgressRepository{db: db}
+}
+
+const userProgressColumns = `user_id, xp, level, current_streak, longest_streak, last_played_on, updated_at`
+
+func (r *userProgressRepository) FindByUserID(ctx context.Context, userID string) (*models.UserProgress, error) {
+	query := `
+		SELECT ` + userProgressColumns + `
+		FROM user_progress WHERE user_id = $1
+	`
+	return scanUserProgress(conn(ctx, r.db).QueryRowContext(ctx, query, userID))
+}
+
+func (r *userProgressRepository) FindByUserIDForUpdate(ctx context.Context, userID string) (*models.UserProgress, error) {
+	db := conn(ctx, r.db)
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO user_progress (user_id, updated_at)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + userProgressColumns + `
+		FROM user_progress WHERE user_id = $1
+		FOR UPDATE
+	`
+	return scanUserProgress(db.QueryRowContext(ctx, query, userID))
+}
+
+func (r *userProgressRepository) Save(ctx context.Context, progress *models.UserProgress) error {
+	progress.UpdatedAt = time.Now()
+
+	var lastPlayedOn sql.NullString
+	if !progress.LastPlayedOn.IsZero() {
+		lastPlayedOn = sql.NullString{String: progress.LastPlayedOn.Format("2006-01-02"), Valid: true}
+	}
+
+	query := `
+		INSERT INTO user_progress (user_id, xp, level, current_streak, longest_streak, last_played_on, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6::date, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET xp = EXCLUDED.xp,
+			level = EXCLUDED.level,
+			current_streak = EXCLUDED.current_streak,
+			longest_streak = EXCLUDED.longest_streak,
+			last_played_on = EXCLUDED.last_played_on,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		progress.UserID, progress.XP, progress.Level, progress.CurrentStreak, progress.LongestStreak,
+		lastPlayedOn, progress.UpdatedAt,
+	)
+	return err
+}
+
+func scanUserProgress(row rowScanner) (*models.UserProgress, error) {
+	progress := &models.UserProgress{}
+	var lastPlayedOn sql.NullTime
+	err := row.Scan(
+		&progress.UserID, &progress.XP, &progress.Level, &progress.CurrentStreak, &progress.LongestStreak,
+		&lastPlayedOn, &progress.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastPlayedOn.Valid {
+		day := lastPlayedOn.Time
+		progress.LastPlayedOn = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	progress.UpdatedAt = localWallClock(progress.UpdatedAt)
+	return progress, nil
+}
@@ -23,71 +23,76 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	if user.ID == "" {
 		user.ID = uuid.New().String()
 	}
+	if user.ProfileVisibility == "" {
+		user.ProfileVisibility = models.ProfileVisibilityPublic
+	}
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO users (id, username, password_hash, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (
+			id, username, password_hash, role, display_name, profile_visibility, leaderboard_anonymous, timezone, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		user.ID, user.Username, user.PasswordHash, user.Role, user.CreatedAt, user.UpdatedAt,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.ID, user.Username, user.PasswordHash, user.Role, nullString(user.DisplayName),
+		user.ProfileVisibility, user.LeaderboardAnonymous, nullString(user.Timezone), user.CreatedAt, user.UpdatedAt,
 	)
 	return err
 }
 
 func (r *userRepository) FindByID(ctx context.Context, userID string) (*models.User, error) {
 	query := `
-		SELECT id, username, password_hash, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users WHERE id = $1
 	`
-	user := &models.User{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	return scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, userID))
 }
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, username, password_hash, role, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users WHERE username = $1
 	`
-	user := &models.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	return scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, username))
 }
 
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	user.UpdatedAt = time.Now()
 	query := `
 		UPDATE users 
-		SET username = $2, password_hash = $3, role = $4, updated_at = $5
+		SET username = $2, password_hash = $3, role = $4, display_name = $5,
+			profile_visibility = $6, leaderboard_anonymous = $7, timezone = $8, updated_at = $9
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		user.ID, user.Username, user.PasswordHash, user.Role, user.UpdatedAt,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.ID, user.Username, user.PasswordHash, user.Role, nullString(user.DisplayName),
+		user.ProfileVisibility, user.LeaderboardAnonymous, nullString(user.Timezone), user.UpdatedAt,
 	)
 	return err
 }
 
 func (r *userRepository) Delete(ctx context.Context, userID string) error {
 	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
 	return err
 }
 
+const userColumns = `id, username, password_hash, role, COALESCE(display_name, ''), profile_visibility,
+		leaderboard_anonymous, COALESCE(timezone, ''), created_at, updated_at`
+
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(
+		&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.DisplayName, &user.ProfileVisibility,
+		&user.LeaderboardAnonymous, &user.Timezone, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}